@@ -11,7 +11,7 @@ import (
 	"mime/quotedprintable"
 	"strings"
 
-	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/transform"
 
 	"github.com/rorycl/base64toraw"
@@ -26,11 +26,17 @@ import (
 //	len(word) < 8 || !strings.HasPrefix(word, "=?") ||
 //	!strings.HasSuffix(word, "?=") || strings.Count(word, "?") != 4
 func DecodeHeader(s string) (string, error) {
+	return DecodeHeaderFallback(s, nil)
+}
+
+// DecodeHeaderFallback decodes a string like DecodeHeader, using the
+// fallback encoding for encoded-words declaring a charset which cannot
+// be found. If fallback is nil such words cause an error.
+func DecodeHeaderFallback(s string, fallback encoding.Encoding) (string, error) {
 	charsetReader := func(label string, input io.Reader) (io.Reader, error) {
-		enc, _ := charset.Lookup(label)
+		enc, _ := email.LookupCharset(label)
 		if enc == nil {
-			normalizedLabel := strings.ReplaceAll(label, "windows-", "cp")
-			enc, _ = charset.Lookup(normalizedLabel)
+			enc = fallback
 		}
 		if enc == nil {
 			return nil, fmt.Errorf("encoding lookup failed %s", label)
@@ -53,12 +59,18 @@ func DecodeHeader(s string) (string, error) {
 // Note that the base64 decoder "base64toraw.NewBase64ToRaw" decodes all
 // base64 content to data that is base64.RawStdEncoding encoded, i.e.
 // without "=" padding.
+//
+// Multipart content is returned as-is, since a transfer-encoding is not
+// permitted for multipart entities.
 func DecodeContent(content io.Reader, ci *email.ContentInfo) io.Reader {
 	var contentReader io.Reader
-	switch ci.TransferEncoding {
-	case "base64":
+	switch {
+	case strings.HasPrefix(ci.Type, "multipart/"):
+		// multipart bodies are never transfer-decoded
+		return content
+	case ci.TransferEncoding == "base64":
 		contentReader = base64.NewDecoder(base64.RawStdEncoding, base64toraw.NewBase64ToRaw(content))
-	case "quoted-printable":
+	case ci.TransferEncoding == "quoted-printable":
 		contentReader = quotedprintable.NewReader(content)
 	default:
 		contentReader = content
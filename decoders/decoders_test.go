@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/rorycl/letters/email"
+	"golang.org/x/text/encoding/charmap"
 )
 
 func TestDecodeHeader(t *testing.T) {
@@ -28,6 +29,10 @@ func TestDecodeHeader(t *testing.T) {
 			header: `=?utf-8?Q?Andreas_Birkeb=C3=A6k?=`,
 			want:   `Andreas Birkebæk`,
 		},
+		{
+			header: `=?iso_8859-8-i?Q?=F9=EC=E5=ED?=`,
+			want:   `שלום`,
+		},
 	}
 
 	for i, tt := range tests {
@@ -43,6 +48,20 @@ func TestDecodeHeader(t *testing.T) {
 	}
 }
 
+func TestDecodeHeaderFallback(t *testing.T) {
+	header := `=?x-no-such-charset?Q?caf=E9?=`
+	if _, err := DecodeHeader(header); err == nil {
+		t.Error("expected error decoding unknown charset")
+	}
+	got, err := DecodeHeaderFallback(header, charmap.Windows1252)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got, "café"; got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
+
 func TestDecodeContent(t *testing.T) {
 	tests := []struct {
 		name           string
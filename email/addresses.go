@@ -0,0 +1,112 @@
+package email
+
+import (
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Address helpers provide derived information from the address fields
+// of Headers.
+
+// RecipientCount returns the number of unique recipient addresses
+// across the To, Cc and Bcc fields. Addresses are compared
+// case-insensitively on the full address, so that the same address in
+// both To and Cc is counted once. Display names are disregarded.
+func (h *Headers) RecipientCount() int {
+	seen := map[string]bool{}
+	for _, list := range [][]*mail.Address{h.addressList("To"), h.addressList("Cc"), h.addressList("Bcc")} {
+		for _, a := range list {
+			if a == nil || a.Address == "" {
+				continue
+			}
+			seen[strings.ToLower(a.Address)] = true
+		}
+	}
+	return len(seen)
+}
+
+// ReplyTarget returns the addresses to which a reply should be sent.
+// Following RFC 5322 section 3.6.2, these are the Reply-To addresses if
+// present, otherwise the From addresses.
+func (h *Headers) ReplyTarget() []*mail.Address {
+	if replyTo := h.addressList("Reply-To"); len(replyTo) > 0 {
+		return replyTo
+	}
+	return h.addressList("From")
+}
+
+// ReplyAllRecipients returns the addresses to which a "reply all"
+// should be sent: the ReplyTarget addresses followed by the To and Cc
+// addresses. Addresses are de-duplicated case-insensitively, keeping the
+// first occurrence, and any address matching self (also compared
+// case-insensitively) is excluded.
+func (h *Headers) ReplyAllRecipients(self string) []*mail.Address {
+	self = strings.ToLower(strings.TrimSpace(self))
+	seen := map[string]bool{}
+	recipients := []*mail.Address{}
+	for _, list := range [][]*mail.Address{h.ReplyTarget(), h.addressList("To"), h.addressList("Cc")} {
+		for _, a := range list {
+			if a == nil || a.Address == "" {
+				continue
+			}
+			addr := strings.ToLower(a.Address)
+			if addr == self || seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			recipients = append(recipients, a)
+		}
+	}
+	return recipients
+}
+
+// NormalizeAddress returns the address lowercased, with an
+// internationalised domain name converted to its ASCII (punycode) form,
+// so that addresses may be compared consistently. For example
+// "Alice@Bücher.Example" returns "alice@xn--bcher-kva.example". The
+// domain is only lowercased if it cannot be converted.
+func NormalizeAddress(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	i := strings.LastIndex(address, "@")
+	if i < 0 {
+		return address
+	}
+	local, domain := address[:i], address[i+1:]
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = ascii
+	}
+	return local + "@" + domain
+}
+
+// CanonicalSender returns the single identity of the sender of the
+// email, with its address normalized by NormalizeAddress, or nil if no
+// sender can be determined. The precedence is:
+//
+//  1. the first From address
+//  2. the Sender address
+//  3. the Return-Path address, excluding the null "<>" bounce path
+//
+// The display name of the chosen address is retained.
+func (h *Headers) CanonicalSender() *mail.Address {
+	var sender *mail.Address
+	from := h.addressList("From")
+	switch {
+	case len(from) > 0 && from[0] != nil && from[0].Address != "":
+		sender = from[0]
+	case h.Sender != nil && h.Sender.Address != "":
+		sender = h.Sender
+	default:
+		rp := strings.TrimSpace(h.extraHeader("Return-Path"))
+		if strings.Trim(rp, "<> ") == "" {
+			return nil
+		}
+		a, err := mail.ParseAddress(rp)
+		if err != nil {
+			return nil
+		}
+		sender = a
+	}
+	return &mail.Address{Name: sender.Name, Address: NormalizeAddress(sender.Address)}
+}
@@ -0,0 +1,148 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecipientCount(t *testing.T) {
+	tests := []struct {
+		headers Headers
+		count   int
+	}{
+		{
+			headers: Headers{},
+			count:   0,
+		},
+		{
+			headers: Headers{
+				To: []*mail.Address{{Address: "bob@example.com"}, {Address: "carol@example.com"}},
+			},
+			count: 2,
+		},
+		{
+			headers: Headers{
+				To:  []*mail.Address{{Name: "Bob", Address: "bob@example.com"}},
+				Cc:  []*mail.Address{{Name: "Robert", Address: "Bob@Example.com"}, {Address: "dan@example.com"}},
+				Bcc: []*mail.Address{{Address: "eve@example.com"}, {Address: "dan@example.com"}},
+			},
+			count: 3,
+		},
+		{
+			headers: Headers{
+				From: []*mail.Address{{Address: "alice@example.com"}},
+				To:   []*mail.Address{nil, {Address: ""}},
+			},
+			count: 0,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := tt.headers.RecipientCount(), tt.count; got != want {
+				t.Errorf("got %d want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestReplyTarget(t *testing.T) {
+	alice := &mail.Address{Name: "Alice", Address: "alice@example.com"}
+	list := &mail.Address{Name: "List", Address: "list@example.com"}
+	tests := []struct {
+		headers Headers
+		want    []*mail.Address
+	}{
+		{Headers{From: []*mail.Address{alice}}, []*mail.Address{alice}},
+		{Headers{From: []*mail.Address{alice}, ReplyTo: []*mail.Address{list}}, []*mail.Address{list}},
+		{Headers{}, nil},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.headers.ReplyTarget()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReplyAllRecipients(t *testing.T) {
+	h := Headers{
+		From:    []*mail.Address{{Name: "Alice", Address: "alice@example.com"}},
+		ReplyTo: []*mail.Address{{Name: "Alice Home", Address: "alice@example.net"}},
+		To: []*mail.Address{
+			{Name: "Me", Address: "me@example.com"},
+			{Name: "Bob", Address: "bob@example.com"},
+		},
+		Cc: []*mail.Address{
+			{Name: "Bob Again", Address: "BOB@example.com"},
+			{Name: "Alice Home Again", Address: "alice@example.net"},
+			{Name: "Carol", Address: "carol@example.com"},
+		},
+	}
+	got := []string{}
+	for _, a := range h.ReplyAllRecipients("Me@Example.com") {
+		got = append(got, a.Address)
+	}
+	want := []string{"alice@example.net", "bob@example.com", "carol@example.com"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"Alice@Example.COM", "alice@example.com"},
+		{" alice@bücher.example ", "alice@xn--bcher-kva.example"},
+		{"Alice@Bücher.Example", "alice@xn--bcher-kva.example"},
+		{"no-domain", "no-domain"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := NormalizeAddress(tt.address), tt.want; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCanonicalSender(t *testing.T) {
+	from := []*mail.Address{{Name: "Alice", Address: "Alice@Bücher.Example"}, {Address: "bob@example.com"}}
+	sender := &mail.Address{Name: "List", Address: "List@Example.com"}
+	returnPath := map[string][]string{"Return-Path": {"<Bounces@Example.net>"}}
+
+	tests := []struct {
+		headers Headers
+		want    *mail.Address
+	}{
+		{
+			Headers{From: from, Sender: sender, ExtraHeaders: returnPath},
+			&mail.Address{Name: "Alice", Address: "alice@xn--bcher-kva.example"},
+		},
+		{
+			Headers{Sender: sender, ExtraHeaders: returnPath},
+			&mail.Address{Name: "List", Address: "list@example.com"},
+		},
+		{
+			Headers{From: []*mail.Address{{Name: "Empty"}}, ExtraHeaders: returnPath},
+			&mail.Address{Address: "bounces@example.net"},
+		},
+		{
+			Headers{ExtraHeaders: map[string][]string{"Return-Path": {"<>"}}},
+			nil,
+		},
+		{Headers{}, nil},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.headers.CanonicalSender()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package email
+
+import "time"
+
+// DKIMSignature is a DKIM-Signature header (RFC 6376 section 3.5)
+// parsed into its tags, such as:
+//
+//	DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed;
+//		d=example.com; s=selector1; h=from:to:subject:date;
+//		bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=; b=AuTJ...
+//
+// The signature is only parsed, not verified. Tags which are missing or
+// cannot be parsed are left empty.
+type DKIMSignature struct {
+	// Raw is the unfolded header value.
+	Raw string
+	// Tags holds the value of every tag keyed by tag name, such as
+	// "d", with folding whitespace removed.
+	Tags map[string]string
+	// Version (v=), Algorithm (a=), Canonicalization (c=), Domain
+	// (d=), Selector (s=) and Identity (i=) are the values of the
+	// respective tags.
+	Version          string
+	Algorithm        string
+	Canonicalization string
+	Domain           string
+	Selector         string
+	Identity         string
+	// Headers are the names of the signed header fields (h=), in
+	// signing order.
+	Headers []string
+	// BodyHash (bh=) and Signature (b=) are the base64 encoded body
+	// hash and signature.
+	BodyHash  string
+	Signature string
+	// Timestamp (t=) and Expiration (x=) are the signing and expiry
+	// times of the signature, or the zero time if absent or invalid.
+	Timestamp  time.Time
+	Expiration time.Time
+}
+
+// AuthResult is the result of an authentication method, such as
+// "dkim=pass header.d=example.com", reported in an
+// Authentication-Results header (RFC 8601) by the server identified by
+// AuthServID, such as:
+//
+//	Authentication-Results: mx.example.net;
+//		dkim=pass header.d=example.com header.s=selector1;
+//		spf=fail smtp.mailfrom=alice@example.com
+type AuthResult struct {
+	// AuthServID is the identifier of the server which performed the
+	// authentication, such as "mx.example.net".
+	AuthServID string
+	// Method is the lowercased authentication method, such as "dkim",
+	// "spf", "dmarc" or "arc", and Result its lowercased result, such
+	// as "pass", "fail" or "none".
+	Method string
+	Result string
+	// Reason is the value of the optional reason property.
+	Reason string
+	// Properties holds the other properties of the result keyed by
+	// their "ptype.property" name, such as "header.d" or
+	// "smtp.mailfrom".
+	Properties map[string]string
+	// Instance is the ARC instance (i=) of a result reported by an
+	// ARC-Authentication-Results header (RFC 8617), numbering each
+	// server handling the message from 1, or 0 for a result reported
+	// by an Authentication-Results header.
+	Instance int
+}
@@ -0,0 +1,54 @@
+package email
+
+import "time"
+
+// Calendar summarises an iCalendar (RFC 5545) object sent in a
+// text/calendar part, such as a meeting invitation or a reply to one.
+// Only the main properties of its events are extracted; the object
+// itself is retained as a file with a FileType of "calendar".
+type Calendar struct {
+	// Method is the uppercased iTIP method of the object, such as
+	// "REQUEST", "REPLY" or "CANCEL", or empty if it is not a
+	// scheduling message.
+	Method string
+	// Events are the VEVENT components of the object.
+	Events []CalendarEvent
+}
+
+// CalendarEvent holds the main properties of an iCalendar event. Text
+// values are unescaped.
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	// Status is the uppercased status, such as "CONFIRMED" or
+	// "CANCELLED".
+	Status string
+	// Sequence is the revision sequence number of the event.
+	Sequence int
+	// Start and End are the times of the event, or zero if absent or
+	// invalid. Times with a TZID are interpreted in that time zone if
+	// it is known, and floating times in UTC.
+	Start time.Time
+	End   time.Time
+	// AllDay reports if the start is a date rather than a time.
+	AllDay    bool
+	Organizer *CalendarAttendee
+	Attendees []CalendarAttendee
+}
+
+// CalendarAttendee is the organizer or an attendee of an event.
+type CalendarAttendee struct {
+	// Name is the common name (CN) of the attendee, if given.
+	Name string
+	// Address is the calendar address of the attendee, stripped of any
+	// "mailto:" prefix.
+	Address string
+	// Role is the uppercased participation role, such as
+	// "REQ-PARTICIPANT".
+	Role string
+	// PartStat is the uppercased participation status, such as
+	// "NEEDS-ACTION" or "ACCEPTED".
+	PartStat string
+}
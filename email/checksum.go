@@ -0,0 +1,29 @@
+package email
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// FileChecksums returns a map of the name of each file of the email to
+// the hex encoded SHA-256 checksum of its decoded content, such as for
+// recognising previously scanned attachments. The checksum computed
+// while parsing with the WithFileChecksums option is used if present,
+// otherwise it is computed from the file Data, in which case files
+// without data are omitted. Where files share a name, the checksum of
+// the last is used.
+func (e *Email) FileChecksums() map[string]string {
+	checksums := map[string]string{}
+	for _, f := range e.Files {
+		switch {
+		case f == nil:
+			continue
+		case f.SHA256 != "":
+			checksums[f.Name] = f.SHA256
+		case f.Data != nil:
+			sum := sha256.Sum256(f.Data)
+			checksums[f.Name] = hex.EncodeToString(sum[:])
+		}
+	}
+	return checksums
+}
@@ -0,0 +1,26 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileChecksums(t *testing.T) {
+	e := &Email{
+		Files: []*File{
+			{Name: "hello.txt", Data: []byte("hello")},
+			{Name: "empty.txt", Data: []byte{}},
+			{Name: "streamed.bin", SHA256: "0123abcd"},
+			{Name: "unread.bin"},
+		},
+	}
+	want := map[string]string{
+		"hello.txt":    "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"empty.txt":    "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		"streamed.bin": "0123abcd",
+	}
+	if diff := cmp.Diff(want, e.FileChecksums()); diff != "" {
+		t.Error(diff)
+	}
+}
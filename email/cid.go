@@ -0,0 +1,91 @@
+package email
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// normaliseContentID strips a Content-ID of any "cid:" prefix, angle
+// brackets and surrounding whitespace.
+func normaliseContentID(cid string) string {
+	cid = strings.TrimSpace(cid)
+	if len(cid) >= len("cid:") && strings.EqualFold(cid[:len("cid:")], "cid:") {
+		cid = cid[len("cid:"):]
+		if decoded, err := url.PathUnescape(cid); err == nil {
+			cid = decoded
+		}
+	}
+	return strings.Trim(cid, "<> \t")
+}
+
+// InlineByContentID returns the file of the email with the Content-ID
+// cid, or nil if there is none. The cid may be given with or without
+// angle brackets, or as a percent-encoded "cid:" URL. ContentIDFiles is
+// consulted if set, otherwise the Files are searched, returning the
+// last match as ContentIDFiles does.
+func (e *Email) InlineByContentID(cid string) *File {
+	cid = normaliseContentID(cid)
+	if cid == "" {
+		return nil
+	}
+	if e.ContentIDFiles != nil {
+		return e.ContentIDFiles[cid]
+	}
+	var found *File
+	for _, f := range e.Files {
+		if f != nil && f.ContentInfo != nil && normaliseContentID(f.ContentInfo.ID) == cid {
+			found = f
+		}
+	}
+	return found
+}
+
+// dataURI returns the file's content as a base64 encoded data URI, or
+// an empty string if the content of a lazily parsed file cannot be
+// read.
+func (f *File) dataURI() string {
+	data := f.Data
+	if data == nil && f.Lazy != nil {
+		var err error
+		if data, err = f.Lazy.Bytes(); err != nil {
+			return ""
+		}
+	}
+	mediaType := "application/octet-stream"
+	if f.ContentInfo != nil && f.ContentInfo.Type != "" {
+		mediaType = strings.ToLower(f.ContentInfo.Type)
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// ResolveCIDs returns html, such as the HTML body of the email, with
+// each "cid:" URL (RFC 2392) in a URL attribute, such as the src of an
+// img element, or a CSS url() referring to a file of the email, as
+// found by InlineByContentID, replaced by the URL returned by urlFunc
+// for the file. If urlFunc is nil the file is embedded as a base64
+// data URI, so that inline images can be rendered without their files,
+// such as with:
+//
+//	html := e.ResolveCIDs(e.HTML, nil)
+//
+// URLs referring to an unknown Content-ID, or for which urlFunc returns
+// an empty string, are left unaltered. The email is not altered.
+func (e *Email) ResolveCIDs(html string, urlFunc func(*File) string) string {
+	if urlFunc == nil {
+		urlFunc = (*File).dataURI
+	}
+	return rewriteHTMLURLs(html, func(u string) (string, bool) {
+		if len(u) < len("cid:") || !strings.EqualFold(u[:len("cid:")], "cid:") {
+			return "", false
+		}
+		f := e.InlineByContentID(u)
+		if f == nil {
+			return "", false
+		}
+		if u := urlFunc(f); u != "" {
+			return u, true
+		}
+		return "", false
+	})
+}
@@ -0,0 +1,87 @@
+package email
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+func TestInlineByContentID(t *testing.T) {
+	logo := &File{Name: "logo.gif", ContentInfo: &ContentInfo{ID: "<logo@example.com>"}}
+	chart := &File{Name: "chart png", ContentInfo: &ContentInfo{ID: "chart 1@example.com"}}
+	e := &Email{Files: []*File{{Name: "none"}, logo, chart}}
+
+	tests := []struct {
+		cid  string
+		want *File
+	}{
+		{"logo@example.com", logo},
+		{"<logo@example.com>", logo},
+		{"cid:logo@example.com", logo},
+		{"CID:logo%40example.com", logo},
+		{"cid:chart%201@example.com", chart},
+		{"other@example.com", nil},
+		{"", nil},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := e.InlineByContentID(tt.cid), tt.want; got != want {
+				t.Errorf("got %v want %v", got, want)
+			}
+		})
+	}
+
+	// ContentIDFiles is consulted if set
+	e.ContentIDFiles = map[string]*File{"logo@example.com": chart}
+	if got, want := e.InlineByContentID("cid:logo@example.com"), chart; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestResolveCIDs(t *testing.T) {
+	gif, err := base64.StdEncoding.DecodeString(testGIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Email{
+		HTML: `<img src="cid:logo@example.com"><img src='cid:missing'>` +
+			`<div style="background: url(cid:logo@example.com)"></div>` +
+			`<p>see cid:logo@example.com</p>`,
+		Files: []*File{
+			{Name: "logo.gif", Data: gif, ContentInfo: &ContentInfo{Type: "image/GIF", ID: "<logo@example.com>"}},
+		},
+	}
+	original := e.HTML
+
+	tests := []struct {
+		urlFunc func(*File) string
+		want    string
+	}{
+		{
+			nil,
+			`<img src="data:image/gif;base64,` + testGIF + `"><img src='cid:missing'>` +
+				`<div style="background: url(data:image/gif;base64,` + testGIF + `)"></div>` +
+				`<p>see cid:logo@example.com</p>`,
+		},
+		{
+			func(f *File) string { return "/files/" + f.Name },
+			`<img src="/files/logo.gif"><img src='cid:missing'>` +
+				`<div style="background: url(/files/logo.gif)"></div>` +
+				`<p>see cid:logo@example.com</p>`,
+		},
+		{
+			func(f *File) string { return "" },
+			original,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := e.ResolveCIDs(e.HTML, tt.urlFunc), tt.want; got != want {
+				t.Errorf("got %s want %s", got, want)
+			}
+			if got, want := e.HTML, original; got != want {
+				t.Error("html body should not be altered")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package email
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Classification helpers report on the nature of an email from its
+// parsed headers and structure.
+
+// extraHeader returns the first value of the named extra header, which
+// should be in canonical form, or an empty string.
+func (h *Headers) extraHeader(name string) string {
+	if v := h.ExtraHeaders[name]; len(v) > 0 {
+		return strings.TrimSpace(v[0])
+	}
+	return ""
+}
+
+// IsAutoSubmitted reports if the email appears to have been generated
+// automatically, such as a vacation reply, bounce or bulk mailing, to
+// which an automatic response should not be sent to avoid mail loops.
+// Following the best practice of RFC 3834 and common auto-responders,
+// an email is considered auto-submitted if any of the following apply:
+//
+//   - the Auto-Submitted header is present with a value other than "no"
+//   - the Precedence header is "bulk", "list" or "junk"
+//   - the X-Auto-Response-Suppress header is present with a value other
+//     than "None"
+//   - the Return-Path header is empty ("<>"), as used for bounces
+func (e *Email) IsAutoSubmitted() bool {
+	h := &e.Headers
+	if h.AutoSubmitted != "" && h.AutoSubmitted != "no" {
+		return true
+	}
+	switch strings.ToLower(h.extraHeader("Precedence")) {
+	case "bulk", "list", "junk":
+		return true
+	}
+	if v := h.extraHeader("X-Auto-Response-Suppress"); v != "" && !strings.EqualFold(v, "none") {
+		return true
+	}
+	if _, ok := h.ExtraHeaders["Return-Path"]; ok {
+		if rp := strings.Join(strings.Fields(h.extraHeader("Return-Path")), ""); rp == "<>" || rp == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Structural spam signals reported by StructuralSpamSignals.
+const (
+	SignalMissingDate            = "missing-date"
+	SignalMissingMessageID       = "missing-message-id"
+	SignalFromReturnPathMismatch = "from-return-path-mismatch"
+	SignalNoReceived             = "no-received"
+	SignalUnknownCharset         = "unknown-charset"
+	SignalHTMLOnlyTrackingPixel  = "html-only-tracking-pixel"
+)
+
+// StructuralSpamSignals returns the names of the structural red flags,
+// commonly associated with spam, that are raised by the email. This is
+// not a verdict; the signals are intended as inputs to a scoring model.
+// The signals, reported in the order below, are:
+//
+//   - SignalMissingDate: the Date header is missing or invalid
+//   - SignalMissingMessageID: the Message-ID header is missing
+//   - SignalFromReturnPathMismatch: the domain of the Return-Path
+//     differs from that of the first From address
+//   - SignalNoReceived: there are no Received headers
+//   - SignalUnknownCharset: a part declared a charset for which no
+//     decoder is known
+//   - SignalHTMLOnlyTrackingPixel: the email has an HTML body but no
+//     text body, and the HTML includes a 1x1 or 0x0 image
+func (e *Email) StructuralSpamSignals() []string {
+	h := &e.Headers
+	signals := []string{}
+	if h.Date.IsZero() {
+		signals = append(signals, SignalMissingDate)
+	}
+	if h.MessageID == "" {
+		signals = append(signals, SignalMissingMessageID)
+	}
+	from := h.addressList("From")
+	if rp := domainOf(strings.Trim(h.extraHeader("Return-Path"), "<> ")); rp != "" && len(from) > 0 && from[0] != nil {
+		if fromDomain := domainOf(from[0].Address); fromDomain != "" && fromDomain != rp {
+			signals = append(signals, SignalFromReturnPathMismatch)
+		}
+	}
+	if len(h.Received) == 0 {
+		signals = append(signals, SignalNoReceived)
+	}
+	if e.DecodeStats.CharsetFallbacks > 0 {
+		signals = append(signals, SignalUnknownCharset)
+	}
+	if e.Text == "" && e.HTML != "" && hasTrackingPixel(e.HTML) {
+		signals = append(signals, SignalHTMLOnlyTrackingPixel)
+	}
+	return signals
+}
+
+// domainOf returns the lowercased domain of an email address, or an
+// empty string if the address has no domain.
+func domainOf(address string) string {
+	i := strings.LastIndex(address, "@")
+	if i < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(address[i+1:]))
+}
+
+// hasTrackingPixel reports if the HTML includes an image with a width
+// and height of 1 or 0 pixels, as commonly used to track the opening
+// of an email.
+func hasTrackingPixel(s string) bool {
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return false
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		tok := z.Token()
+		if tok.DataAtom != atom.Img {
+			continue
+		}
+		tiny := func(v string) bool {
+			v = strings.TrimSuffix(strings.TrimSpace(v), "px")
+			return v == "0" || v == "1"
+		}
+		width, height := false, false
+		for _, a := range tok.Attr {
+			switch a.Key {
+			case "width":
+				width = tiny(a.Val)
+			case "height":
+				height = tiny(a.Val)
+			}
+		}
+		if width && height {
+			return true
+		}
+	}
+}
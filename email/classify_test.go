@@ -0,0 +1,102 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsAutoSubmitted(t *testing.T) {
+	tests := []struct {
+		headers Headers
+		isAuto  bool
+	}{
+		{Headers{}, false},
+		{Headers{AutoSubmitted: "no"}, false},
+		{Headers{AutoSubmitted: "auto-generated"}, true},
+		{Headers{ExtraHeaders: map[string][]string{"Precedence": {"Bulk"}}}, true},
+		{Headers{ExtraHeaders: map[string][]string{"Precedence": {"first-class"}}}, false},
+		{Headers{ExtraHeaders: map[string][]string{"X-Auto-Response-Suppress": {"OOF, AutoReply"}}}, true},
+		{Headers{ExtraHeaders: map[string][]string{"X-Auto-Response-Suppress": {"None"}}}, false},
+		{Headers{ExtraHeaders: map[string][]string{"Return-Path": {"< >"}}}, true},
+		{Headers{ExtraHeaders: map[string][]string{"Return-Path": {"<alice@example.com>"}}}, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			e := &Email{Headers: tt.headers}
+			if got, want := e.IsAutoSubmitted(), tt.isAuto; got != want {
+				t.Errorf("got %t want %t", got, want)
+			}
+		})
+	}
+}
+
+func TestStructuralSpamSignals(t *testing.T) {
+
+	// a message raising no signals
+	clean := func() *Email {
+		return &Email{
+			Headers: Headers{
+				Date:      time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+				MessageID: "id@example.com",
+				From:      []*mail.Address{{Address: "alice@example.com"}},
+				Received:  []string{"from mx.example.com"},
+				ExtraHeaders: map[string][]string{
+					"Return-Path": {"<bounces@Example.com>"},
+				},
+			},
+			Text: "Hello",
+			HTML: `<p>Hello</p><img src="logo.png" width="120" height="40">`,
+		}
+	}
+
+	tests := []struct {
+		modify func(e *Email)
+		want   []string
+	}{
+		{func(e *Email) {}, []string{}},
+		{func(e *Email) { e.Headers.Date = time.Time{} }, []string{SignalMissingDate}},
+		{func(e *Email) { e.Headers.MessageID = "" }, []string{SignalMissingMessageID}},
+		{
+			func(e *Email) { e.Headers.ExtraHeaders["Return-Path"] = []string{"<x@spam.example.net>"} },
+			[]string{SignalFromReturnPathMismatch},
+		},
+		{ // empty bounce return path
+			func(e *Email) { e.Headers.ExtraHeaders["Return-Path"] = []string{"<>"} },
+			[]string{},
+		},
+		{func(e *Email) { e.Headers.Received = nil }, []string{SignalNoReceived}},
+		{func(e *Email) { e.DecodeStats.CharsetFallbacks = 1 }, []string{SignalUnknownCharset}},
+		{ // html only without a pixel
+			func(e *Email) { e.Text = "" },
+			[]string{},
+		},
+		{
+			func(e *Email) {
+				e.Text = ""
+				e.HTML += `<img src="https://t.example.net/o.gif" width="1" height="1px" />`
+			},
+			[]string{SignalHTMLOnlyTrackingPixel},
+		},
+		{ // pixel with a text body
+			func(e *Email) { e.HTML += `<img src="https://t.example.net/o.gif" width="1" height="1">` },
+			[]string{},
+		},
+		{
+			func(e *Email) { e.Headers = Headers{}; e.Text = "" },
+			[]string{SignalMissingDate, SignalMissingMessageID, SignalNoReceived},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			e := clean()
+			tt.modify(e)
+			if diff := cmp.Diff(tt.want, e.StructuralSpamSignals()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
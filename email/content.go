@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"mime"
 	"net/textproto"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/net/html/charset"
 	"golang.org/x/text/encoding"
@@ -135,10 +138,15 @@ type ContentInfo struct {
 	Disposition       string            // Content-Disposition header or mime-part data description
 	DispositionParams map[string]string // Content-Disposition parameters
 	TransferEncoding  string            // Content-Transfer-Encoding header or mime-part data description
+	IgnoredEncoding   string            // illegal Content-Transfer-Encoding of a multipart, which is ignored
 	ID                string            // ContentID part labelling
+	Description       string            // Content-Description header, undecoded
+	Duration          time.Duration     // Content-Duration header (RFC 3803)
+	Languages         []string          // Content-Language header tags, lowercased (RFC 3282)
+	Location          string            // Content-Location header URI (RFC 2557)
 	// additional fields
 	Charset  string            // the charset extracted from the content type
-	Encoding encoding.Encoding // the encoding determined by the charset
+	Encoding encoding.Encoding `json:"-"` // the encoding determined by the charset
 	encDone  bool              // flag to show if encoding has been run
 }
 
@@ -164,6 +172,10 @@ var contentTransferEncodings = []string{
 	"base64",
 }
 
+// identityTransferEncodings is a slice of the transfer encodings which
+// don't alter content
+var identityTransferEncodings = []string{"7bit", "8bit", "binary"}
+
 // ExtractContentInfo extracts information from a headers map from
 // either a net/mail.Message.Header or mime/multipart.Part.Header, whose
 // underlying type is a map[string][]string.
@@ -195,6 +207,10 @@ func ExtractContentInfo(headers map[string][]string, parentCI *ContentInfo) (*Co
 		return c, err
 	}
 	c.extractID(get("Content-ID"))
+	c.Description = strings.TrimSpace(get("Content-Description"))
+	c.extractDuration(get("Content-Duration"))
+	c.extractLanguages(get("Content-Language"))
+	c.extractLocation(get("Content-Location"))
 	return c, nil
 }
 
@@ -226,7 +242,11 @@ func (c *ContentInfo) IsAttachedFile(parentCI *ContentInfo) bool {
 	return false
 }
 
-// extractType extracts the Content-Type and Parameter information
+// extractType extracts the Content-Type and Parameter information,
+// decoding any RFC 2231 extended parameters. A missing Content-Type
+// defaults to text/plain following RFC 2045 section 5.2. The us-ascii
+// charset default is not applied, to avoid mangling undeclared 8bit
+// content.
 func (c *ContentInfo) extractType(s string) error {
 	if s == "" {
 		s = "text/plain"
@@ -236,6 +256,7 @@ func (c *ContentInfo) extractType(s string) error {
 	if err != nil {
 		return fmt.Errorf("cannot extract Content-Type %q: %w", s, err)
 	}
+	decodeExtendedParams(s, c.TypeParams)
 	for _, param := range []string{"charset", "micalg", "protocol"} {
 		if v, ok := c.TypeParams[param]; ok {
 			c.TypeParams[param] = strings.ToLower(v)
@@ -244,29 +265,101 @@ func (c *ContentInfo) extractType(s string) error {
 	return nil
 }
 
-// extractCharset extracts the charset from the Content Type or parent
-// Content Type
+// extractCharset extracts the charset from the Content Type or, if the
+// part declares none, the charset of the enclosing part. A part's own
+// charset always takes precedence, so that the parts of a message may
+// use different charsets, while a charset declared on an enclosing
+// multipart is inherited by the nearest text parts lacking one. Other
+// parts, such as images, do not inherit a charset, so that their
+// content is not decoded as text, although nested multiparts pass it
+// on to their own text parts.
 func (c *ContentInfo) extractCharset(parentCI *ContentInfo) {
 	c.Charset = c.TypeParams["charset"]
-	if c.Charset == "" && parentCI != nil {
-		c.Charset = parentCI.TypeParams["charset"]
+	inherits := strings.HasPrefix(c.Type, "text/") ||
+		strings.HasPrefix(c.Type, "multipart/")
+	if c.Charset == "" && inherits && parentCI != nil {
+		c.Charset = parentCI.Charset
+		if c.Charset == "" {
+			c.Charset = parentCI.TypeParams["charset"]
+		}
 	}
 }
 
+// Direction returns "implicit" or "explicit" for the bidirectional
+// charsets of RFC 1555 and RFC 1556, such as "iso-8859-8-i", or an
+// empty string for other charsets.
+func (c *ContentInfo) Direction() string {
+	_, direction := charsetDirection(c.Charset)
+	return direction
+}
+
 // ExtractEncoding extracts an encoding from a charset
 func (c *ContentInfo) ExtractEncoding() {
 	if c.encDone {
 		return
 	}
-	c.Encoding, _ = charset.Lookup(c.Charset)
-	if c.Encoding == nil {
-		normalizedLabel := strings.ReplaceAll(c.Charset, "windows-", "cp")
-		c.Encoding, _ = charset.Lookup(normalizedLabel)
-	}
+	c.Encoding, _ = LookupCharset(c.Charset)
 	c.encDone = true
 }
 
-// extractDisposition extracts the Content-Disposition and Parameter information
+// bidiCharsetRegexp matches the bidirectional Arabic and Hebrew charset
+// labels of RFC 1555 and RFC 1556, such as "iso-8859-8-i", capturing the
+// base charset number and the "i" (implicit) or "e" (explicit)
+// directionality suffix.
+var bidiCharsetRegexp = regexp.MustCompile(`^iso[-_]?8859[-_]?([68])[-_]([ie])$`)
+
+// charsetAliases maps common misspelt or non-standard charset labels
+// found in the wild to the labels known to charset.Lookup.
+var charsetAliases = map[string]string{
+	"latin-1":     "iso-8859-1",
+	"iso-latin-1": "iso-8859-1",
+	"8859-1":      "iso-8859-1",
+	"iso8859_1":   "iso-8859-1",
+	"latin-9":     "iso-8859-15",
+	"latin9":      "iso-8859-15",
+	"ansi":        "windows-1252",
+	"cp-1252":     "windows-1252",
+	"win-1252":    "windows-1252",
+	"windows1252": "windows-1252",
+	"cp932":       "shift_jis",
+	"cp936":       "gbk",
+	"cp949":       "euc-kr",
+	"cp950":       "big5",
+}
+
+// LookupCharset returns the encoding for a charset label, or nil if the
+// label is unknown. Labels are tried as given, then with a "windows-"
+// prefix rewritten as "cp", and finally using a small table of common
+// aliases. The directionality suffixes of bidirectional
+// labels such as "iso-8859-8-i" are removed for lookup, as they do not
+// change the character mapping, and reported as a direction of
+// "implicit" or "explicit".
+func LookupCharset(label string) (encoding.Encoding, string) {
+	label = strings.ToLower(strings.TrimSpace(label))
+	base, direction := charsetDirection(label)
+	for _, l := range []string{label, strings.ReplaceAll(label, "windows-", "cp"), charsetAliases[label], base} {
+		if l == "" {
+			continue
+		}
+		if enc, _ := charset.Lookup(l); enc != nil {
+			return enc, direction
+		}
+	}
+	return nil, direction
+}
+
+// charsetDirection returns the base charset and direction of a
+// bidirectional charset label, or empty strings for other labels.
+func charsetDirection(label string) (base, direction string) {
+	m := bidiCharsetRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(label)))
+	if m == nil {
+		return "", ""
+	}
+	return "iso-8859-" + m[1], map[string]string{"i": "implicit", "e": "explicit"}[m[2]]
+}
+
+// extractDisposition extracts the Content-Disposition and Parameter
+// information, decoding any RFC 2231 extended parameters.
 func (c *ContentInfo) extractDisposition(s string) error {
 	if s == "" {
 		return nil
@@ -276,6 +369,7 @@ func (c *ContentInfo) extractDisposition(s string) error {
 	if err != nil {
 		return fmt.Errorf("cannot extract Content-Disposition %q: %w", s, err)
 	}
+	decodeExtendedParams(s, c.DispositionParams)
 	if !inSlice(contentDispositions, c.Disposition) {
 		return fmt.Errorf("unknown Content-Disposition %q", c.Disposition)
 	}
@@ -290,6 +384,13 @@ func (c *ContentInfo) extractTransferEncoding(s string) error {
 		return nil
 	}
 
+	// multipart entities may only use an identity encoding (RFC 2045
+	// section 6.4); decoding the body would corrupt its boundaries
+	if strings.HasPrefix(c.Type, "multipart/") && !inSlice(identityTransferEncodings, c.TransferEncoding) {
+		c.IgnoredEncoding = c.TransferEncoding
+		return nil
+	}
+
 	if !inSlice(contentTransferEncodings, c.TransferEncoding) {
 		return fmt.Errorf("unknown Content-Transfer-Encoding %q", c.TransferEncoding)
 	}
@@ -300,3 +401,37 @@ func (c *ContentInfo) extractTransferEncoding(s string) error {
 func (c *ContentInfo) extractID(s string) {
 	c.ID = strings.TrimSpace(strings.Trim(s, "<>"))
 }
+
+// extractLocation extracts the Content-Location URI, removing the
+// whitespace which may be introduced by folding long URIs (RFC 2557
+// section 4.3) and any surrounding quotes.
+func (c *ContentInfo) extractLocation(s string) {
+	c.Location = strings.Trim(strings.Join(strings.Fields(s), ""), `"`)
+}
+
+// languageTagRegexp matches an RFC 5646 language tag, such as "en" or
+// "de-CH", loosely.
+var languageTagRegexp = regexp.MustCompile(`^[A-Za-z]{1,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// extractLanguages extracts the comma separated language tags of the
+// Content-Language header, lowercased and with any comments removed.
+// Malformed tags are ignored.
+func (c *ContentInfo) extractLanguages(s string) {
+	for _, tag := range strings.Split(s, ",") {
+		tag, _, _ = strings.Cut(tag, "(")
+		if tag = strings.TrimSpace(tag); languageTagRegexp.MatchString(tag) {
+			c.Languages = append(c.Languages, strings.ToLower(tag))
+		}
+	}
+}
+
+// extractDuration extracts the Content-Duration of audio and video
+// content, expressed as a whole number of seconds by RFC 3803. Invalid
+// durations are ignored.
+func (c *ContentInfo) extractDuration(s string) {
+	seconds, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || seconds < 0 {
+		return
+	}
+	c.Duration = time.Duration(seconds) * time.Second
+}
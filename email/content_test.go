@@ -3,6 +3,7 @@ package email
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -49,6 +50,19 @@ func TestExtractContentTypeHeader(t *testing.T) {
 				"boundary": "SignedBoundaryString",
 			},
 		},
+		{ // RFC 2231 continuations of a name in a shift_jis charset
+			input: `application/pdf; name*0*=shift_jis''%8C%A9%90%CF;` +
+				` name*1*=%8F%91; name*2=.pdf`,
+			contentType: "application/pdf",
+			params: map[string]string{
+				"name": "見積書.pdf",
+			},
+		},
+		{ // missing Content-Type defaults to text/plain (RFC 2045 s5.2)
+			input:       "",
+			contentType: "text/plain",
+			params:      map[string]string{},
+		},
 	}
 
 	for i, tt := range tests {
@@ -91,6 +105,32 @@ func TestExtractContentDisposition(t *testing.T) {
 				"filename": "inline-jpg-image-filename.jpg",
 			},
 		},
+		// thai filename split within a character across three sections
+		{
+			input: `attachment; filename*0*=utf-8'th'%E0%B8%A3%E0%B8%B2%E0%B8;` +
+				` filename*1*=%A2%E0%B8%87%E0%B8%B2%E0%B8%99; filename*2=".pdf"`,
+			contentDisposition: "attachment",
+			params: map[string]string{
+				"filename": "รายงาน.pdf",
+			},
+		},
+		// german filename in a charset not decoded by mime.ParseMediaType
+		{
+			input: `attachment; filename="fallback.pdf";` +
+				` filename*0*=iso-8859-1''%DCbersicht%20; filename*1*=M%E4rz;` +
+				` filename*2=".pdf"`,
+			contentDisposition: "attachment",
+			params: map[string]string{
+				"filename": "Übersicht März.pdf",
+			},
+		},
+		{
+			input:              `attachment; filename*=windows-1252''Gr%FC%DFe.txt`,
+			contentDisposition: "attachment",
+			params: map[string]string{
+				"filename": "Grüße.txt",
+			},
+		},
 	}
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
@@ -113,13 +153,32 @@ func TestExtractContentDisposition(t *testing.T) {
 
 func TestExtractContentTransferEncoding(t *testing.T) {
 	tests := []struct {
-		input string
-		cte   string
+		contentType string
+		input       string
+		cte         string
+		ignored     string
 	}{
 		{
 			input: ``, // empty
 			cte:   "7bit",
 		},
+		{
+			contentType: "multipart/mixed",
+			input:       `base64`,
+			cte:         "base64",
+			ignored:     "base64",
+		},
+		{
+			contentType: "multipart/related",
+			input:       `X-UUEncode`,
+			cte:         "x-uuencode",
+			ignored:     "x-uuencode",
+		},
+		{
+			contentType: "multipart/alternative",
+			input:       `8bit`,
+			cte:         "8bit",
+		},
 		{
 			input: `base64`,
 			cte:   "base64",
@@ -131,7 +190,7 @@ func TestExtractContentTransferEncoding(t *testing.T) {
 	}
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
-			c := &ContentInfo{}
+			c := &ContentInfo{Type: tt.contentType}
 			err := c.extractTransferEncoding(tt.input)
 			if err != nil {
 				t.Fatalf("cannot parse part Content-Transfer-Encoding: %s", err)
@@ -140,16 +199,86 @@ func TestExtractContentTransferEncoding(t *testing.T) {
 			if got, want := c.TransferEncoding, tt.cte; got != want {
 				t.Errorf("got %s want %s", got, want)
 			}
+			if got, want := c.IgnoredEncoding, tt.ignored; got != want {
+				t.Errorf("ignored got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestExtractContentDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		duration time.Duration
+	}{
+		{``, 0},
+		{`33`, 33 * time.Second},
+		{` 120 `, 2 * time.Minute},
+		{`-5`, 0},
+		{`1.5`, 0},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			c := &ContentInfo{}
+			c.extractDuration(tt.input)
+			if got, want := c.Duration, tt.duration; got != want {
+				t.Errorf("got %s want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestExtractLanguages(t *testing.T) {
+	tests := []struct {
+		input     string
+		languages []string
+	}{
+		{``, nil},
+		{`en`, []string{"en"}},
+		{`th, en`, []string{"th", "en"}},
+		{`de-CH (Swiss German), fr`, []string{"de-ch", "fr"}},
+		{`EN-gb, Th`, []string{"en-gb", "th"}},
+		{`???, en_GB, `, nil},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			c := &ContentInfo{}
+			c.extractLanguages(tt.input)
+			if diff := cmp.Diff(tt.languages, c.Languages); diff != "" {
+				t.Errorf("languages are not equal\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExtractLocation(t *testing.T) {
+	tests := []struct {
+		input    string
+		location string
+	}{
+		{``, ""},
+		{`images/logo.png`, "images/logo.png"},
+		{` "http://example.com/news/images/` + "\r\n " + `banner.png" `, "http://example.com/news/images/banner.png"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			c := &ContentInfo{}
+			c.extractLocation(tt.input)
+			if got, want := c.Location, tt.location; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
 		})
 	}
 }
 
 func TestExtractCharset(t *testing.T) {
 	tests := []struct {
+		ctype       string
 		input       string
 		parentCI    *ContentInfo
 		charset     string
 		hasEncoding bool
+		direction   string
 	}{
 		{
 			input:       "xyz",
@@ -165,15 +294,87 @@ func TestExtractCharset(t *testing.T) {
 			charset:     "UTF-8",
 			hasEncoding: true,
 		},
+		{
+			input: "tis-620",
+			parentCI: &ContentInfo{
+				Charset:    "utf-8",
+				TypeParams: map[string]string{},
+			},
+			charset:     "tis-620",
+			hasEncoding: true,
+		},
+		{
+			input: "",
+			parentCI: &ContentInfo{
+				Charset:    "tis-620",
+				TypeParams: map[string]string{},
+			},
+			charset:     "tis-620",
+			hasEncoding: true,
+		},
+		{
+			input:       "iso-8859-8-i",
+			charset:     "iso-8859-8-i",
+			hasEncoding: true,
+			direction:   "implicit",
+		},
+		{
+			input:       "iso_8859-8-e",
+			charset:     "iso_8859-8-e",
+			hasEncoding: true,
+			direction:   "explicit",
+		},
+		{
+			input:       "iso8859-6-i",
+			charset:     "iso8859-6-i",
+			hasEncoding: true,
+			direction:   "implicit",
+		},
+		{
+			input:       "Latin-1",
+			charset:     "Latin-1",
+			hasEncoding: true,
+		},
+		{
+			input:       "cp932",
+			charset:     "cp932",
+			hasEncoding: true,
+		},
+		{
+			ctype: "image/gif",
+			input: "",
+			parentCI: &ContentInfo{
+				Type:       "multipart/mixed",
+				TypeParams: map[string]string{"charset": "utf-8"},
+			},
+			charset:     "",
+			hasEncoding: false,
+		},
+		{
+			ctype: "multipart/alternative",
+			input: "",
+			parentCI: &ContentInfo{
+				Type:       "multipart/mixed",
+				TypeParams: map[string]string{"charset": "utf-8"},
+			},
+			charset:     "utf-8",
+			hasEncoding: true,
+		},
 	}
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
-			c := &ContentInfo{}
+			c := &ContentInfo{Type: tt.ctype}
+			if c.Type == "" {
+				c.Type = "text/plain"
+			}
 			c.TypeParams = map[string]string{"charset": tt.input}
 			c.extractCharset(tt.parentCI)
 			if got, want := c.Charset, tt.charset; got != want {
 				t.Errorf("charset got %s want %s", got, want)
 			}
+			if got, want := c.Direction(), tt.direction; got != want {
+				t.Errorf("direction got %q want %q", got, want)
+			}
 			c.ExtractEncoding()
 			if got, want := (c.Encoding != nil), tt.hasEncoding; got != want {
 				t.Errorf("encoding got %t want %t", got, want)
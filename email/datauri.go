@@ -0,0 +1,128 @@
+package email
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// imageExtensions maps the media types of images to the extensions of
+// the synthetic names given to images extracted from data URIs. A
+// fixed table is used, rather than the host's mime tables, so that
+// names are the same on every machine.
+var imageExtensions = map[string]string{
+	"image/avif":               ".avif",
+	"image/bmp":                ".bmp",
+	"image/gif":                ".gif",
+	"image/heic":               ".heic",
+	"image/jpeg":               ".jpg",
+	"image/png":                ".png",
+	"image/svg+xml":            ".svg",
+	"image/tiff":               ".tif",
+	"image/vnd.microsoft.icon": ".ico",
+	"image/webp":               ".webp",
+	"image/x-icon":             ".ico",
+}
+
+// dataURIContentIDDomain is the domain of the synthetic Content-IDs
+// given to images extracted from data URIs, making each a valid
+// msg-id (RFC 2392).
+const dataURIContentIDDomain = "data-uri.invalid"
+
+// parseDataURIImage parses a base64 encoded image data URI, such as
+// "data:image/png;base64,iVBORw0KGgo...", returning its media type and
+// the base64 data, which may include whitespace, or false if u is not
+// such a URI.
+func parseDataURIImage(u string) (mediaType, data string, ok bool) {
+	if len(u) < len("data:") || !strings.EqualFold(u[:len("data:")], "data:") {
+		return "", "", false
+	}
+	header, data, ok := strings.Cut(u[len("data:"):], ",")
+	if !ok {
+		return "", "", false
+	}
+	header = strings.ToLower(header)
+	header, ok = strings.CutSuffix(header, ";base64")
+	if !ok {
+		return "", "", false
+	}
+	mediaType, _, _ = strings.Cut(header, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if !strings.HasPrefix(mediaType, "image/") || len(mediaType) == len("image/") {
+		return "", "", false
+	}
+	return mediaType, data, true
+}
+
+// dataURIFile decodes the data URI content into an inline File, with a
+// synthetic name and Content-ID derived from the index.
+func dataURIFile(index int, mediaType, data string) (*File, error) {
+	data = strings.Join(strings.Fields(data), "")
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode data uri %d: %w", index, err)
+		}
+	}
+	ext, ok := imageExtensions[mediaType]
+	if !ok {
+		ext = "." + strings.TrimPrefix(mediaType, "image/")
+	}
+	name := fmt.Sprintf("data_uri_image_%d%s", index, ext)
+	return &File{
+		FileType: "inline",
+		Name:     name,
+		ContentInfo: &ContentInfo{
+			Type:              mediaType,
+			TypeParams:        map[string]string{"name": name},
+			Disposition:       "inline",
+			DispositionParams: map[string]string{"filename": name},
+			TransferEncoding:  "base64",
+			ID:                fmt.Sprintf("data-uri-image-%d@%s", index, dataURIContentIDDomain),
+		},
+		Data: decoded,
+	}, nil
+}
+
+// extractDataURIImages decodes the base64 image data URIs in the URL
+// attributes and CSS of html into Files, returning html with each
+// decodable data URI replaced by a "cid:" reference to the Content-ID
+// of its File.
+func extractDataURIImages(html string) (string, []*File) {
+	files := []*File{}
+	html = rewriteHTMLURLs(html, func(u string) (string, bool) {
+		mediaType, data, ok := parseDataURIImage(u)
+		if !ok {
+			return "", false
+		}
+		f, err := dataURIFile(len(files), mediaType, data)
+		if err != nil {
+			return "", false
+		}
+		files = append(files, f)
+		return "cid:" + f.ContentInfo.ID, true
+	})
+	return html, files
+}
+
+// ExtractDataURIImages decodes the base64 image data URIs embedded in
+// the HTML body of the email, such as in the src of an img element or
+// a CSS url(), into inline Files with synthetic names of the form
+// "data_uri_image_<n>.<ext>". Data URIs which cannot be decoded are
+// skipped. The HTML body is not altered.
+func (e *Email) ExtractDataURIImages() []*File {
+	_, files := extractDataURIImages(e.HTML)
+	return files
+}
+
+// RewriteDataURIImages extracts the base64 image data URIs embedded in
+// the HTML body of the email as ExtractDataURIImages does, and rewrites
+// each decodable data URI in the HTML body as a "cid:" reference to the
+// Content-ID of the returned File. The returned Files are not added to
+// the email's Files.
+func (e *Email) RewriteDataURIImages() []*File {
+	var files []*File
+	e.HTML, files = extractDataURIImages(e.HTML)
+	return files
+}
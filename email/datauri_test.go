@@ -0,0 +1,87 @@
+package email
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// a 1x1 gif
+const testGIF string = "R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7"
+
+func TestExtractDataURIImages(t *testing.T) {
+	e := &Email{
+		HTML: `<p>Logo</p><img src="data:image/gif;base64,` + testGIF + `">` +
+			`<img src="data:image/png;base64,!!!not-base64">` +
+			`<div style="background: url(data:image/GIF;charset=utf-8;base64,R0lGODlhAQABAIAAAAAAAP///yH5BAEA
+AAAALAAAAAABAAEAAAIBRAA7)"></div>`,
+	}
+	original := e.HTML
+
+	files := e.ExtractDataURIImages()
+	if got, want := len(files), 2; got != want {
+		t.Fatalf("got %d want %d files", got, want)
+	}
+	if got, want := e.HTML, original; got != want {
+		t.Error("html body should not be altered")
+	}
+	for _, f := range files {
+		if got, want := f.ContentInfo.Type, "image/gif"; got != want {
+			t.Errorf("type got %s want %s", got, want)
+		}
+		if got, want := string(f.Data[:6]), "GIF89a"; got != want {
+			t.Errorf("data got %q want %q", got, want)
+		}
+	}
+	names := []string{files[0].Name, files[1].Name}
+	if diff := cmp.Diff([]string{"data_uri_image_0.gif", "data_uri_image_1.gif"}, names); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRewriteDataURIImages(t *testing.T) {
+	e := &Email{
+		HTML: `<img src="data:image/gif;base64,` + testGIF + `"><img src="cid:other">`,
+	}
+	files := e.RewriteDataURIImages()
+	if got, want := len(files), 1; got != want {
+		t.Fatalf("got %d want %d files", got, want)
+	}
+	if got, want := e.HTML, `<img src="cid:data-uri-image-0@data-uri.invalid"><img src="cid:other">`; got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+	if got, want := files[0].ContentInfo.ID, "data-uri-image-0@data-uri.invalid"; got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
+
+func TestRewriteDataURIImagesMarkup(t *testing.T) {
+	uri := "data:image/gif;base64," + testGIF
+	cid := "cid:data-uri-image-0@data-uri.invalid"
+	tests := []struct {
+		html string
+		want string
+	}{
+		// text is not a url
+		{`<p>` + uri + `</p>`, `<p>` + uri + `</p>`},
+		{
+			`<style>p { background: url('` + uri + `') }</style>`,
+			`<style>p { background: url('` + cid + `') }</style>`,
+		},
+		{
+			`<IMG alt=logo SRC = "` + uri + `"><br>`,
+			`<img alt="logo" src="` + cid + `"><br>`,
+		},
+		{`<img src="data:text/plain;base64,aGk=">`, `<img src="data:text/plain;base64,aGk=">`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			e := &Email{HTML: tt.html}
+			e.RewriteDataURIImages()
+			if got, want := e.HTML, tt.want; got != want {
+				t.Errorf("got %s want %s", got, want)
+			}
+		})
+	}
+}
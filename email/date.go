@@ -0,0 +1,21 @@
+package email
+
+import "time"
+
+// SenderTimezoneOffset returns the numeric timezone offset of the Date
+// header as stamped by the sender's client, approximating the sender's
+// locale, for example -5h for "Tue, 8 Apr 2025 09:00:00 -0500". The
+// boolean reports if a non-zero offset was present; it is false for a
+// missing date and for a "+0000" or "-0000" (unknown) offset, which
+// cannot be distinguished from a sender in UTC.
+//
+// Dates lacking a timezone which are interpreted with the
+// WithDefaultTimezone parser option report the offset of that
+// timezone rather than of the sender.
+func (h *Headers) SenderTimezoneOffset() (time.Duration, bool) {
+	if h.Date.IsZero() {
+		return 0, false
+	}
+	_, offset := h.Date.Zone()
+	return time.Duration(offset) * time.Second, offset != 0
+}
@@ -0,0 +1,42 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"testing"
+	"time"
+)
+
+func TestSenderTimezoneOffset(t *testing.T) {
+	tests := []struct {
+		date   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"Tue, 8 Apr 2025 09:00:00 -0500", -5 * time.Hour, true},
+		{"Tue, 8 Apr 2025 19:30:00 +0530", 5*time.Hour + 30*time.Minute, true},
+		{"Tue, 8 Apr 2025 14:00:00 +0000", 0, false},
+		{"Tue, 8 Apr 2025 14:00:00 -0000", 0, false},
+		{"Tue, 8 Apr 2025 14:00:00 GMT", 0, false},
+		{"", 0, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			h := &Headers{}
+			if tt.date != "" {
+				d, err := mail.ParseDate(tt.date)
+				if err != nil {
+					t.Fatal(err)
+				}
+				h.Date = d
+			}
+			offset, ok := h.SenderTimezoneOffset()
+			if got, want := offset, tt.offset; got != want {
+				t.Errorf("offset got %s want %s", got, want)
+			}
+			if got, want := ok, tt.ok; got != want {
+				t.Errorf("ok got %t want %t", got, want)
+			}
+		})
+	}
+}
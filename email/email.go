@@ -48,6 +48,122 @@ type Email struct {
 
 	// Inline and attached files
 	Files []*File
+
+	// ContentIDFiles indexes the files having a Content-ID by its value
+	// stripped of angle brackets, for resolving "cid:" references in
+	// HTML bodies. If several files share a Content-ID the last is
+	// indexed.
+	ContentIDFiles map[string]*File `json:"-"`
+
+	// UnhandledParts records the parts of an unknown content type if
+	// parsing with the WithSkipUnknownContentTypes option. The Data of
+	// each is the raw content of the part, which has not been transfer
+	// or charset decoded (decoders.DecodeContent may be used to decode
+	// it), and is only read when parsing the whole email.
+	UnhandledParts []*File
+
+	// InlinePGP holds the first OpenPGP armored block found in a plain
+	// text body if parsing with the WithDetectInlinePGP option.
+	InlinePGP *InlinePGP
+
+	// LanguageBodies holds the plain text and html bodies of parts
+	// declaring a Content-Language, grouped by language, for selection
+	// with BodyForLanguage.
+	LanguageBodies []LanguageBody
+
+	// SubMessages are the emails enclosed in the email, such as the
+	// messages of a multipart/digest or forwarded messages, parsed with
+	// the same options, to the depth permitted by the parser's
+	// WithMaxMessageDepth option. Enclosed message/rfc822 parts outside
+	// of a digest are also retained in Files.
+	SubMessages []*Email
+
+	// FeedbackReport holds the machine-readable part of an Abuse
+	// Reporting Format feedback report, if any.
+	FeedbackReport *FeedbackReport
+
+	// Calendars summarise the iCalendar objects of text/calendar parts,
+	// such as meeting invitations, which are also retained in Files.
+	Calendars []*Calendar
+
+	// OrderedParts holds the body parts and files of the email in
+	// document order if parsing with the WithOrderedParts option.
+	OrderedParts []OrderedPart
+
+	// HeaderInjectionSuspected reports if a header contained an
+	// embedded line break which was not part of legitimate folding, as
+	// used in header injection attacks. Details are recorded in
+	// Warnings.
+	HeaderInjectionSuspected bool
+
+	// RawMessage holds the raw bytes of the message if parsing with the
+	// WithRetainRaw option.
+	RawMessage []byte
+
+	// Warnings records non-fatal problems encountered while parsing,
+	// such as repairs made to the message, together with the problems
+	// recovered from when parsing with the WithLenient option, which
+	// are also recorded in Errors.
+	Warnings []error
+
+	// Errors records the problems recovered from when parsing with the
+	// WithLenient option, such as bad addresses, undecodable headers,
+	// unknown charsets and unknown content types, which would otherwise
+	// have aborted the parse. Each is also recorded in Warnings.
+	Errors []ParseError
+
+	// DecodeStats records counts of decoding fallbacks made while
+	// parsing, useful for assessing the quality of a mail corpus.
+	DecodeStats DecodeStats
+}
+
+// OrderedPart kinds
+const (
+	OrderedPartText     = "text"
+	OrderedPartEnriched = "enriched"
+	OrderedPartHTML     = "html"
+	OrderedPartFile     = "file"
+)
+
+// OrderedPart is a body part or file of an email, recorded in document
+// order to allow the reading flow of an email interleaving text and
+// inline images to be reconstructed. Kind is one of the OrderedPart*
+// kinds. Text is set for text, enriched and html parts, while File is
+// set for file parts.
+type OrderedPart struct {
+	Kind string
+	Text string
+	File *File
+}
+
+// DecodeStats holds counters of the decoding fallbacks, repairs and
+// skips made during the parsing of an email.
+type DecodeStats struct {
+	// CharsetFallbacks counts parts whose declared charset could not
+	// be resolved to an encoding.
+	CharsetFallbacks int
+	// LenientRepairs counts problems repaired or worked around
+	// through lenient parsing.
+	LenientRepairs int
+	// UnknownSkipped counts parts skipped due to their content type not
+	// being handled.
+	UnknownSkipped int
+}
+
+// ParseError is a problem recovered from when parsing leniently.
+type ParseError struct {
+	// ContentType is the media type of the part in which the problem
+	// was encountered, or empty if it was in the headers.
+	ContentType string
+	Err         error
+}
+
+func (e ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ParseError) Unwrap() error {
+	return e.Err
 }
 
 type Headers struct {
@@ -126,6 +242,11 @@ type Headers struct {
 	From    []*mail.Address
 	ReplyTo []*mail.Address
 
+	// FromComments holds the parenthetical comments of the From
+	// addresses, such as "work" in "john@example.com (work)", keyed by
+	// address, if parsing with the WithAddressComments option.
+	FromComments map[string][]string
+
 	// RFC 3522 3.6.3.  Destination Address Fields
 	//
 	// The destination fields of a message consist of three possible fields,
@@ -307,10 +428,20 @@ type Headers struct {
 	// body of the message.  The "Keywords:" field contains a comma-
 	// separated list of important words and phrases that might be useful
 	// for the recipient.
+	//
+	// The values of multiple Comments headers are joined by newlines,
+	// while the keywords of multiple Keywords headers are merged.
 	Subject  string
 	Comments string
 	Keywords []string
 
+	// RawSubject is the Subject header value as it appears on the
+	// wire, including any folding line breaks but without the
+	// whitespace following the colon, before unfolding and RFC 2047
+	// decoding. It is retained for forensic purposes if parsing with
+	// the WithRawSubject option.
+	RawSubject string
+
 	// RFC 3522 3.6.6.  Resent Fields
 	//
 	// Resent fields SHOULD be added to any message that is reintroduced by
@@ -399,15 +530,75 @@ type Headers struct {
 	ResentBcc       []*mail.Address
 	ResentMessageID string
 
+	// OriginalMessageID is the message id, stripped of angle brackets,
+	// of the message from which a forwarded or resent message was
+	// derived, taken from the Original-Message-ID header or, failing
+	// that, the X-Original-Message-ID header.
+	OriginalMessageID string
+
+	// RFC 3834 5. The Auto-Submitted header field
+	// AutoSubmitted is the lowercased Auto-Submitted keyword, such as
+	// "no", "auto-generated" or "auto-replied", without any comments or
+	// parameters.
+	AutoSubmitted string
+
+	// Priority is the priority of the message, PriorityHigh,
+	// PriorityNormal or PriorityLow, reconciled from the Importance,
+	// X-MSMail-Priority and X-Priority headers in that order of
+	// precedence. It is PriorityNormal if none of the headers is
+	// present or has a recognised value.
+	Priority Priority
+
+	// PriorityRaw holds the raw values of the Importance,
+	// X-MSMail-Priority and X-Priority headers present, keyed by their
+	// canonical names, such as "X-Msmail-Priority". The headers are
+	// also left in ExtraHeaders, as they are not standard headers.
+	PriorityRaw map[string]string
+
+	// SpamStatus is the SpamAssassin verdict parsed from the
+	// X-Spam-Status and X-Spam-Score headers, or nil if neither is
+	// present. The raw headers remain in ExtraHeaders.
+	SpamStatus *SpamStatus
+
+	// RFC 5064 The Archived-At Message Header Field
+	// ArchivedAt is the URI, stripped of angle brackets, of an archived
+	// copy of the message.
+	ArchivedAt string
+
+	// RFC 2369 3.6. List-Archive
+	// ListArchive holds the URIs, stripped of angle brackets, of the
+	// archives of the mailing list from which the message was sent.
+	ListArchive []string
+
 	// ExtraHeaders are those headers that aren't explicitly named in
 	// fields above.
 	ExtraHeaders map[string][]string
 
+	// LazyAddresses holds the unparsed address list headers if parsing
+	// with the WithLazyAddresses option, in which case the From,
+	// Reply-To, To, Cc, Bcc and Resent address list fields are not
+	// populated. Use ParsedAddresses or methods such as ParsedTo to
+	// access addresses regardless of the option.
+	LazyAddresses *LazyAddresses `json:"-"`
+
+	// RawOrdered holds all headers in the order they appear in the
+	// message, with their values unfolded but not decoded. Duplicate
+	// headers, such as Received and DKIM-Signature, are retained in
+	// sequence.
+	RawOrdered []RawHeader
+
 	// RFC 2045 5.  Content-Type Header Field
 	// ContentInfo holds the Content-Type, Content-Disposition and
 	// related content information.
 	ContentInfo *ContentInfo
 
+	// RFC 3282 2. The Content-Language header
+	// ContentLanguage holds the lowercased language tags of the
+	// message's Content-Language header, such as ["th", "en"], or nil if
+	// none is declared. The tags of individual parts are held in their
+	// ContentInfo.
+	ContentLanguage []string
+
 	// RFC2076 3.2 Trace Information (for "Received" header)
 	// references RFC 822: 4.3.2; RFC 1123: 5.2.8
 	// 822: 4.1.2.  COMMAND SYNTAX
@@ -443,10 +634,31 @@ type Headers struct {
 	//  	<zone> ::= "UT" for Universal Time (the default) or other
 	//  	          time zone designator (as in [2]).
 	//
-	// Received does not (at present) attempt to parse the received
-	// fragments. In future consider this being a slice of a "Received"
-	// type.
+	// Received holds the raw Received headers, most recent first.
 	Received []string
+
+	// ParsedReceived holds the Received headers parsed into their
+	// clauses and timestamps, in the same order as Received.
+	ParsedReceived []ReceivedHeader
+
+	// RFC 6376 3.5. The DKIM-Signature Header Field
+	// DKIMSignatures holds the DKIM-Signature headers parsed into their
+	// tags, in the order they appear in the message. The signatures are
+	// not verified. The raw headers remain in ExtraHeaders.
+	DKIMSignatures []DKIMSignature
+
+	// RFC 8601 2.2. The Authentication-Results Header Field
+	// AuthResults holds the results of the authentication methods
+	// reported by the Authentication-Results headers, in the order they
+	// appear in the message. The raw headers remain in ExtraHeaders.
+	AuthResults []AuthResult
+
+	// RFC 8617 4.1.1. ARC-Authentication-Results (AAR)
+	// ARCAuthResults holds the results reported by the
+	// ARC-Authentication-Results headers, in the order they appear in
+	// the message, with the Instance of each result set. The raw
+	// headers remain in ExtraHeaders.
+	ARCAuthResults []AuthResult
 }
 
 // File is a shared type between inline and attached files. Internally
@@ -458,9 +670,67 @@ type Headers struct {
 // The File Name will be extracted from the content type header
 // parameters if possible, otherwise it will be autogenerated.
 type File struct {
+	// FileType is the Content-Disposition of the file, such as
+	// "inline" or "attachment", or "calendar" for a text/calendar part
+	// without a disposition.
 	FileType    string
 	Name        string
 	ContentInfo *ContentInfo
 	Reader      io.Reader
 	Data        []byte
+
+	// Index is the position of the file's part among all the parts of
+	// the message in depth-first document order, counting from 0,
+	// including body text, skipped and multipart container parts. It
+	// allows files to be placed in their original order, and is 0 for
+	// the body of a single part message.
+	Index int
+
+	// Location is the Content-Location of the file, if any, used to
+	// resolve references to the file from an HTML body (RFC 2557).
+	Location string
+
+	// InferredType is the media type inferred from the file name
+	// extension of an application/octet-stream file if parsing with the
+	// WithInferContentType option. The declared ContentInfo.Type is not
+	// altered.
+	InferredType string
+
+	// SniffedType is the media type detected from the content of the
+	// file if parsing with the WithDetectMagicBytes option, and
+	// TypeMismatch reports if it disagrees with the declared type or
+	// the type implied by the file name extension.
+	SniffedType  string
+	TypeMismatch bool
+
+	// MaxSize is the maximum decoded size of the file in bytes if
+	// parsing with the WithMaxAttachmentSize option, beyond which reads
+	// of the Reader fail.
+	MaxSize int64
+
+	// SHA256 is the hex encoded SHA-256 checksum of the decoded content
+	// of the file if parsing with the WithFileChecksums option.
+	SHA256 string
+
+	// Lazy holds the decoded content of the file, in place of Data, if
+	// parsing with the WithLazyFiles option.
+	Lazy *LazyData
+
+	// Caption is the decoded Content-Description of the file, if any,
+	// or the raw description if it cannot be decoded.
+	Caption string
+
+	// Duration is the Content-Duration of an audio or video file, if
+	// any.
+	Duration time.Duration
+
+	// ExternalBody describes the externally stored content of a
+	// message/external-body part, which has a FileType of "external"
+	// and no data.
+	ExternalBody *ExternalBody
+
+	// DecodeError records the error encountered reading the file if
+	// parsing with the WithLenient option. Data may hold the content
+	// decoded before the error occurred.
+	DecodeError error
 }
@@ -0,0 +1,540 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Encoding serialises an Email as an RFC 5322 message. The message is
+// not a byte-for-byte reproduction of a parsed original, but re-parses
+// to an equivalent Email.
+
+// maxLineLen is the length at which header lines are folded and base64
+// content is wrapped (RFC 5322 section 2.1.1, RFC 2045 section 6.8)
+const maxLineLen = 76
+
+// encodedHeader is a header name and its encoded value
+type encodedHeader struct {
+	name, value string
+}
+
+// encodePart is a MIME part to be encoded, either a leaf with a
+// transfer encoded body or a multipart container of parts
+type encodePart struct {
+	header   textproto.MIMEHeader
+	body     []byte
+	boundary string
+	parts    []*encodePart
+	// exact reports if the body is written without a final line
+	// ending, as for unencoded content which must be reproduced exactly
+	exact bool
+}
+
+// Encode writes the email to w as an RFC 5322 message.
+//
+// The headers are written in a stable order, with non-ASCII values
+// encoded as RFC 2047 encoded-words and long lines folded. The trace,
+// address, identification, informational and resent headers are
+// written from their fields, followed by ExtraHeaders sorted by name.
+//
+// The text, enriched text and html bodies are encoded as
+// quoted-printable UTF-8 in a multipart/alternative if more than one is
+// present, together with any calendar invitations. Inline files
+// accompany an html body in a multipart/related, while other files are
+// attached in a multipart/mixed, base64 encoded from their Data with a
+// Content-Type and Content-Disposition derived from their ContentInfo
+// and Name. Enclosed message/rfc822 files are written unencoded, as
+// 7bit, 8bit or binary according to their content. Multipart
+// boundaries are randomly generated. Files referring to external bodies, unhandled parts and
+// the messages of a digest are not encoded.
+//
+// The message's own Content-Language and other Content-* headers, such
+// as Content-Description or Content-ID, are written from
+// ContentLanguage and ExtraHeaders, and those of files from their
+// ContentInfo, Caption, Location and Duration. Content-Length and
+// Content-MD5, which no longer describe the encoded content, and the
+// Content-* headers of the parts of a multipart body other than files,
+// which are not retained when parsing, are not written.
+func Encode(w io.Writer, e *Email) error {
+	headers, err := e.Headers.encodeHeaders()
+	if err != nil {
+		return err
+	}
+	root, err := e.encodeBody()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, h := range headers {
+		b.WriteString(foldHeader(h.name, h.value))
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	e.Headers.encodeContentHeaders(root.header)
+	for _, name := range sortedKeys(root.header) {
+		for _, v := range root.header[name] {
+			b.WriteString(foldHeader(name, v))
+		}
+	}
+	b.WriteString("\r\n")
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	return root.writeBody(w)
+}
+
+// countingWriter counts the bytes written to an io.Writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteTo writes the email to w as an RFC 5322 message using Encode,
+// returning the number of bytes written. It implements io.WriterTo, so
+// that a parsed email may be modified, such as by removing its Files,
+// and written out again.
+func (e *Email) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := Encode(cw, e)
+	return cw.n, err
+}
+
+// encodeHeaders returns the encoded headers of the email in a stable
+// order, excluding the MIME headers, which are added to the header of
+// the root part by encodeContentHeaders.
+func (h *Headers) encodeHeaders() ([]encodedHeader, error) {
+	headers := []encodedHeader{}
+	add := func(name, value string) {
+		if value = sanitizeHeaderValue(value); value != "" {
+			headers = append(headers, encodedHeader{name, value})
+		}
+	}
+	addText := func(name, value string) {
+		add(name, encodeWords(value))
+	}
+	addDate := func(name string, t time.Time) {
+		if !t.IsZero() {
+			add(name, t.Format(time.RFC1123Z))
+		}
+	}
+	addID := func(name, id string) {
+		if id != "" {
+			add(name, "<"+id+">")
+		}
+	}
+	addIDs := func(name string, ids []string) {
+		bracketed := make([]string, len(ids))
+		for i, id := range ids {
+			bracketed[i] = "<" + id + ">"
+		}
+		add(name, strings.Join(bracketed, " "))
+	}
+	addAddress := func(name string, a *mail.Address) {
+		if a != nil {
+			add(name, a.String())
+		}
+	}
+	var err error
+	addList := func(name string) {
+		list, lerr := h.ParsedAddresses(name)
+		if lerr != nil && err == nil {
+			err = fmt.Errorf("cannot encode %s header: %w", name, lerr)
+		}
+		addresses := make([]string, 0, len(list))
+		for _, a := range list {
+			if a != nil {
+				addresses = append(addresses, a.String())
+			}
+		}
+		add(name, strings.Join(addresses, ", "))
+	}
+
+	for _, r := range h.Received {
+		add("Received", r)
+	}
+	addDate("Date", h.Date)
+	addList("From")
+	addAddress("Sender", h.Sender)
+	addList("Reply-To")
+	addList("To")
+	addList("Cc")
+	addList("Bcc")
+	addID("Message-ID", h.MessageID)
+	addIDs("In-Reply-To", h.InReplyTo)
+	addIDs("References", h.References)
+	addText("Subject", h.Subject)
+	for _, c := range strings.Split(h.Comments, "\n") {
+		addText("Comments", c)
+	}
+	addText("Keywords", strings.Join(h.Keywords, ", "))
+	addDate("Resent-Date", h.ResentDate)
+	addList("Resent-From")
+	addAddress("Resent-Sender", h.ResentSender)
+	addList("Resent-To")
+	addList("Resent-Cc")
+	addList("Resent-Bcc")
+	addID("Resent-Message-ID", h.ResentMessageID)
+	addID("Original-Message-ID", h.OriginalMessageID)
+	add("Auto-Submitted", h.AutoSubmitted)
+	if h.ArchivedAt != "" {
+		add("Archived-At", "<"+h.ArchivedAt+">")
+	}
+	addIDs("List-Archive", h.ListArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range sortedKeys(h.ExtraHeaders) {
+		// the MIME headers are derived from the body
+		if name == "Mime-Version" || strings.HasPrefix(name, "Content-") {
+			continue
+		}
+		for _, v := range h.ExtraHeaders[name] {
+			addText(name, v)
+		}
+	}
+	return headers, nil
+}
+
+// droppedContentHeaders are the Content-* headers of a message which
+// are derived from its body when encoding, or no longer describe it.
+var droppedContentHeaders = []string{
+	"Content-Type",
+	"Content-Transfer-Encoding",
+	"Content-Disposition",
+	"Content-Length",
+	"Content-Md5",
+}
+
+// encodeContentHeaders adds the Content-Language and the other Content-*
+// headers of the message held in ExtraHeaders to header, the header of
+// the root part of the body, unless set from a file at the root.
+func (h *Headers) encodeContentHeaders(header textproto.MIMEHeader) {
+	if len(h.ContentLanguage) > 0 && header.Get("Content-Language") == "" {
+		header.Set("Content-Language", strings.Join(h.ContentLanguage, ", "))
+	}
+	for _, name := range sortedKeys(h.ExtraHeaders) {
+		if !strings.HasPrefix(name, "Content-") || slices.Contains(droppedContentHeaders, name) {
+			continue
+		}
+		if header.Get(name) != "" {
+			continue
+		}
+		for _, v := range h.ExtraHeaders[name] {
+			if v = sanitizeHeaderValue(v); v != "" {
+				header.Add(name, encodeWords(v))
+			}
+		}
+	}
+}
+
+// encodeBody returns the root part of the email's body.
+func (e *Email) encodeBody() (*encodePart, error) {
+	bodies := []*encodePart{}
+	for _, body := range []struct{ mediaType, text string }{
+		{"text/plain", e.Text},
+		{"text/enriched", e.EnrichedText},
+		{"text/html", e.HTML},
+	} {
+		if body.text != "" {
+			p, err := newTextPart(body.mediaType, body.text)
+			if err != nil {
+				return nil, err
+			}
+			bodies = append(bodies, p)
+		}
+	}
+
+	inline, attached := []*encodePart{}, []*encodePart{}
+	for _, f := range e.Files {
+		if f == nil || f.ExternalBody != nil {
+			continue
+		}
+		p, err := newFilePart(f)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case f.FileType == "calendar":
+			// an invitation is an alternative to the bodies
+			bodies = append(bodies, p)
+		case e.HTML != "" && f.isRelated():
+			inline = append(inline, p)
+		default:
+			attached = append(attached, p)
+		}
+	}
+
+	var root *encodePart
+	switch len(bodies) {
+	case 0:
+		if len(attached) == 0 && len(inline) == 0 {
+			return newTextPart("text/plain", "")
+		}
+	case 1:
+		root = bodies[0]
+	default:
+		root = newMultipart("multipart/alternative", bodies)
+	}
+	if len(inline) > 0 {
+		root = newMultipart("multipart/related", append([]*encodePart{root}, inline...))
+	}
+	if len(attached) > 0 {
+		if root != nil {
+			attached = append([]*encodePart{root}, attached...)
+		}
+		root = newMultipart("multipart/mixed", attached)
+	}
+	return root, nil
+}
+
+// isRelated reports if the file is inline, or lacks a disposition but
+// has a Content-ID or Content-Location by which an html body may refer
+// to it.
+func (f *File) isRelated() bool {
+	switch {
+	case f.FileType == "inline":
+		return true
+	case f.FileType != "":
+		return false
+	}
+	return f.Location != "" || (f.ContentInfo != nil && f.ContentInfo.ID != "")
+}
+
+// newTextPart returns a quoted-printable encoded UTF-8 text part.
+func newTextPart(mediaType, text string) (*encodePart, error) {
+	var b strings.Builder
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := io.WriteString(qp, text); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return &encodePart{
+		header: textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"})},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		},
+		body: []byte(b.String()),
+	}, nil
+}
+
+// newFilePart returns a base64 encoded file part, reading the content
+// of a file parsed lazily. An enclosed message/rfc822 file is written
+// as it is, since RFC 2046 section 5.2.1 does not permit it to be
+// base64 encoded.
+func newFilePart(f *File) (*encodePart, error) {
+	ci := f.ContentInfo
+	if ci == nil {
+		ci = &ContentInfo{}
+	}
+	mediaType := ci.Type
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	typeParams, dispositionParams := maps.Clone(ci.TypeParams), maps.Clone(ci.DispositionParams)
+	if typeParams == nil {
+		typeParams = map[string]string{}
+	}
+	if dispositionParams == nil {
+		dispositionParams = map[string]string{}
+	}
+	// textual content has been decoded to UTF-8
+	if _, ok := typeParams["charset"]; ok {
+		typeParams["charset"] = "utf-8"
+	}
+
+	// the name of the file replaces any declared names, and is added to
+	// a disposition or to the content type of a file without content
+	// info
+	if _, ok := typeParams["name"]; ok || (ci.Type == "" && f.Name != "") {
+		typeParams["name"] = f.Name
+	}
+	if f.Name != "" {
+		dispositionParams["filename"] = f.Name
+	}
+
+	contentType := mime.FormatMediaType(mediaType, typeParams)
+	if contentType == "" {
+		// an invalid media type
+		contentType = "application/octet-stream"
+	}
+	header := textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	if slices.Contains(contentDispositions, f.FileType) {
+		header.Set("Content-Disposition", mime.FormatMediaType(f.FileType, dispositionParams))
+	}
+	if ci.ID != "" {
+		header.Set("Content-Id", "<"+ci.ID+">")
+	}
+	if f.Caption != "" {
+		header.Set("Content-Description", encodeWords(sanitizeHeaderValue(f.Caption)))
+	}
+	if f.Location != "" {
+		header.Set("Content-Location", f.Location)
+	}
+	if len(ci.Languages) > 0 {
+		header.Set("Content-Language", strings.Join(ci.Languages, ", "))
+	}
+	if f.Duration > 0 {
+		header.Set("Content-Duration", strconv.Itoa(int(f.Duration/time.Second)))
+	}
+
+	data := f.Data
+	if data == nil && f.Lazy != nil {
+		var err error
+		if data, err = f.Lazy.Bytes(); err != nil {
+			return nil, fmt.Errorf("cannot read file %q: %w", f.Name, err)
+		}
+	}
+	if strings.EqualFold(mediaType, "message/rfc822") {
+		header.Set("Content-Transfer-Encoding", identityEncoding(data))
+		return &encodePart{header: header, body: data, exact: true}, nil
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for len(encoded) > maxLineLen {
+		b.WriteString(encoded[:maxLineLen] + "\r\n")
+		encoded = encoded[maxLineLen:]
+	}
+	b.WriteString(encoded)
+	return &encodePart{header: header, body: []byte(b.String())}, nil
+}
+
+// identityEncoding returns the identity Content-Transfer-Encoding
+// describing data: "7bit" for lines of ASCII, "8bit" if any bytes are
+// not ASCII, or "binary" if data holds NULs, bare carriage returns or
+// lines longer than the 998 characters allowed by RFC 5322.
+func identityEncoding(data []byte) string {
+	encoding := "7bit"
+	for line := range bytes.Lines(data) {
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		if len(line) > 998 || bytes.ContainsAny(line, "\x00\r") {
+			return "binary"
+		}
+		if encoding == "7bit" && slices.ContainsFunc(line, func(b byte) bool { return b >= 0x80 }) {
+			encoding = "8bit"
+		}
+	}
+	return encoding
+}
+
+// newMultipart returns a multipart part of the given media type with a
+// random boundary.
+func newMultipart(mediaType string, parts []*encodePart) *encodePart {
+	boundary := randomBoundary()
+	return &encodePart{
+		header: textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType(mediaType, map[string]string{"boundary": boundary})},
+		},
+		boundary: boundary,
+		parts:    parts,
+	}
+}
+
+// writeBody writes the body of the part, including any nested parts,
+// to w.
+func (p *encodePart) writeBody(w io.Writer) error {
+	if p.boundary == "" && p.exact {
+		_, err := w.Write(p.body)
+		return err
+	}
+	if p.boundary == "" {
+		_, err := w.Write(append(p.body, "\r\n"...))
+		return err
+	}
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(p.boundary); err != nil {
+		return err
+	}
+	for _, child := range p.parts {
+		pw, err := mw.CreatePart(child.header)
+		if err != nil {
+			return err
+		}
+		if err := child.writeBody(pw); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// randomBoundary returns a random multipart boundary.
+func randomBoundary() string {
+	var buf [24]byte
+	_, _ = rand.Read(buf[:])
+	return fmt.Sprintf("%x", buf[:])
+}
+
+// encodeWords encodes a header value containing non-ASCII characters
+// as RFC 2047 encoded-words, returning ASCII values unchanged.
+func encodeWords(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return mime.QEncoding.Encode("utf-8", s)
+		}
+	}
+	return s
+}
+
+// sanitizeHeaderValue replaces line breaks in a header value, which
+// would otherwise allow header injection, with spaces.
+func sanitizeHeaderValue(s string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(s))
+}
+
+// foldHeader returns the header line, folded at spaces so that lines
+// do not exceed maxLineLen where possible, terminated by CRLF. Spaces
+// adjoining other whitespace are not folded, as the whitespace around
+// a fold is collapsed when the header is read.
+func foldHeader(name, value string) string {
+	var b strings.Builder
+	b.WriteString(name + ":")
+	lineLen := len(name) + 1
+	prev := name
+	for _, word := range strings.Split(value, " ") {
+		foldable := prev != "" && word != "" && !strings.HasSuffix(prev, "\t") && !strings.HasPrefix(word, "\t")
+		if foldable && lineLen+1+len(word) > maxLineLen {
+			b.WriteString("\r\n")
+			lineLen = 0
+		}
+		b.WriteString(" " + word)
+		lineLen += 1 + len(word)
+		prev = word
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// sortedKeys returns the sorted keys of a header map
+func sortedKeys[M ~map[string][]string](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
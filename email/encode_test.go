@@ -0,0 +1,64 @@
+package email
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFoldHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"Subject", "Short", "Subject: Short\r\n"},
+		{
+			"References",
+			"<first-message-id@example.com> <second-message-id@example.com> <third@example.com>",
+			"References: <first-message-id@example.com> <second-message-id@example.com>\r\n <third@example.com>\r\n",
+		},
+		{
+			// whitespace adjoining a tab is not folded
+			"X-Test",
+			strings.Repeat("a", 60) + "\t " + strings.Repeat("b", 20),
+			"X-Test: " + strings.Repeat("a", 60) + "\t " + strings.Repeat("b", 20) + "\r\n",
+		},
+		{
+			// a long first word is folded onto its own line
+			"Subject",
+			"=?utf-8?q?" + strings.Repeat("x", 63) + "?=",
+			"Subject:\r\n =?utf-8?q?" + strings.Repeat("x", 63) + "?=\r\n",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := foldHeader(tt.name, tt.value), tt.want; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestEncodeWords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Plain subject", "Plain subject"},
+		{"Café", "=?utf-8?q?Caf=C3=A9?="},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := encodeWords(sanitizeHeaderValue(tt.input)), tt.want; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSanitizeHeaderValue(t *testing.T) {
+	if got, want := sanitizeHeaderValue(" Invoice\r\nBcc: victim@example.com\r"), "Invoice Bcc: victim@example.com"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,19 @@
+package email
+
+// ExternalBody describes the content referenced by a
+// message/external-body part (RFC 2046 section 5.2.3), which is stored
+// elsewhere rather than included in the message. The values are those
+// of the Content-Type parameters of the part and are not validated.
+type ExternalBody struct {
+	// AccessType is the lowercased access method, such as "url",
+	// "anon-ftp" or "local-file".
+	AccessType string
+	// URL is the location of the content for the "URL" access type
+	// (RFC 2017) with any whitespace removed.
+	URL string
+	// Size is the size of the content in octets, if provided.
+	Size string
+	// Expiration is the date after which the content may no longer
+	// exist, if provided.
+	Expiration string
+}
@@ -0,0 +1,111 @@
+package email
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// urlAttrs are the attributes of HTML elements holding a URL which may
+// refer to an inline file, such as the src of an img element.
+var urlAttrs = map[string]bool{
+	"src":        true,
+	"href":       true,
+	"background": true,
+	"poster":     true,
+}
+
+// rewriteHTMLURLs returns s, an HTML document, with each URL held in a
+// URL attribute of an element, or in a CSS url() of a style attribute
+// or style element, replaced by the URL returned by fn where fn reports
+// a replacement. Elements which are not altered are returned as
+// written, while altered elements are rendered afresh.
+func rewriteHTMLURLs(s string, fn func(u string) (string, bool)) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	inStyle := false
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := string(z.Raw())
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			inStyle = tt == html.StartTagToken && tok.DataAtom == atom.Style
+			altered := false
+			for i, a := range tok.Attr {
+				var u string
+				var ok bool
+				switch {
+				case a.Namespace != "":
+					continue
+				case a.Key == "style":
+					u, ok = rewriteCSSURLs(a.Val, fn)
+				case urlAttrs[a.Key]:
+					u, ok = fn(strings.TrimSpace(a.Val))
+				}
+				if ok {
+					tok.Attr[i].Val = u
+					altered = true
+				}
+			}
+			if altered {
+				raw = tok.String()
+			}
+		case html.EndTagToken:
+			inStyle = false
+		case html.TextToken:
+			if inStyle {
+				if css, ok := rewriteCSSURLs(raw, fn); ok {
+					raw = css
+				}
+			}
+		}
+		b.WriteString(raw)
+	}
+	return b.String()
+}
+
+// rewriteCSSURLs returns css with the URL of each url() replaced by the
+// URL returned by fn where fn reports a replacement, reporting if any
+// replacement was made. The URL may be quoted, and is passed to fn
+// without quotes or surrounding whitespace.
+func rewriteCSSURLs(css string, fn func(u string) (string, bool)) (string, bool) {
+	var b strings.Builder
+	altered := false
+	for {
+		i := strings.Index(strings.ToLower(css), "url(")
+		if i < 0 {
+			break
+		}
+		i += len("url(")
+		b.WriteString(css[:i])
+		css = css[i:]
+
+		// find the end of the quoted or unquoted url
+		arg := strings.TrimLeft(css, " \t\r\n\f")
+		end := ")"
+		if arg != "" && (arg[0] == '"' || arg[0] == '\'') {
+			end = arg[:1]
+			arg = arg[1:]
+		}
+		j := strings.Index(arg, end)
+		if j < 0 {
+			break
+		}
+		u, ok := fn(strings.TrimSpace(arg[:j]))
+		if !ok {
+			continue
+		}
+		// write any quote, the replacement and the closing quote
+		b.WriteString(css[:len(css)-len(arg)])
+		b.WriteString(u)
+		css = arg[j:]
+		altered = true
+	}
+	b.WriteString(css)
+	return b.String(), altered
+}
@@ -0,0 +1,212 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+)
+
+// JSON helpers encode parsed emails for indexing pipelines.
+
+// jsonWriter writes JSON tokens to an io.Writer, retaining the first
+// error encountered so that writes may be chained.
+type jsonWriter struct {
+	w   io.Writer
+	err error
+}
+
+// raw writes s unaltered
+func (j *jsonWriter) raw(s string) {
+	if j.err != nil {
+		return
+	}
+	_, j.err = io.WriteString(j.w, s)
+}
+
+// value writes the JSON encoding of v
+func (j *jsonWriter) value(v any) {
+	if j.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		j.err = err
+		return
+	}
+	_, j.err = j.w.Write(b)
+}
+
+// base64 streams data as a base64 encoded JSON string. The base64
+// alphabet requires no JSON escaping.
+func (j *jsonWriter) base64(data []byte) {
+	j.base64Reader(bytes.NewReader(data))
+}
+
+// base64Reader streams the content of r as a base64 encoded JSON
+// string.
+func (j *jsonWriter) base64Reader(r io.Reader) {
+	j.raw(`"`)
+	if j.err != nil {
+		return
+	}
+	enc := base64.NewEncoder(base64.StdEncoding, j.w)
+	if _, j.err = io.Copy(enc, r); j.err != nil {
+		return
+	}
+	j.err = enc.Close()
+	j.raw(`"`)
+}
+
+// errorString returns the message of an error, or an empty string if
+// the error is nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// fileFields and emailFields have the fields of File and Email without
+// their methods, for marshalling by encoding/json.
+type (
+	fileFields  File
+	emailFields Email
+)
+
+// omitted shadows a field of an embedded struct so that it is omitted
+// from the marshalled JSON, to be written separately if required.
+type omitted *struct{}
+
+// object writes the JSON object v, less its closing brace, so that
+// further fields may be written. v must marshal to an object with at
+// least one field.
+func (j *jsonWriter) object(v any) {
+	if j.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		j.err = err
+		return
+	}
+	_, j.err = j.w.Write(bytes.TrimSuffix(b, []byte("}")))
+}
+
+// file writes the file as a JSON object, streaming its data as base64,
+// including the data of a file held lazily.
+func (j *jsonWriter) file(f *File) {
+	j.object(struct {
+		*fileFields
+		Reader      omitted `json:",omitempty"`
+		Lazy        omitted `json:",omitempty"`
+		Data        omitted `json:",omitempty"`
+		DecodeError string
+	}{
+		fileFields:  (*fileFields)(f),
+		DecodeError: errorString(f.DecodeError),
+	})
+	j.raw(`,"Data":`)
+	if f.Data == nil && f.Lazy != nil && j.err == nil {
+		r, err := f.Lazy.Open()
+		if err != nil {
+			j.err = err
+			return
+		}
+		j.base64Reader(r)
+		if err := r.Close(); err != nil && j.err == nil {
+			j.err = err
+		}
+	} else {
+		j.base64(f.Data)
+	}
+	j.raw("}")
+}
+
+// files writes the named list of files, preceded by a comma.
+func (j *jsonWriter) files(name string, files []*File) {
+	j.raw(",")
+	j.value(name)
+	j.raw(":[")
+	for i, f := range files {
+		if i > 0 {
+			j.raw(",")
+		}
+		j.file(f)
+	}
+	j.raw("]")
+}
+
+// EncodeJSON encodes the email as a JSON object to w, streaming the
+// data of each file as base64 directly to w to avoid holding a second
+// copy of large attachments in memory, including files held lazily by
+// the WithLazyFiles parser option. The fields are encoded as by
+// encoding/json, except that the file Reader and content info Encoding
+// are omitted, errors are encoded as their messages and ordered parts
+// refer to files by their index in Files.
+func (e *Email) EncodeJSON(w io.Writer) error {
+	type orderedPart struct {
+		Kind      string
+		Text      string `json:",omitempty"`
+		FileIndex *int   `json:",omitempty"`
+	}
+	fileIndex := map[*File]int{}
+	for i, f := range e.Files {
+		fileIndex[f] = i
+	}
+	parts := []orderedPart{}
+	for _, p := range e.OrderedParts {
+		op := orderedPart{Kind: p.Kind, Text: p.Text}
+		if i, ok := fileIndex[p.File]; ok && p.File != nil {
+			op.FileIndex = &i
+		}
+		parts = append(parts, op)
+	}
+	warnings := []string{}
+	for _, w := range e.Warnings {
+		warnings = append(warnings, errorString(w))
+	}
+	type parseError struct {
+		ContentType string
+		Err         string
+	}
+	errs := []parseError{}
+	for _, pe := range e.Errors {
+		errs = append(errs, parseError{pe.ContentType, errorString(pe.Err)})
+	}
+
+	j := &jsonWriter{w: w}
+	j.object(struct {
+		*emailFields
+		Files          omitted `json:",omitempty"`
+		UnhandledParts omitted `json:",omitempty"`
+		SubMessages    omitted `json:",omitempty"`
+		RawMessage     omitted `json:",omitempty"`
+		OrderedParts   []orderedPart
+		Warnings       []string
+		Errors         []parseError
+	}{
+		emailFields:  (*emailFields)(e),
+		OrderedParts: parts,
+		Warnings:     warnings,
+		Errors:       errs,
+	})
+	j.files("Files", e.Files)
+	j.files("UnhandledParts", e.UnhandledParts)
+
+	j.raw(`,"SubMessages":[`)
+	for i, sub := range e.SubMessages {
+		if i > 0 {
+			j.raw(",")
+		}
+		if j.err == nil {
+			j.err = sub.EncodeJSON(w)
+		}
+	}
+	j.raw("]")
+
+	j.raw(`,"RawMessage":`)
+	j.base64(e.RawMessage)
+	j.raw("}")
+	return j.err
+}
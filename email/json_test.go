@@ -0,0 +1,269 @@
+package email
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// failWriter fails after accepting n bytes
+type failWriter struct {
+	n int
+}
+
+func (f *failWriter) Write(p []byte) (int, error) {
+	if len(p) > f.n {
+		return 0, errors.New("write failed")
+	}
+	f.n -= len(p)
+	return len(p), nil
+}
+
+func TestEncodeJSON(t *testing.T) {
+	data := bytes.Repeat([]byte{0, 1, 2, 253, 254, 255}, 1000)
+	file := &File{
+		FileType:    "attachment",
+		Name:        "data.bin",
+		ContentInfo: &ContentInfo{Type: "application/octet-stream"},
+		Data:        data,
+		DecodeError: errors.New("truncated"),
+	}
+	e := &Email{
+		Headers: Headers{
+			Subject: `A "quoted" subject`,
+			From:    []*mail.Address{{Name: "Alice", Address: "alice@example.com"}},
+		},
+		Text:  "Hello",
+		Files: []*File{file},
+		OrderedParts: []OrderedPart{
+			{Kind: OrderedPartText, Text: "Hello"},
+			{Kind: OrderedPartFile, File: file},
+		},
+		Warnings:    []error{errors.New("a warning")},
+		Errors:      []ParseError{{ContentType: "text/plain", Err: errors.New("an error")}},
+		SubMessages: []*Email{{Text: "Enclosed"}},
+	}
+
+	var buf bytes.Buffer
+	if err := e.EncodeJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Headers struct {
+			Subject string
+			From    []*mail.Address
+		}
+		Text  string
+		Files []struct {
+			Name        string
+			DecodeError string
+			Data        []byte
+		}
+		OrderedParts []struct {
+			Kind      string
+			Text      string
+			FileIndex *int
+		}
+		Warnings []string
+		Errors   []struct {
+			ContentType string
+			Err         string
+		}
+		SubMessages []struct {
+			Text string
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if got, want := got.Headers.Subject, e.Headers.Subject; got != want {
+		t.Errorf("got subject %q want %q", got, want)
+	}
+	if got, want := got.Headers.From[0].Address, "alice@example.com"; got != want {
+		t.Errorf("got from %q want %q", got, want)
+	}
+	if got, want := got.Text, "Hello"; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := len(got.Files), 1; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	if !bytes.Equal(got.Files[0].Data, data) {
+		t.Error("file data does not round trip")
+	}
+	if got, want := got.Files[0].DecodeError, "truncated"; got != want {
+		t.Errorf("got decode error %q want %q", got, want)
+	}
+	if got.OrderedParts[0].FileIndex != nil || got.OrderedParts[1].FileIndex == nil || *got.OrderedParts[1].FileIndex != 0 {
+		t.Error("unexpected ordered part file indexes")
+	}
+	if diff := cmp.Diff([]string{"a warning"}, got.Warnings); diff != "" {
+		t.Error(diff)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].ContentType != "text/plain" || got.Errors[0].Err != "an error" {
+		t.Errorf("unexpected errors %v", got.Errors)
+	}
+
+	if len(got.SubMessages) != 1 || got.SubMessages[0].Text != "Enclosed" {
+		t.Errorf("unexpected sub messages %v", got.SubMessages)
+	}
+
+	// write errors are returned
+	if err := e.EncodeJSON(&failWriter{n: 100}); err == nil {
+		t.Error("expected write error")
+	}
+}
+
+// jsonKeys returns the keys encoding/json uses for the exported fields
+// of the struct type t, other than those tagged "-" and those excluded.
+func jsonKeys(t reflect.Type, exclude ...string) []string {
+	keys := []string{}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous || f.Tag.Get("json") == "-" || slices.Contains(exclude, f.Name) {
+			continue
+		}
+		keys = append(keys, f.Name)
+	}
+	return keys
+}
+
+func TestEncodeJSONFields(t *testing.T) {
+	file := &File{
+		Name:         "a.bin",
+		Data:         []byte("data"),
+		Index:        3,
+		Location:     "http://example.com/a.bin",
+		SniffedType:  "image/png",
+		TypeMismatch: true,
+		MaxSize:      100,
+		SHA256:       "abc",
+	}
+	e := &Email{
+		Headers: Headers{
+			RawOrdered: []RawHeader{{Key: "Subject", Value: "Fields"}},
+		},
+		Files:          []*File{file},
+		UnhandledParts: []*File{{Name: "unknown", Data: []byte("raw")}},
+	}
+	var buf bytes.Buffer
+	if err := e.EncodeJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// every field is encoded, so that fields added later are not
+	// silently dropped
+	var got struct {
+		Headers        map[string]json.RawMessage
+		Files          []map[string]json.RawMessage
+		UnhandledParts []map[string]json.RawMessage
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &top); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	for _, tt := range []struct {
+		name    string
+		object  map[string]json.RawMessage
+		typ     reflect.Type
+		exclude []string
+	}{
+		{"email", top, reflect.TypeOf(Email{}), nil},
+		{"headers", got.Headers, reflect.TypeOf(Headers{}), nil},
+		{"file", got.Files[0], reflect.TypeOf(File{}), []string{"Reader", "Lazy"}},
+		{"unhandled part", got.UnhandledParts[0], reflect.TypeOf(File{}), []string{"Reader", "Lazy"}},
+	} {
+		for _, key := range jsonKeys(tt.typ, tt.exclude...) {
+			if _, ok := tt.object[key]; !ok {
+				t.Errorf("%s field %s not encoded", tt.name, key)
+			}
+		}
+	}
+
+	// the values of the fields round trip
+	var decoded struct {
+		Headers struct {
+			RawOrdered []RawHeader
+		}
+		Files []struct {
+			Index        int
+			Location     string
+			SniffedType  string
+			TypeMismatch bool
+			MaxSize      int64
+			SHA256       string
+			Data         []byte
+		}
+		UnhandledParts []struct {
+			Name string
+			Data []byte
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(e.Headers.RawOrdered, decoded.Headers.RawOrdered); diff != "" {
+		t.Error(diff)
+	}
+	f := decoded.Files[0]
+	gotFile := []any{f.Index, f.Location, f.SniffedType, f.TypeMismatch, f.MaxSize, f.SHA256, string(f.Data)}
+	wantFile := []any{3, "http://example.com/a.bin", "image/png", true, int64(100), "abc", "data"}
+	if diff := cmp.Diff(wantFile, gotFile); diff != "" {
+		t.Error(diff)
+	}
+	if got, want := decoded.UnhandledParts[0].Name+" "+string(decoded.UnhandledParts[0].Data), "unknown raw"; got != want {
+		t.Errorf("got unhandled part %q want %q", got, want)
+	}
+}
+
+func TestEncodeJSONLazyFiles(t *testing.T) {
+	spooled := bytes.Repeat([]byte{0, 1, 2, 253, 254, 255}, 1000)
+	path := filepath.Join(t.TempDir(), "spooled")
+	if err := os.WriteFile(path, spooled, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	e := &Email{
+		Files: []*File{
+			{Name: "memory.txt", Lazy: NewLazyData([]byte("in memory"), "", 9)},
+			{Name: "spooled.bin", Lazy: NewLazyData(nil, path, int64(len(spooled)))},
+		},
+	}
+	var buf bytes.Buffer
+	if err := e.EncodeJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Files []struct {
+			Data []byte
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if got, want := string(got.Files[0].Data), "in memory"; got != want {
+		t.Errorf("got data %q want %q", got, want)
+	}
+	if !bytes.Equal(got.Files[1].Data, spooled) {
+		t.Errorf("got %d bytes of spooled data want %d", len(got.Files[1].Data), len(spooled))
+	}
+
+	// closed lazy data cannot be encoded
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.EncodeJSON(io.Discard), ErrLazyDataClosed; !errors.Is(got, want) {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}
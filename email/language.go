@@ -0,0 +1,51 @@
+package email
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// LanguageBody holds the plain text and html bodies of the parts of an
+// email declaring the same Content-Language, such as the alternatives
+// of a multilingual newsletter.
+type LanguageBody struct {
+	// Languages are the language tags of the Content-Language header.
+	Languages []string
+	Text      string
+	HTML      string
+}
+
+// BodyForLanguage returns the plain text and html bodies of the
+// language alternative best matching the language preferences, given in
+// order of priority in the form of Accept-Language values such as "fr-CH"
+// or "en;q=0.8". Language tags are matched following BCP 47, so that a
+// preference for "en-GB" matches an alternative in "en". If no
+// alternative matches, the first alternative is returned, while the
+// Text and HTML bodies are returned if the email declares no languages.
+func (e *Email) BodyForLanguage(prefs []string) (text, html string) {
+	if len(e.LanguageBodies) == 0 {
+		return e.Text, e.HTML
+	}
+
+	// each alternative is represented by its first valid tag, or "und"
+	supported := make([]language.Tag, len(e.LanguageBodies))
+	for i, lb := range e.LanguageBodies {
+		supported[i] = language.Und
+		for _, l := range lb.Languages {
+			if tag, err := language.Parse(l); err == nil {
+				supported[i] = tag
+				break
+			}
+		}
+	}
+
+	desired, _, err := language.ParseAcceptLanguage(strings.Join(prefs, ","))
+	if err != nil || len(desired) == 0 {
+		lb := e.LanguageBodies[0]
+		return lb.Text, lb.HTML
+	}
+	_, i, _ := language.NewMatcher(supported).Match(desired...)
+	lb := e.LanguageBodies[i]
+	return lb.Text, lb.HTML
+}
@@ -0,0 +1,52 @@
+package email
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBodyForLanguage(t *testing.T) {
+	e := &Email{
+		Text: "Hello\n\nBonjour",
+		HTML: "<p>Hello</p><p>Bonjour</p>",
+		LanguageBodies: []LanguageBody{
+			{Languages: []string{"en"}, Text: "Hello", HTML: "<p>Hello</p>"},
+			{Languages: []string{"fr"}, Text: "Bonjour", HTML: "<p>Bonjour</p>"},
+			{Languages: []string{"de-CH"}, Text: "Gruezi"},
+		},
+	}
+
+	tests := []struct {
+		prefs []string
+		text  string
+		html  string
+	}{
+		{[]string{"fr"}, "Bonjour", "<p>Bonjour</p>"},
+		{[]string{"fr-CA"}, "Bonjour", "<p>Bonjour</p>"},
+		{[]string{"en-GB"}, "Hello", "<p>Hello</p>"},
+		{[]string{"ja", "fr"}, "Bonjour", "<p>Bonjour</p>"},
+		{[]string{"en;q=0.5", "fr;q=0.9"}, "Bonjour", "<p>Bonjour</p>"},
+		{[]string{"de-CH"}, "Gruezi", ""},
+		{[]string{"ja"}, "Hello", "<p>Hello</p>"}, // fallback to first
+		{[]string{"und"}, "Hello", "<p>Hello</p>"},
+		{nil, "Hello", "<p>Hello</p>"},
+		{[]string{"!invalid!"}, "Hello", "<p>Hello</p>"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			text, html := e.BodyForLanguage(tt.prefs)
+			if got, want := text, tt.text; got != want {
+				t.Errorf("text got %q want %q", got, want)
+			}
+			if got, want := html, tt.html; got != want {
+				t.Errorf("html got %q want %q", got, want)
+			}
+		})
+	}
+
+	// emails without languages return the bodies
+	e.LanguageBodies = nil
+	if text, html := e.BodyForLanguage([]string{"fr"}); text != e.Text || html != e.HTML {
+		t.Errorf("got %q, %q want the email bodies", text, html)
+	}
+}
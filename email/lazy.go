@@ -0,0 +1,208 @@
+package email
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"sync"
+)
+
+// LazyAddresses holds the raw address list headers of an email, which
+// are parsed on first access, if parsing with the WithLazyAddresses
+// option. This defers the cost of address parsing for consumers that
+// seldom use addresses. It is safe for concurrent use.
+type LazyAddresses struct {
+	mu     sync.Mutex
+	raw    map[string]string
+	parse  func(string) ([]*mail.Address, error)
+	parsed map[string]lazyResult
+}
+
+// lazyResult is the memoised result of parsing an address list header
+type lazyResult struct {
+	addresses []*mail.Address
+	err       error
+}
+
+// NewLazyAddresses returns a LazyAddresses for the raw address list
+// headers, keyed by canonical header name, to be parsed with parse.
+func NewLazyAddresses(raw map[string]string, parse func(string) ([]*mail.Address, error)) *LazyAddresses {
+	return &LazyAddresses{
+		raw:    raw,
+		parse:  parse,
+		parsed: map[string]lazyResult{},
+	}
+}
+
+// get returns the parsed addresses of the named header, parsing it on
+// first access. A missing header returns no addresses.
+func (l *LazyAddresses) get(field string) ([]*mail.Address, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if r, ok := l.parsed[field]; ok {
+		return r.addresses, r.err
+	}
+	var r lazyResult
+	if s := l.raw[field]; s != "" {
+		r.addresses, r.err = l.parse(s)
+	}
+	l.parsed[field] = r
+	return r.addresses, r.err
+}
+
+// ParsedAddresses returns the parsed addresses of the named address list
+// header, such as "To" or "Resent-Cc". If the email was parsed with the
+// WithLazyAddresses option the header is parsed on first access,
+// otherwise the eagerly parsed field is returned.
+func (h *Headers) ParsedAddresses(field string) ([]*mail.Address, error) {
+	field = textproto.CanonicalMIMEHeaderKey(field)
+	if h.LazyAddresses != nil {
+		return h.LazyAddresses.get(field)
+	}
+	switch field {
+	case "From":
+		return h.From, nil
+	case "Reply-To":
+		return h.ReplyTo, nil
+	case "To":
+		return h.To, nil
+	case "Cc":
+		return h.Cc, nil
+	case "Bcc":
+		return h.Bcc, nil
+	case "Resent-From":
+		return h.ResentFrom, nil
+	case "Resent-To":
+		return h.ResentTo, nil
+	case "Resent-Cc":
+		return h.ResentCc, nil
+	case "Resent-Bcc":
+		return h.ResentBcc, nil
+	}
+	return nil, nil
+}
+
+// addressList returns the addresses of the named address list header
+// as ParsedAddresses does, disregarding any error parsing a lazily
+// parsed header, for helpers which derive information from the
+// addresses.
+func (h *Headers) addressList(field string) []*mail.Address {
+	addresses, _ := h.ParsedAddresses(field)
+	return addresses
+}
+
+// ParsedFrom returns the From addresses, parsing them on first access
+// if parsed lazily.
+func (h *Headers) ParsedFrom() ([]*mail.Address, error) { return h.ParsedAddresses("From") }
+
+// ParsedReplyTo returns the Reply-To addresses, parsing them on first
+// access if parsed lazily.
+func (h *Headers) ParsedReplyTo() ([]*mail.Address, error) { return h.ParsedAddresses("Reply-To") }
+
+// ParsedTo returns the To addresses, parsing them on first access if
+// parsed lazily.
+func (h *Headers) ParsedTo() ([]*mail.Address, error) { return h.ParsedAddresses("To") }
+
+// ParsedCc returns the Cc addresses, parsing them on first access if
+// parsed lazily.
+func (h *Headers) ParsedCc() ([]*mail.Address, error) { return h.ParsedAddresses("Cc") }
+
+// ParsedBcc returns the Bcc addresses, parsing them on first access if
+// parsed lazily.
+func (h *Headers) ParsedBcc() ([]*mail.Address, error) { return h.ParsedAddresses("Bcc") }
+
+// ErrLazyDataClosed is returned when opening LazyData which has been
+// closed.
+var ErrLazyDataClosed = errors.New("lazy data closed")
+
+// LazyData holds the decoded content of a file parsed with the
+// WithLazyFiles option, in memory or, if larger than the parser's
+// threshold, spooled to a temporary file, to be read on demand. The
+// content may be read any number of times until it is closed. It is
+// safe for concurrent use.
+type LazyData struct {
+	mu     sync.Mutex
+	data   []byte
+	path   string
+	size   int64
+	closed bool
+}
+
+// NewLazyData returns a LazyData of size bytes held in data, or in the
+// temporary file at path if path is not empty, which is removed by
+// Close.
+func NewLazyData(data []byte, path string, size int64) *LazyData {
+	return &LazyData{data: data, path: path, size: size}
+}
+
+// Size returns the decoded size of the content in bytes.
+func (l *LazyData) Size() int64 {
+	return l.size
+}
+
+// Spooled reports if the content is held in a temporary file.
+func (l *LazyData) Spooled() bool {
+	return l.path != ""
+}
+
+// Open returns a reader of the content from its start, which should
+// be closed after use.
+func (l *LazyData) Open() (io.ReadCloser, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, ErrLazyDataClosed
+	}
+	if l.path == "" {
+		return io.NopCloser(bytes.NewReader(l.data)), nil
+	}
+	return os.Open(l.path)
+}
+
+// Bytes reads the content into memory.
+func (l *LazyData) Bytes() ([]byte, error) {
+	r, err := l.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Close releases the content, removing any temporary file. Closing
+// LazyData more than once has no effect.
+func (l *LazyData) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed, l.data = true, nil
+	if l.path == "" {
+		return nil
+	}
+	return os.Remove(l.path)
+}
+
+// Close releases the LazyData of the files of the email and its
+// SubMessages, removing any temporary files, if parsed with the
+// WithLazyFiles option. The first error encountered is returned.
+func (e *Email) Close() error {
+	var err error
+	for _, f := range e.Files {
+		if f.Lazy != nil {
+			if cerr := f.Lazy.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}
+	for _, sub := range e.SubMessages {
+		if cerr := sub.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package email
+
+import (
+	"net/mail"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// lazyHeaders returns headers with the raw address list headers parsed
+// lazily, as when parsing with WithLazyAddresses, leaving the address
+// fields empty.
+func lazyHeaders(raw map[string]string) Headers {
+	return Headers{LazyAddresses: NewLazyAddresses(raw, mail.ParseAddressList)}
+}
+
+// addressStrings returns the bare addresses of a list
+func addressStrings(list []*mail.Address) []string {
+	s := []string{}
+	for _, a := range list {
+		s = append(s, a.Address)
+	}
+	return s
+}
+
+func TestLazyRecipientCount(t *testing.T) {
+	h := lazyHeaders(map[string]string{
+		"To":  "bob@example.net, carol@example.org",
+		"Cc":  "Bob@example.net",
+		"Bcc": "dave@example.com",
+	})
+	if got, want := h.RecipientCount(), 3; got != want {
+		t.Errorf("got %d want %d", got, want)
+	}
+}
+
+func TestLazyReplyTarget(t *testing.T) {
+	h := lazyHeaders(map[string]string{"From": "alice@example.com"})
+	if got, want := addressStrings(h.ReplyTarget()), []string{"alice@example.com"}; !slices.Equal(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	h = lazyHeaders(map[string]string{"From": "alice@example.com", "Reply-To": "list@example.org"})
+	if got, want := addressStrings(h.ReplyTarget()), []string{"list@example.org"}; !slices.Equal(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestLazyReplyAllRecipients(t *testing.T) {
+	h := lazyHeaders(map[string]string{
+		"From": "alice@example.com",
+		"To":   "me@example.net, bob@example.net",
+		"Cc":   "Alice@example.com, carol@example.org",
+	})
+	got := addressStrings(h.ReplyAllRecipients("me@example.net"))
+	want := []string{"alice@example.com", "bob@example.net", "carol@example.org"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestLazyCanonicalSender(t *testing.T) {
+	h := lazyHeaders(map[string]string{"From": "Alice <Alice@Example.com>"})
+	got := h.CanonicalSender()
+	if got == nil {
+		t.Fatal("expected sender")
+	}
+	if got, want := got.Address, "alice@example.com"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestLazyThreadKey(t *testing.T) {
+	e := &Email{Headers: lazyHeaders(map[string]string{
+		"From": "bob@example.net",
+		"To":   "alice@example.com",
+		"Cc":   "carol@example.com",
+	})}
+	e.Headers.Subject = "Re: Lunch on Friday"
+	if got, want := e.ThreadKey(), "lunch on friday|example.com,example.net"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestLazyEmailString(t *testing.T) {
+	e := &Email{Headers: lazyHeaders(map[string]string{
+		"From": "Alice <alice@example.com>",
+		"To":   "bob@example.net",
+	})}
+	s := e.String()
+	for _, want := range []string{
+		"From:    Alice <alice@example.com>\n",
+		"To:      bob@example.net\n",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("%q does not contain %q", s, want)
+		}
+	}
+}
+
+func TestLazyStructuralSpamSignals(t *testing.T) {
+	e := &Email{Headers: lazyHeaders(map[string]string{"From": "alice@example.com"})}
+	e.Headers.ExtraHeaders = map[string][]string{"Return-Path": {"<bounce@example.net>"}}
+	if !slices.Contains(e.StructuralSpamSignals(), SignalFromReturnPathMismatch) {
+		t.Errorf("got signals %v without %q", e.StructuralSpamSignals(), SignalFromReturnPathMismatch)
+	}
+}
@@ -0,0 +1,181 @@
+package email
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RFC 2231 extended parameters allow long or non-ASCII parameter
+// values, such as attachment filenames, to be split into numbered
+// continuations and percent-encoded in a declared charset:
+//
+//	Content-Disposition: attachment;
+//	    filename*0*=utf-8''%E0%B8%A3%E0%B8%B2%E0%B8%A2;
+//	    filename*1*=%E0%B8%87%E0%B8%B2%E0%B8%99;
+//	    filename*2=.pdf
+//
+// mime.ParseMediaType reassembles such parameters but only decodes the
+// utf-8 and us-ascii charsets, dropping or mangling values in other
+// charsets such as iso-8859-1 or shift_jis.
+
+// extendedSegment is a section of an RFC 2231 extended parameter
+type extendedSegment struct {
+	value   string
+	encoded bool
+}
+
+// splitParams splits the parameters of a header value on semicolons,
+// respecting quoted strings, omitting the leading media type or
+// disposition.
+func splitParams(s string) []string {
+	params := []string{}
+	start, inQuote := -1, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if inQuote {
+				i++
+			}
+		case '"':
+			inQuote = !inQuote
+		case ';':
+			if inQuote {
+				continue
+			}
+			if start >= 0 {
+				params = append(params, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start >= 0 {
+		params = append(params, s[start:])
+	}
+	return params
+}
+
+// unquote removes the quotes and backslash escapes of a quoted string,
+// returning other values unchanged.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// percentDecode decodes the %XX escapes of an RFC 2231 encoded value,
+// leaving malformed escapes as they are.
+func percentDecode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b = append(b, byte(v))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, s[i])
+	}
+	return b
+}
+
+// decodeExtendedParams reassembles and decodes the RFC 2231 extended
+// parameters, such as "filename*" and its numbered continuations, of
+// the Content-Type or Content-Disposition header value s, setting the
+// decoded UTF-8 values in params. Continuations are percent-decoded and
+// joined before decoding using the charset declared in the first
+// section, which may be any charset known to LookupCharset.
+func decodeExtendedParams(s string, params map[string]string) {
+	if !strings.Contains(s, "*") {
+		return
+	}
+	extended := map[string]map[int]extendedSegment{}
+	for _, p := range splitParams(s) {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		name, section, found := strings.Cut(key, "*")
+		if !found || name == "" {
+			continue
+		}
+		seg := extendedSegment{value: unquote(strings.TrimSpace(value))}
+		n := 0
+		switch {
+		case section == "":
+			// name*=charset'language'value
+			seg.encoded = true
+		default:
+			section, seg.encoded = strings.CutSuffix(section, "*")
+			var err error
+			if n, err = strconv.Atoi(section); err != nil || n < 0 {
+				continue
+			}
+		}
+		if extended[name] == nil {
+			extended[name] = map[int]extendedSegment{}
+		}
+		extended[name][n] = seg
+	}
+
+	for name, segments := range extended {
+		first, ok := segments[0]
+		if !ok {
+			continue
+		}
+		charset, value := "", first.value
+		if first.encoded {
+			// the first section declares the charset and language
+			parts := strings.SplitN(first.value, "'", 3)
+			if len(parts) == 3 {
+				charset, value = parts[0], parts[2]
+			}
+		}
+		var b []byte
+		for n := 0; ; n++ {
+			seg, ok := segments[n]
+			if !ok {
+				break
+			}
+			if n > 0 {
+				value = seg.value
+			}
+			if seg.encoded {
+				b = append(b, percentDecode(value)...)
+			} else {
+				b = append(b, value...)
+			}
+		}
+		params[name] = decodeCharset(b, charset)
+	}
+}
+
+// decodeCharset decodes b from the named charset to UTF-8. Content in
+// an unknown or undeclared charset is returned unchanged if it is valid
+// UTF-8, and otherwise decoded as iso-8859-1.
+func decodeCharset(b []byte, label string) string {
+	if enc, _ := LookupCharset(label); enc != nil {
+		if decoded, err := enc.NewDecoder().Bytes(b); err == nil {
+			return string(decoded)
+		}
+	}
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
@@ -0,0 +1,19 @@
+package email
+
+// InlinePGP holds an OpenPGP ASCII armored block found in the plain
+// text body of an email, rather than in a PGP/MIME part, such as a
+// message sent by an older PGP client. The block is neither verified
+// nor decrypted.
+type InlinePGP struct {
+	// Type is the lowercased armor type, such as "message" for an
+	// encrypted message, "signed message" for a clearsigned message or
+	// "public key block".
+	Type string
+	// Block is the armored block including its BEGIN and END lines.
+	// For clearsigned messages this extends to the end of the
+	// signature.
+	Block string
+	// ClearText is the dash-unescaped signed text of a clearsigned
+	// message.
+	ClearText string
+}
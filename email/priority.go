@@ -0,0 +1,12 @@
+package email
+
+// Priority is the normalized priority of a message, reconciled from the
+// Importance, X-MSMail-Priority and X-Priority headers.
+type Priority string
+
+// The normalized priorities of Headers.Priority.
+const (
+	PriorityHigh   Priority = "high"
+	PriorityNormal Priority = "normal"
+	PriorityLow    Priority = "low"
+)
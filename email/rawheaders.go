@@ -0,0 +1,29 @@
+package email
+
+// RawHeader is a header in the order in which it appears in a message,
+// with its value unfolded but not decoded.
+type RawHeader struct {
+	// Key is the canonical form of the header name, such as
+	// "Message-Id".
+	Key string
+	// Value is the unfolded, undecoded header value.
+	Value string
+}
+
+// ExtraHeadersOrdered returns the extra headers, those not stored in
+// their own field, in the order in which they appear in the message,
+// with their values unfolded but not decoded. It relies on RawOrdered,
+// returning nil if it is empty, as it is for an Email not made by the
+// parser.
+func (h *Headers) ExtraHeadersOrdered() []RawHeader {
+	if len(h.RawOrdered) == 0 {
+		return nil
+	}
+	extras := []RawHeader{}
+	for _, rh := range h.RawOrdered {
+		if _, ok := h.ExtraHeaders[rh.Key]; ok {
+			extras = append(extras, rh)
+		}
+	}
+	return extras
+}
@@ -0,0 +1,41 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExtraHeadersOrdered(t *testing.T) {
+	h := Headers{
+		ExtraHeaders: map[string][]string{
+			"X-Mailer":  {"Mutt"},
+			"X-Spam":    {"no", "yes"},
+			"List-Id":   {"<list.example.com>"},
+			"X-Unknown": {"not in raw headers"},
+		},
+		RawOrdered: []RawHeader{
+			{"X-Spam", "no"},
+			{"From", "alice@example.com"},
+			{"List-Id", "<list.example.com>"},
+			{"Subject", "=?utf-8?q?caf=C3=A9?="},
+			{"X-Mailer", "Mutt"},
+			{"X-Spam", "yes"},
+		},
+	}
+	want := []RawHeader{
+		{"X-Spam", "no"},
+		{"List-Id", "<list.example.com>"},
+		{"X-Mailer", "Mutt"},
+		{"X-Spam", "yes"},
+	}
+	if diff := cmp.Diff(want, h.ExtraHeadersOrdered()); diff != "" {
+		t.Error(diff)
+	}
+
+	// without raw headers
+	h.RawOrdered = nil
+	if got := h.ExtraHeadersOrdered(); got != nil {
+		t.Errorf("got %v want nil", got)
+	}
+}
@@ -0,0 +1,32 @@
+package email
+
+import "time"
+
+// ReceivedHeader is a Received trace header parsed into its clauses,
+// for tracing the delivery hops of a message, such as:
+//
+//	Received: from mx.example.com (mx.example.com [192.0.2.1])
+//		by mail.example.net with ESMTPS id 4A1B2C3D
+//		for <alice@example.net>; Tue, 8 Apr 2025 10:00:05 +0000
+//
+// Clauses which are missing or cannot be parsed are left empty.
+type ReceivedHeader struct {
+	// Raw is the unfolded header value.
+	Raw string
+	// From, By, Via, With, ID and For are the values of the respective
+	// clauses, without comments. The angle brackets of the For path
+	// are removed.
+	From string
+	By   string
+	Via  string
+	With string
+	ID   string
+	For  string
+	// Timestamp is the text of the timestamp following the semicolon,
+	// without comments and with whitespace collapsed, retained even if
+	// it cannot be parsed into Date.
+	Timestamp string
+	// Date is the timestamp following the semicolon, or the zero time
+	// if it is missing or invalid.
+	Date time.Time
+}
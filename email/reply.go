@@ -0,0 +1,144 @@
+package email
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Reply helpers separate the new content of a reply from the quoted
+// original message, and detect replies and forwards.
+
+// replyPrefixRegexp matches a leading reply prefix such as "Re:",
+// "RE[2]:" or the German, Swedish and Finnish "AW:", "SV:" and "VS:".
+var replyPrefixRegexp = regexp.MustCompile(`(?i)^(re|aw|sv|vs)\s*(\[\d+\]|\(\d+\))?\s*:`)
+
+// forwardPrefixRegexp matches a leading forward prefix such as "Fwd:",
+// "FW:" or the German "WG:".
+var forwardPrefixRegexp = regexp.MustCompile(`(?i)^(fw|fwd|wg)\s*(\[\d+\]|\(\d+\))?\s*:`)
+
+// subjectLead returns the subject with leading whitespace and bracketed
+// list tags, such as "[golang-nuts]", removed.
+func subjectLead(subject string) string {
+	s := strings.TrimSpace(subject)
+	for {
+		t := strings.TrimSpace(subjectBlobRegexp.ReplaceAllString(s, ""))
+		if t == s {
+			return s
+		}
+		s = t
+	}
+}
+
+// IsReply reports if the email is a reply, being one with an
+// In-Reply-To or References header, or a subject starting with a reply
+// prefix such as "Re:" after any list tags.
+func (e *Email) IsReply() bool {
+	if len(e.Headers.InReplyTo) > 0 || len(e.Headers.References) > 0 {
+		return true
+	}
+	return replyPrefixRegexp.MatchString(subjectLead(e.Headers.Subject))
+}
+
+// IsForward reports if the email is a forward, being one with a subject
+// starting with a forward prefix such as "Fwd:" after any list tags, or
+// one enclosing a message/rfc822 file. Messages forwarded inline without
+// a forward prefix are not detected.
+func (e *Email) IsForward() bool {
+	if forwardPrefixRegexp.MatchString(subjectLead(e.Headers.Subject)) {
+		return true
+	}
+	for _, f := range e.Files {
+		if f.ContentInfo != nil && strings.EqualFold(f.ContentInfo.Type, "message/rfc822") {
+			return true
+		}
+	}
+	return false
+}
+
+// originalMessageRegexp matches the separator used by Outlook and
+// others before the original message.
+var originalMessageRegexp = regexp.MustCompile(`(?i)^-{2,}\s*original message\s*-{2,}$`)
+
+// attributionRegexp matches a complete attribution line introducing a
+// quote, such as "On Tue, 8 Apr 2025 at 10:00, Alice <a@example.com>
+// wrote:", together with the German and French equivalents.
+var attributionRegexp = regexp.MustCompile(`(?i)^(on\s.+\swrote|am\s.+\sschrieb\s.+|le\s.+\sa\s+écrit)\s?:$`)
+
+// attributionStartRegexp matches the start of an attribution which may
+// have been wrapped onto a second line.
+var attributionStartRegexp = regexp.MustCompile(`(?i)^(on|am|le)\s`)
+
+// outlookHeaderRegexp matches the header block fields that Outlook
+// places before an original message without a separator.
+var outlookHeaderRegexp = regexp.MustCompile(`(?i)^(from|sent|date|to|cc|subject)\s*:`)
+
+// SplitReply splits the plain text body of a reply into the new content
+// and the quoted original message, returning the whole text as new
+// content if no quote is found. The heuristics are conservative and
+// recognise the quote boundaries used by common clients:
+//
+//   - an attribution line such as "On <date>, <name> wrote:", possibly
+//     wrapped over two lines (Gmail, Apple Mail, Thunderbird), or the
+//     German "Am ... schrieb ...:" and French "Le ... a écrit :"
+//   - an "-----Original Message-----" separator (Outlook)
+//   - an Outlook header block starting with "From:" followed by "Sent:"
+//     or "Date:" and further header lines
+//   - otherwise, a final block of ">" quoted lines
+//
+// Inline replies interleaving new content and quotes are split at the
+// first quote boundary only.
+func (e *Email) SplitReply() (newContent, quotedOriginal string) {
+	text := strings.ReplaceAll(e.Text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	split := func(i int) (string, string) {
+		return strings.TrimSpace(strings.Join(lines[:i], "\n")),
+			strings.TrimSpace(strings.Join(lines[i:], "\n"))
+	}
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case originalMessageRegexp.MatchString(line):
+			return split(i)
+		case attributionRegexp.MatchString(line):
+			return split(i)
+		case attributionStartRegexp.MatchString(line) && i+1 < len(lines) &&
+			attributionRegexp.MatchString(line+" "+strings.TrimSpace(lines[i+1])):
+			return split(i)
+		case isOutlookHeaderBlock(lines[i:]):
+			return split(i)
+		}
+	}
+
+	// fall back to a final block of quoted lines
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, ">") {
+			break
+		}
+		start = i
+	}
+	if start < 0 {
+		return strings.TrimSpace(text), ""
+	}
+	return split(start)
+}
+
+// isOutlookHeaderBlock reports if the lines start with an Outlook style
+// header block of at least three header lines, commencing with "From:"
+// followed by "Sent:" or "Date:".
+func isOutlookHeaderBlock(lines []string) bool {
+	if len(lines) < 3 || !strings.HasPrefix(strings.ToLower(strings.TrimSpace(lines[0])), "from:") {
+		return false
+	}
+	second := strings.ToLower(strings.TrimSpace(lines[1]))
+	if !strings.HasPrefix(second, "sent:") && !strings.HasPrefix(second, "date:") {
+		return false
+	}
+	return outlookHeaderRegexp.MatchString(strings.TrimSpace(lines[2]))
+}
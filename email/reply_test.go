@@ -0,0 +1,126 @@
+package email
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplitReply(t *testing.T) {
+	tests := []struct {
+		style  string
+		text   string
+		reply  string
+		quoted string
+	}{
+		{
+			style:  "gmail",
+			text:   "Sounds good.\n\nOn Tue, 8 Apr 2025 at 10:00, Alice <alice@example.com> wrote:\n> Lunch on Friday?\n",
+			reply:  "Sounds good.",
+			quoted: "On Tue, 8 Apr 2025 at 10:00, Alice <alice@example.com> wrote:\n> Lunch on Friday?",
+		},
+		{
+			style:  "gmail wrapped attribution",
+			text:   "Sounds good.\r\n\r\nOn Tue, 8 Apr 2025 at 10:00, Alice Long-Name <alice@example.com>\r\nwrote:\r\n\r\n> Lunch on Friday?\r\n",
+			reply:  "Sounds good.",
+			quoted: "On Tue, 8 Apr 2025 at 10:00, Alice Long-Name <alice@example.com>\nwrote:\n\n> Lunch on Friday?",
+		},
+		{
+			style:  "apple mail",
+			text:   "Yes!\n\n> On 8 Apr 2025, at 10:00, Alice <alice@example.com> wrote:\n> \n> Lunch on Friday?\n",
+			reply:  "Yes!",
+			quoted: "> On 8 Apr 2025, at 10:00, Alice <alice@example.com> wrote:\n> \n> Lunch on Friday?",
+		},
+		{
+			style:  "thunderbird",
+			text:   "Fine by me.\n\nOn 4/8/25 10:00 AM, Alice wrote:\n> Lunch on Friday?\n",
+			reply:  "Fine by me.",
+			quoted: "On 4/8/25 10:00 AM, Alice wrote:\n> Lunch on Friday?",
+		},
+		{
+			style:  "outlook separator",
+			text:   "Agreed.\n\n-----Original Message-----\nFrom: Alice\nSent: Tuesday, April 8, 2025 10:00 AM\nSubject: Lunch\n\nLunch on Friday?",
+			reply:  "Agreed.",
+			quoted: "-----Original Message-----\nFrom: Alice\nSent: Tuesday, April 8, 2025 10:00 AM\nSubject: Lunch\n\nLunch on Friday?",
+		},
+		{
+			style:  "outlook header block",
+			text:   "Agreed.\n\nFrom: Alice <alice@example.com>\nSent: Tuesday, April 8, 2025 10:00 AM\nTo: Bob <bob@example.com>\nSubject: Lunch\n\nLunch on Friday?",
+			reply:  "Agreed.",
+			quoted: "From: Alice <alice@example.com>\nSent: Tuesday, April 8, 2025 10:00 AM\nTo: Bob <bob@example.com>\nSubject: Lunch\n\nLunch on Friday?",
+		},
+		{
+			style:  "german",
+			text:   "Gerne.\n\nAm 08.04.2025 um 10:00 schrieb Alice <alice@example.com>:\n> Mittagessen am Freitag?",
+			reply:  "Gerne.",
+			quoted: "Am 08.04.2025 um 10:00 schrieb Alice <alice@example.com>:\n> Mittagessen am Freitag?",
+		},
+		{
+			style:  "french",
+			text:   "Avec plaisir.\n\nLe mar. 8 avr. 2025 à 10:00, Alice <alice@example.com> a écrit :\n> Déjeuner vendredi ?",
+			reply:  "Avec plaisir.",
+			quoted: "Le mar. 8 avr. 2025 à 10:00, Alice <alice@example.com> a écrit :\n> Déjeuner vendredi ?",
+		},
+		{
+			style:  "bare quote",
+			text:   "Works for me.\n\n> Lunch on Friday?\n> At noon?\n",
+			reply:  "Works for me.",
+			quoted: "> Lunch on Friday?\n> At noon?",
+		},
+		{
+			style:  "inline reply not split on quotes",
+			text:   "> Lunch on Friday?\nYes.\n> At noon?\nBetter at one.",
+			reply:  "> Lunch on Friday?\nYes.\n> At noon?\nBetter at one.",
+			quoted: "",
+		},
+		{
+			style:  "no quote",
+			text:   "On reflection, I will come.\nFrom: the office\n",
+			reply:  "On reflection, I will come.\nFrom: the office",
+			quoted: "",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			e := &Email{Text: tt.text}
+			reply, quoted := e.SplitReply()
+			if got, want := reply, tt.reply; got != want {
+				t.Errorf("%s reply got %q want %q", tt.style, got, want)
+			}
+			if got, want := quoted, tt.quoted; got != want {
+				t.Errorf("%s quoted got %q want %q", tt.style, got, want)
+			}
+		})
+	}
+}
+
+func TestIsReplyIsForward(t *testing.T) {
+	rfc822 := &File{ContentInfo: &ContentInfo{Type: "message/rfc822"}}
+	tests := []struct {
+		email   *Email
+		reply   bool
+		forward bool
+	}{
+		{&Email{Headers: Headers{Subject: "Lunch"}}, false, false},
+		{&Email{Headers: Headers{Subject: "Re: Lunch"}}, true, false},
+		{&Email{Headers: Headers{Subject: "AW: Lunch"}}, true, false},
+		{&Email{Headers: Headers{Subject: "[list] RE[2]: Lunch"}}, true, false},
+		{&Email{Headers: Headers{Subject: "Lunch", InReplyTo: []string{"1@example.com"}}}, true, false},
+		{&Email{Headers: Headers{Subject: "Lunch", References: []string{"1@example.com"}}}, true, false},
+		{&Email{Headers: Headers{Subject: "Fwd: Lunch"}}, false, true},
+		{&Email{Headers: Headers{Subject: "WG: Lunch"}}, false, true},
+		{&Email{Headers: Headers{Subject: "Fw: Re: Lunch"}}, false, true},
+		{&Email{Headers: Headers{Subject: "Lunch"}, Files: []*File{rfc822}}, false, true},
+		{&Email{Headers: Headers{Subject: "Regarding lunch"}}, false, false},
+		{&Email{Headers: Headers{Subject: "Forward planning"}}, false, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := tt.email.IsReply(), tt.reply; got != want {
+				t.Errorf("reply got %t want %t", got, want)
+			}
+			if got, want := tt.email.IsForward(), tt.forward; got != want {
+				t.Errorf("forward got %t want %t", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package email
+
+// FeedbackReport holds the machine-readable message/feedback-report
+// part of an Abuse Reporting Format (ARF, RFC 5965) report, sent as a
+// multipart/report with a report-type of "feedback-report". The
+// original message reported is attached as a file.
+type FeedbackReport struct {
+	// FeedbackType is the lowercased type of feedback, such as
+	// "abuse", "fraud" or "virus".
+	FeedbackType string
+	// UserAgent is the name and version of the reporting software.
+	UserAgent string
+	// OriginalMailFrom is the envelope sender of the original message,
+	// stripped of angle brackets.
+	OriginalMailFrom string
+	// SourceIP is the IP address from which the original message was
+	// received.
+	SourceIP string
+	// Fields holds all the fields of the report, keyed by canonical
+	// field name, including those above.
+	Fields map[string][]string
+}
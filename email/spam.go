@@ -0,0 +1,18 @@
+package email
+
+// SpamStatus is the verdict of an upstream SpamAssassin filter, parsed
+// from the X-Spam-Status and X-Spam-Score headers, such as:
+//
+//	X-Spam-Status: Yes, score=5.2 required=5.0 tests=BAYES_99,
+//		HTML_MESSAGE autolearn=no version=3.4.6
+type SpamStatus struct {
+	// Flagged reports if the message was flagged as spam ("Yes").
+	Flagged bool
+	// Score is the spam score of the message, and Required the score
+	// at or above which the filter flags a message as spam.
+	Score    float64
+	Required float64
+	// Tests are the names of the SpamAssassin rules which matched the
+	// message, such as "BAYES_99".
+	Tests []string
+}
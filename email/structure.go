@@ -0,0 +1,56 @@
+package email
+
+import "io"
+
+// PartNode is a node in the MIME tree of an email, describing a part
+// without its content. The root node describes the message itself.
+type PartNode struct {
+	// ContentInfo holds the Content-Type, Content-Disposition and
+	// related information of the part.
+	ContentInfo *ContentInfo
+	// Filename is the name of the part taken from the
+	// Content-Disposition filename or Content-Type name parameters, if
+	// any.
+	Filename string
+	// Size is the size in bytes of the undecoded content of a leaf
+	// part.
+	Size int64
+	// Children are the sub-parts of a multipart part.
+	Children []*PartNode
+}
+
+// Walk calls fn for the node and each of its descendants in depth
+// first order, providing the depth of each node from the root (0).
+func (n *PartNode) Walk(fn func(node *PartNode, depth int)) {
+	var walk func(node *PartNode, depth int)
+	walk = func(node *PartNode, depth int) {
+		fn(node, depth)
+		for _, c := range node.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(n, 0)
+}
+
+// Leaf is a leaf (non-multipart) part of an email with its decoded
+// content, as returned by Parser.ParseLeaves or provided to the func
+// passed to Parser.ParseLeavesFunc.
+type Leaf struct {
+	// Path is the chain of part indexes through the nested multiparts
+	// of the email leading to the part, such as [1 0] for the first
+	// part of the second part of the message. It is empty for a
+	// message which is not multipart.
+	Path []int
+	// ContentInfo holds the Content-Type, Content-Disposition and
+	// related information of the part.
+	ContentInfo *ContentInfo
+	// Reader provides the content of the part, decoded from its
+	// transfer-encoding and, where a charset is given, to UTF-8, to the
+	// func passed to Parser.ParseLeavesFunc. It is only valid for the
+	// duration of the call, and is nil in the leaves returned by
+	// Parser.ParseLeaves.
+	Reader io.Reader `json:"-"`
+	// Data is the decoded content of the part read by
+	// Parser.ParseLeaves.
+	Data []byte
+}
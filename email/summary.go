@@ -0,0 +1,161 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// File categories
+const (
+	FileCategoryImage    = "image"
+	FileCategoryDocument = "document"
+	FileCategoryArchive  = "archive"
+	FileCategoryAudio    = "audio"
+	FileCategoryVideo    = "video"
+	FileCategoryOther    = "other"
+)
+
+// archiveTypes are the media types of common compressed archives
+var archiveTypes = []string{
+	"application/gzip",
+	"application/vnd.rar",
+	"application/x-7z-compressed",
+	"application/x-bzip2",
+	"application/x-gzip",
+	"application/x-rar-compressed",
+	"application/x-tar",
+	"application/x-xz",
+	"application/x-zip-compressed",
+	"application/zip",
+}
+
+// documentTypes are the media types of common documents, in addition
+// to the text types and office document prefixes noted in Category
+var documentTypes = []string{
+	"application/msword",
+	"application/pdf",
+	"application/rtf",
+	"application/vnd.ms-excel",
+	"application/vnd.ms-powerpoint",
+}
+
+// documentPrefixes are the media type prefixes of office documents
+var documentPrefixes = []string{
+	"application/vnd.oasis.opendocument.",
+	"application/vnd.openxmlformats-officedocument.",
+}
+
+// Category returns a coarse category for the file suitable for display,
+// being one of the FileCategory* values, derived from its media type.
+// The InferredType is used for application/octet-stream files if
+// available. The mapping is:
+//
+//   - image/*: image
+//   - audio/*: audio
+//   - video/*: video
+//   - text/*, PDF, RTF and Microsoft Office and OpenDocument formats:
+//     document
+//   - zip, gzip, tar, bzip2, xz, 7z and rar: archive
+//   - anything else: other
+func (f *File) Category() string {
+	if f.ContentInfo == nil {
+		return FileCategoryOther
+	}
+	t := strings.ToLower(f.ContentInfo.Type)
+	if t == "application/octet-stream" && f.InferredType != "" {
+		t = strings.ToLower(f.InferredType)
+	}
+	switch {
+	case strings.HasPrefix(t, "image/"):
+		return FileCategoryImage
+	case strings.HasPrefix(t, "audio/"):
+		return FileCategoryAudio
+	case strings.HasPrefix(t, "video/"):
+		return FileCategoryVideo
+	case strings.HasPrefix(t, "text/"), inSlice(documentTypes, t):
+		return FileCategoryDocument
+	case inSlice(archiveTypes, t):
+		return FileCategoryArchive
+	}
+	for _, p := range documentPrefixes {
+		if strings.HasPrefix(t, p) {
+			return FileCategoryDocument
+		}
+	}
+	return FileCategoryOther
+}
+
+// FileSummary counts the files of the email by Category, for display
+// such as "3 images, 2 documents", and separately by FileType in
+// dispositions, counting "inline" and "attachment" files. Only non-zero
+// counts are included. For example:
+//
+//	categories: map[string]int{"image": 3, "document": 2}
+//	dispositions: map[string]int{"inline": 3, "attachment": 2}
+func (e *Email) FileSummary() (categories, dispositions map[string]int) {
+	categories, dispositions = map[string]int{}, map[string]int{}
+	for _, f := range e.Files {
+		if f == nil {
+			continue
+		}
+		categories[f.Category()]++
+		switch f.FileType {
+		case "inline", "attachment":
+			dispositions[f.FileType]++
+		}
+	}
+	return categories, dispositions
+}
+
+// summaryAddresses renders addresses for String as "Name <address>",
+// without the RFC 2047 encoding of mail.Address.String.
+func summaryAddresses(list []*mail.Address) string {
+	parts := []string{}
+	for _, a := range list {
+		switch {
+		case a == nil:
+			continue
+		case a.Name != "":
+			parts = append(parts, a.Name+" <"+a.Address+">")
+		default:
+			parts = append(parts, a.Address)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String returns a compact multiline summary of the email for logging
+// and debugging, implementing fmt.Stringer. The date, senders,
+// recipients and subject are followed by counts of the files and
+// enclosed messages and the lengths of the bodies. No body content or
+// file data is included. For example:
+//
+//	Date:    Tue, 08 Apr 2025 10:00:00 +0000
+//	From:    Alice <alice@example.com>
+//	To:      Bob <bob@example.net>
+//	Subject: Lunch on Friday
+//	Files:   3 (2 inline, 1 attachment)
+//	Body:    120 text chars, 480 html chars
+func (e *Email) String() string {
+	if e == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	date := ""
+	if !e.Headers.Date.IsZero() {
+		date = e.Headers.Date.Format(time.RFC1123Z)
+	}
+	fmt.Fprintf(&b, "Date:    %s\n", date)
+	fmt.Fprintf(&b, "From:    %s\n", summaryAddresses(e.Headers.addressList("From")))
+	fmt.Fprintf(&b, "To:      %s\n", summaryAddresses(e.Headers.addressList("To")))
+	fmt.Fprintf(&b, "Subject: %s\n", strings.Join(strings.Fields(e.Headers.Subject), " "))
+	_, dispositions := e.FileSummary()
+	fmt.Fprintf(&b, "Files:   %d (%d inline, %d attachment)\n", len(e.Files), dispositions["inline"], dispositions["attachment"])
+	if len(e.SubMessages) > 0 {
+		fmt.Fprintf(&b, "Messages: %d enclosed\n", len(e.SubMessages))
+	}
+	fmt.Fprintf(&b, "Body:    %d text chars, %d html chars", e.TextLen(), e.HTMLLen())
+	return b.String()
+}
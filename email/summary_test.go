@@ -0,0 +1,115 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileCategory(t *testing.T) {
+	tests := []struct {
+		contentType  string
+		inferredType string
+		want         string
+	}{
+		{"image/png", "", FileCategoryImage},
+		{"IMAGE/JPEG", "", FileCategoryImage},
+		{"audio/mpeg", "", FileCategoryAudio},
+		{"video/mp4", "", FileCategoryVideo},
+		{"application/pdf", "", FileCategoryDocument},
+		{"text/csv", "", FileCategoryDocument},
+		{"application/msword", "", FileCategoryDocument},
+		{"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "", FileCategoryDocument},
+		{"application/vnd.oasis.opendocument.spreadsheet", "", FileCategoryDocument},
+		{"application/zip", "", FileCategoryArchive},
+		{"application/x-7z-compressed", "", FileCategoryArchive},
+		{"application/gzip", "", FileCategoryArchive},
+		{"application/octet-stream", "", FileCategoryOther},
+		{"application/octet-stream", "image/gif", FileCategoryImage},
+		{"application/pgp-signature", "", FileCategoryOther},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f := &File{ContentInfo: &ContentInfo{Type: tt.contentType}, InferredType: tt.inferredType}
+			if got, want := f.Category(), tt.want; got != want {
+				t.Errorf("got %s want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestFileSummary(t *testing.T) {
+	file := func(fileType, contentType string) *File {
+		return &File{FileType: fileType, ContentInfo: &ContentInfo{Type: contentType}}
+	}
+	e := &Email{
+		Files: []*File{
+			file("inline", "image/png"),
+			file("inline", "image/jpeg"),
+			file("attachment", "image/gif"),
+			file("attachment", "application/pdf"),
+			file("attachment", "text/csv"),
+			file("external", "message/external-body"),
+			{FileType: "attachment"},
+		},
+	}
+	categories, dispositions := e.FileSummary()
+	wantCategories := map[string]int{
+		"image":    3,
+		"document": 2,
+		"other":    2,
+	}
+	if diff := cmp.Diff(wantCategories, categories); diff != "" {
+		t.Error(diff)
+	}
+	wantDispositions := map[string]int{
+		"inline":     2,
+		"attachment": 4,
+	}
+	if diff := cmp.Diff(wantDispositions, dispositions); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestEmailString(t *testing.T) {
+	date, err := mail.ParseDate("Tue, 8 Apr 2025 10:00:00 +0000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Email{
+		Headers: Headers{
+			Date:    date,
+			From:    []*mail.Address{{Name: "Zoë", Address: "zoe@example.com"}},
+			To:      []*mail.Address{{Name: "Bob", Address: "bob@example.net"}, {Address: "carol@example.org"}},
+			Subject: "Lunch\r\n on Friday",
+		},
+		Text: "Lunch?",
+		HTML: "<p>Lunch?</p>",
+		Files: []*File{
+			{FileType: "inline", ContentInfo: &ContentInfo{Type: "image/png"}, Data: []byte("secret image data")},
+			{FileType: "attachment", ContentInfo: &ContentInfo{Type: "application/pdf"}, Data: []byte("secret pdf data")},
+		},
+		SubMessages: []*Email{{}},
+	}
+	want := `Date:    Tue, 08 Apr 2025 10:00:00 +0000
+From:    Zoë <zoe@example.com>
+To:      Bob <bob@example.net>, carol@example.org
+Subject: Lunch on Friday
+Files:   2 (1 inline, 1 attachment)
+Messages: 1 enclosed
+Body:    6 text chars, 13 html chars`
+	if diff := cmp.Diff(want, e.String()); diff != "" {
+		t.Errorf("unexpected summary (-want +got):\n%s", diff)
+	}
+	if got := fmt.Sprintf("%v", e); strings.Contains(got, "secret") || strings.Contains(got, "Lunch?") {
+		t.Errorf("summary includes content: %s", got)
+	}
+
+	var nilEmail *Email
+	if got, want := nilEmail.String(), "<nil>"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
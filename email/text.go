@@ -0,0 +1,148 @@
+package email
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Text helpers provide plain text renderings of email bodies.
+
+// whitespaceRegexp matches runs of whitespace
+var whitespaceRegexp = regexp.MustCompile(`\s+`)
+
+// multiNewlineRegexp matches three or more newlines
+var multiNewlineRegexp = regexp.MustCompile(`\n{3,}`)
+
+// HTMLToText renders HTML as plain text. Tags are stripped, entities
+// decoded and whitespace collapsed, while script, style and head
+// content is removed. Line breaks and block elements such as
+// paragraphs and headings are rendered as newlines, list items are
+// prefixed with "* " and links are followed by their target in
+// parentheses where this differs from the link text.
+func HTMLToText(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+
+	skipDepth := 0 // depth of elements whose content is skipped
+	href, linkText := "", ""
+	inLink := false
+
+	write := func(t string) {
+		b.WriteString(t)
+		if inLink {
+			linkText += t
+		}
+	}
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		tok := z.Token()
+		switch tt {
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			// newlines in html text are insignificant
+			text := whitespaceRegexp.ReplaceAllString(tok.Data, " ")
+			write(text)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch tok.DataAtom {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				if tt == html.StartTagToken {
+					skipDepth++
+				}
+			case atom.Br:
+				write("\n")
+			case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+				atom.Blockquote, atom.Table, atom.Ul, atom.Ol, atom.Pre:
+				write("\n\n")
+			case atom.Div, atom.Tr, atom.Hr:
+				write("\n")
+			case atom.Li:
+				write("\n* ")
+			case atom.Td, atom.Th:
+				write(" ")
+			case atom.A:
+				for _, a := range tok.Attr {
+					if a.Key == "href" {
+						href = strings.TrimSpace(a.Val)
+					}
+				}
+				inLink, linkText = true, ""
+			}
+
+		case html.EndTagToken:
+			switch tok.DataAtom {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				if skipDepth > 0 {
+					skipDepth--
+				}
+			case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+				atom.Blockquote, atom.Table, atom.Ul, atom.Ol, atom.Pre:
+				write("\n\n")
+			case atom.Div, atom.Tr:
+				write("\n")
+			case atom.A:
+				inLink = false
+				if href != "" && !strings.HasPrefix(href, "#") && href != strings.TrimSpace(linkText) {
+					b.WriteString(" (" + href + ")")
+				}
+				href, linkText = "", ""
+			}
+		}
+	}
+
+	// tidy whitespace around and between lines
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	text := strings.Join(lines, "\n")
+	text = multiNewlineRegexp.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// BodyText returns the text to show as the body of the email. If
+// preferHTML is true and the email has an HTML body, the plain text
+// rendering of the HTML is returned. Otherwise the plain text body is
+// returned, falling back to the rendered HTML body and finally the
+// enriched text body if no plain text body is present.
+func (e *Email) BodyText(preferHTML bool) string {
+	switch {
+	case preferHTML && e.HTML != "":
+		return HTMLToText(e.HTML)
+	case e.Text != "":
+		return e.Text
+	case e.HTML != "":
+		return HTMLToText(e.HTML)
+	}
+	return e.EnrichedText
+}
+
+// TextLen returns the number of characters (runes, not bytes) of the
+// decoded plain text body, without copying it.
+//
+// The count is not cached: each call counts the runes of the body
+// afresh, taking time proportional to its length. Text is an exported
+// field which may be changed after parsing, so that a count cached
+// when the email is parsed could be stale. Callers needing the count
+// repeatedly, such as for a reading time estimate, should retain it.
+func (e *Email) TextLen() int {
+	return utf8.RuneCountInString(e.Text)
+}
+
+// HTMLLen returns the number of characters (runes, not bytes) of the
+// decoded HTML body, including markup, without copying it. As with
+// TextLen, the count is not cached, taking time proportional to the
+// length of the body on each call.
+func (e *Email) HTMLLen() int {
+	return utf8.RuneCountInString(e.HTML)
+}
@@ -0,0 +1,109 @@
+package email
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		html string
+		text string
+	}{
+		{
+			html: `<div dir="ltr"><div>Pictures of cats!</div><img src="cid:ii_m6s9zyhs0" alt="cat2.png"><br></div>`,
+			text: "Pictures of cats!",
+		},
+		{
+			html: "<html><head><title>Title</title><style>p {color: red}</style></head>" +
+				"<body><p>The quick   brown\nfox &amp; the lazy dog.</p><p>Second<br>line</p>" +
+				"<script>alert('hi')</script></body></html>",
+			text: "The quick brown fox & the lazy dog.\n\nSecond\nline",
+		},
+		{
+			html: `<ul><li>one</li><li>two</li></ul><p>See <a href="https://example.com">the site</a>` +
+				` or <a href="https://example.com">https://example.com</a>.</p>`,
+			text: "* one\n* two\n\nSee the site (https://example.com) or https://example.com.",
+		},
+		{
+			html: "",
+			text: "",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := HTMLToText(tt.html), tt.text; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestBodyText(t *testing.T) {
+	tests := []struct {
+		email      *Email
+		preferHTML bool
+		text       string
+	}{
+		{
+			email:      &Email{Text: "plain", HTML: "<p>html</p>"},
+			preferHTML: false,
+			text:       "plain",
+		},
+		{
+			email:      &Email{Text: "plain", HTML: "<p>html</p>"},
+			preferHTML: true,
+			text:       "html",
+		},
+		{
+			email:      &Email{Text: "plain"},
+			preferHTML: true,
+			text:       "plain",
+		},
+		{
+			email:      &Email{HTML: "<p>html</p>"},
+			preferHTML: false,
+			text:       "html",
+		},
+		{
+			email:      &Email{EnrichedText: "<bold>enriched</bold>"},
+			preferHTML: true,
+			text:       "<bold>enriched</bold>",
+		},
+		{
+			email:      &Email{},
+			preferHTML: true,
+			text:       "",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := tt.email.BodyText(tt.preferHTML), tt.text; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestBodyLen(t *testing.T) {
+	tests := []struct {
+		email   *Email
+		textLen int
+		htmlLen int
+	}{
+		{email: &Email{}, textLen: 0, htmlLen: 0},
+		{email: &Email{Text: "plain", HTML: "<p>html</p>"}, textLen: 5, htmlLen: 11},
+		{email: &Email{Text: "Grüße", HTML: "<p>שלום</p>"}, textLen: 5, htmlLen: 11},
+		{email: &Email{Text: "猫の写真 🐈"}, textLen: 6, htmlLen: 0},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := tt.email.TextLen(), tt.textLen; got != want {
+				t.Errorf("text len got %d want %d", got, want)
+			}
+			if got, want := tt.email.HTMLLen(), tt.htmlLen; got != want {
+				t.Errorf("html len got %d want %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package email
+
+import (
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Threading helpers provide approximate message grouping for use when
+// proper In-Reply-To and References headers are absent.
+
+// subjectPrefixRegexp matches a leading reply or forward prefix such as
+// "Re:", "RE[2]:", "Fwd:" or the common non-English "AW:", "WG:", "SV:"
+// and "VS:" variants.
+var subjectPrefixRegexp = regexp.MustCompile(`(?i)^(re|fw|fwd|aw|wg|sv|vs)\s*(\[\d+\]|\(\d+\))?\s*:`)
+
+// subjectBlobRegexp matches a leading bracketed "blob", such as a
+// mailing list tag like "[golang-nuts]".
+var subjectBlobRegexp = regexp.MustCompile(`^\[[^\[\]]*\]`)
+
+// subjectTrailerRegexp matches a trailing "(fwd)".
+var subjectTrailerRegexp = regexp.MustCompile(`(?i)\(fwd\)$`)
+
+// BaseSubject returns the subject with reply and forward prefixes,
+// leading list tags and trailing "(fwd)" markers removed, and with
+// whitespace collapsed, loosely following the base subject algorithm of
+// RFC 5256 section 2.1. For example "Re: [list] Fwd: Lunch (fwd)"
+// returns "Lunch".
+func (h *Headers) BaseSubject() string {
+	s := strings.Join(strings.Fields(h.Subject), " ")
+	for {
+		last := s
+		s = strings.TrimSpace(subjectTrailerRegexp.ReplaceAllString(s, ""))
+		s = strings.TrimSpace(subjectPrefixRegexp.ReplaceAllString(s, ""))
+		// only remove a blob if something remains after it
+		if t := strings.TrimSpace(subjectBlobRegexp.ReplaceAllString(s, "")); t != "" {
+			s = t
+		}
+		if s == last {
+			return s
+		}
+	}
+}
+
+// ThreadKey returns an opinionated key for grouping messages into
+// threads in the style of Gmail, combining the normalised subject with
+// the participants of the message.
+//
+// The key is composed of the lowercased BaseSubject, followed by a "|"
+// separator and the sorted, de-duplicated, lowercased domains of the
+// From, To and Cc addresses joined by ",". For example:
+//
+//	lunch on friday|example.com,example.net
+func (e *Email) ThreadKey() string {
+	domains := []string{}
+	h := &e.Headers
+	for _, list := range [][]*mail.Address{h.addressList("From"), h.addressList("To"), h.addressList("Cc")} {
+		for _, a := range list {
+			if a == nil {
+				continue
+			}
+			_, domain, ok := strings.Cut(a.Address, "@")
+			if !ok || domain == "" {
+				continue
+			}
+			domains = append(domains, strings.ToLower(domain))
+		}
+	}
+	slices.Sort(domains)
+	domains = slices.Compact(domains)
+	return strings.ToLower(e.Headers.BaseSubject()) + "|" + strings.Join(domains, ",")
+}
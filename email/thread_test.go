@@ -0,0 +1,56 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"testing"
+)
+
+func TestBaseSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		base    string
+	}{
+		{"Lunch", "Lunch"},
+		{"Re: Lunch", "Lunch"},
+		{"RE: re: Fwd: Lunch", "Lunch"},
+		{"Re[2]: Lunch", "Lunch"},
+		{"AW: WG: Lunch", "Lunch"},
+		{"[golang-nuts] Re: Lunch (fwd)", "Lunch"},
+		{"Re:   Lunch    on  Friday", "Lunch on Friday"},
+		{"[only a tag]", "[only a tag]"},
+		{"", ""},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			h := &Headers{Subject: tt.subject}
+			if got, want := h.BaseSubject(), tt.base; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestThreadKey(t *testing.T) {
+	e1 := &Email{
+		Headers: Headers{
+			Subject: "Lunch on Friday",
+			From:    []*mail.Address{{Address: "alice@Example.com"}},
+			To:      []*mail.Address{{Address: "bob@example.net"}},
+		},
+	}
+	e2 := &Email{
+		Headers: Headers{
+			Subject: "Re: lunch on friday",
+			From:    []*mail.Address{{Address: "bob@example.net"}},
+			To:      []*mail.Address{{Address: "alice@example.com"}},
+			Cc:      []*mail.Address{{Address: "carol@example.com"}},
+		},
+	}
+	want := "lunch on friday|example.com,example.net"
+	for i, e := range []*Email{e1, e2} {
+		if got := e.ThreadKey(); got != want {
+			t.Errorf("email %d got %q want %q", i, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package letters_test // package test
 
 import (
+	"errors"
 	"net/mail"
 	"os"
 	"testing"
@@ -25,11 +26,20 @@ func testEmailFromFile(t *testing.T, fp string, expectedEmail *email.Email) {
 		return
 	}
 	got, want := parsedEmail, expectedEmail
+	// the raw headers in their original order are covered by the parser
+	// package tests
 	if diff := cmp.Diff(
 		want,
 		got,
+		cmpopts.IgnoreFields(email.Headers{}, "RawOrdered"),
 		cmpopts.IgnoreFields(email.File{}, "Reader"),
 		cmpopts.IgnoreFields(email.ContentInfo{}, "Encoding", "encDone"),
+		cmp.Comparer(func(a, b error) bool {
+			if a == nil || b == nil {
+				return a == b
+			}
+			return a.Error() == b.Error()
+		}),
 	); diff != "" {
 		t.Errorf("emails are not equal\n%s", diff)
 	}
@@ -70,6 +80,7 @@ func TestParseEmailEnglishEmpty(t *testing.T) {
 				Charset:           "",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "While this email is undeliverable, this test case makes sure that the\n" +
 			"parser does not crash, most fields are nullable, and the rest has sane\n" +
@@ -172,6 +183,7 @@ func TestParseEmailEnglishNoTextContent(t *testing.T) {
 				Charset:          "",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "",
 		EnrichedText: "",
@@ -199,6 +211,7 @@ func TestParseEmailEnglishNoTextContent(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 0,
 			},
 		},
 	}
@@ -301,6 +314,7 @@ func TestParseEmailHeadersEnglishPlaintextAsciiOver7bit(t *testing.T) {
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -412,6 +426,7 @@ func TestParseEmailEnglishPlaintextAsciiOver7bit(t *testing.T) {
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -562,6 +577,7 @@ func TestParseEmailEnglishPlaintextAsciiOverBase64(t *testing.T) {
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -712,6 +728,7 @@ func TestParseEmailEnglishPlaintextAsciiOverQuotedprintable(t *testing.T) {
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -862,6 +879,7 @@ func TestParseEmailEnglishPlaintextUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -1012,6 +1030,7 @@ func TestParseEmailEnglishPlaintextUtf8OverBase64(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -1162,6 +1181,7 @@ func TestParseEmailEnglishPlaintextUtf8OverQuotedprintable(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -1313,6 +1333,7 @@ func TestParseEmailEnglishMultipartRelatedAsciiOver7bit(t *testing.T) {
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -1354,19 +1375,22 @@ func TestParseEmailEnglishMultipartRelatedAsciiOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -1502,10 +1526,12 @@ func TestParseEmailEnglishMultipartRelatedAsciiOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -1547,18 +1573,24 @@ func TestParseEmailEnglishMultipartRelatedAsciiOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -1695,10 +1727,12 @@ func TestParseEmailEnglishMultipartRelatedAsciiOverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -1740,18 +1774,24 @@ func TestParseEmailEnglishMultipartRelatedAsciiOverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -1892,6 +1932,7 @@ func TestParseEmailEnglishMultipartRelatedUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -1933,20 +1974,22 @@ func TestParseEmailEnglishMultipartRelatedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -2082,10 +2125,12 @@ func TestParseEmailEnglishMultipartRelatedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -2127,19 +2172,24 @@ func TestParseEmailEnglishMultipartRelatedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -2276,10 +2326,12 @@ func TestParseEmailEnglishMultipartRelatedUtf8OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -2321,19 +2373,24 @@ func TestParseEmailEnglishMultipartRelatedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -2474,6 +2531,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -2513,16 +2571,16 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -2538,16 +2596,16 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2563,7 +2621,6 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -2571,6 +2628,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -2584,7 +2642,6 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "ascii",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -2592,6 +2649,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2607,11 +2665,11 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2634,6 +2692,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2656,9 +2715,13 @@ func TestParseEmailEnglishMultipartMixedAsciiOver7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -2794,10 +2857,12 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -2837,16 +2902,16 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -2862,16 +2927,16 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2887,7 +2952,6 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -2895,6 +2959,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -2908,7 +2973,6 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "ascii",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -2916,6 +2980,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2931,11 +2996,11 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2958,6 +3023,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -2980,8 +3046,15 @@ func TestParseEmailEnglishMultipartMixedAsciiOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -3118,10 +3191,12 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -3161,16 +3236,16 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -3186,16 +3261,16 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3211,7 +3286,6 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -3219,6 +3293,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -3232,7 +3307,6 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "ascii",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -3240,6 +3314,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3255,11 +3330,11 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3282,6 +3357,7 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3304,8 +3380,15 @@ func TestParseEmailEnglishMultipartMixedAsciiOverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -3446,6 +3529,7 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -3485,17 +3569,16 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -3511,17 +3594,16 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3537,7 +3619,6 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -3545,6 +3626,7 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -3558,7 +3640,6 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -3566,6 +3647,7 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3581,11 +3663,11 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3608,6 +3690,7 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3630,9 +3713,13 @@ func TestParseEmailEnglishMultipartMixedUtf8Over7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -3768,10 +3855,12 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -3811,17 +3900,16 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -3837,17 +3925,16 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3863,7 +3950,6 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -3871,6 +3957,7 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -3884,7 +3971,6 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -3892,6 +3978,7 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3907,11 +3994,11 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3934,6 +4021,7 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -3956,8 +4044,15 @@ func TestParseEmailEnglishMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -4094,10 +4189,12 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -4137,17 +4234,16 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -4163,17 +4259,16 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -4189,7 +4284,6 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -4197,6 +4291,7 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -4210,7 +4305,6 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -4218,6 +4312,7 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -4233,11 +4328,11 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -4260,6 +4355,7 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -4282,8 +4378,15 @@ func TestParseEmailEnglishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -4426,6 +4529,7 @@ func TestParseEmailEnglishMultipartSignedAsciiOver7bit(t *testing.T) {
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -4451,24 +4555,18 @@ func TestParseEmailEnglishMultipartSignedAsciiOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					226, 128, 154, 28, 226, 128, 161, 226, 128, 158, 117, 46, 197, 189, 18, 97, 197,
-					146, 126, 195, 187, 197, 184, 195, 129, 195, 135, 25, 58, 195, 159, 194, 189, 194,
-					185, 195, 163, 195, 175, 197, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80,
-					194, 165, 195, 188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239,
-					191, 189, 197, 184, 29, 195, 182, 19, 195, 171, 226, 128, 166, 80, 194, 165, 195,
-					188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197,
-					184, 29, 195, 182, 19, 195, 170, 49, 195, 187, 195, 174, 127, 7, 28, 104, 33, 195,
-					136, 120, 71, 82, 195, 168, 195, 161, 38, 30, 195, 185, 195, 170, 195, 150, 195,
-					129, 195, 180, 113, 226, 128, 156, 194, 173, 195, 187, 195, 155, 197, 190, 57, 195,
-					188, 28, 113, 226, 128, 156, 194, 173, 195, 187, 195, 161, 38, 24, 195, 135, 195,
-					175, 194, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 195, 187,
-					195, 167, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197, 184, 29, 195, 182,
-					19, 195, 170, 8, 114, 30, 17, 195, 148, 194, 186, 58, 95, 195, 136, 94, 59, 26, 18,
-					6, 124, 119, 195, 152, 79, 194, 174, 21, 65, 194, 185, 195, 163, 195, 175, 197, 190,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
 	}
@@ -4609,10 +4707,12 @@ func TestParseEmailEnglishMultipartSignedAsciiOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -4638,26 +4738,23 @@ func TestParseEmailEnglishMultipartSignedAsciiOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					226, 128, 154, 28, 226, 128, 161, 226, 128, 158, 117, 46, 197, 189, 18, 97, 197,
-					146, 126, 195, 187, 197, 184, 195, 129, 195, 135, 25, 58, 195, 159, 194, 189, 194,
-					185, 195, 163, 195, 175, 197, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80,
-					194, 165, 195, 188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239,
-					191, 189, 197, 184, 29, 195, 182, 19, 195, 171, 226, 128, 166, 80, 194, 165, 195,
-					188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197,
-					184, 29, 195, 182, 19, 195, 170, 49, 195, 187, 195, 174, 127, 7, 28, 104, 33, 195,
-					136, 120, 71, 82, 195, 168, 195, 161, 38, 30, 195, 185, 195, 170, 195, 150, 195,
-					129, 195, 180, 113, 226, 128, 156, 194, 173, 195, 187, 195, 155, 197, 190, 57, 195,
-					188, 28, 113, 226, 128, 156, 194, 173, 195, 187, 195, 161, 38, 24, 195, 135, 195,
-					175, 194, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 195, 187,
-					195, 167, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197, 184, 29, 195, 182,
-					19, 195, 170, 8, 114, 30, 17, 195, 148, 194, 186, 58, 95, 195, 136, 94, 59, 26, 18,
-					6, 124, 119, 195, 152, 79, 194, 174, 21, 65, 194, 185, 195, 163, 195, 175, 197, 190,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -4796,10 +4893,12 @@ func TestParseEmailEnglishMultipartSignedAsciiOverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "ascii",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -4825,26 +4924,23 @@ func TestParseEmailEnglishMultipartSignedAsciiOverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "ascii",
 				},
 				Data: []byte{
-					226, 128, 154, 28, 226, 128, 161, 226, 128, 158, 117, 46, 197, 189, 18, 97, 197,
-					146, 126, 195, 187, 197, 184, 195, 129, 195, 135, 25, 58, 195, 159, 194, 189, 194,
-					185, 195, 163, 195, 175, 197, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80,
-					194, 165, 195, 188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239,
-					191, 189, 197, 184, 29, 195, 182, 19, 195, 171, 226, 128, 166, 80, 194, 165, 195,
-					188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197,
-					184, 29, 195, 182, 19, 195, 170, 49, 195, 187, 195, 174, 127, 7, 28, 104, 33, 195,
-					136, 120, 71, 82, 195, 168, 195, 161, 38, 30, 195, 185, 195, 170, 195, 150, 195,
-					129, 195, 180, 113, 226, 128, 156, 194, 173, 195, 187, 195, 155, 197, 190, 57, 195,
-					188, 28, 113, 226, 128, 156, 194, 173, 195, 187, 195, 161, 38, 24, 195, 135, 195,
-					175, 194, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 195, 187,
-					195, 167, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197, 184, 29, 195, 182,
-					19, 195, 170, 8, 114, 30, 17, 195, 148, 194, 186, 58, 95, 195, 136, 94, 59, 26, 18,
-					6, 124, 119, 195, 152, 79, 194, 174, 21, 65, 194, 185, 195, 163, 195, 175, 197, 190,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -4987,6 +5083,7 @@ func TestParseEmailEnglishMultipartSignedUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -5012,25 +5109,18 @@ func TestParseEmailEnglishMultipartSignedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
 	}
@@ -5171,10 +5261,12 @@ func TestParseEmailEnglishMultipartSignedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -5200,27 +5292,23 @@ func TestParseEmailEnglishMultipartSignedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -5359,10 +5447,12 @@ func TestParseEmailEnglishMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "The quick brown fox jumps over a lazy dog.\n" +
 			"Glib jocks quiz nymph to vex dwarf.\n" +
@@ -5388,27 +5478,23 @@ func TestParseEmailEnglishMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -5548,6 +5634,7 @@ func TestParseEmailChinesePlaintextGb18030OverBase64(t *testing.T) {
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -5701,6 +5788,7 @@ func TestParseEmailChinesePlaintextGb18030OverQuotedprintable(t *testing.T) {
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -5854,6 +5942,7 @@ func TestParseEmailChinesePlaintextGbkOverBase64(t *testing.T) {
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -6007,6 +6096,7 @@ func TestParseEmailChinesePlaintextGbkOverQuotedprintable(t *testing.T) {
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -6157,10 +6247,12 @@ func TestParseEmailChineseMultipartRelatedGb18030OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -6211,19 +6303,24 @@ func TestParseEmailChineseMultipartRelatedGb18030OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -6360,10 +6457,12 @@ func TestParseEmailChineseMultipartRelatedGb18030OverQuotedprintable(t *testing.
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -6414,19 +6513,24 @@ func TestParseEmailChineseMultipartRelatedGb18030OverQuotedprintable(t *testing.
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -6563,10 +6667,12 @@ func TestParseEmailChineseMultipartRelatedGbkOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -6617,19 +6723,24 @@ func TestParseEmailChineseMultipartRelatedGbkOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -6766,10 +6877,12 @@ func TestParseEmailChineseMultipartRelatedGbkOverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -6820,19 +6933,24 @@ func TestParseEmailChineseMultipartRelatedGbkOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -6969,10 +7087,12 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -7021,17 +7141,16 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -7047,17 +7166,16 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7073,7 +7191,6 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -7081,6 +7198,7 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -7094,7 +7212,6 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gb18030",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -7102,6 +7219,7 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7117,11 +7235,11 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7144,6 +7262,7 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7166,8 +7285,15 @@ func TestParseEmailChineseMultipartMixedGb18030OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -7304,10 +7430,12 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -7356,17 +7484,16 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -7382,17 +7509,16 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7408,7 +7534,6 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -7416,6 +7541,7 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -7429,7 +7555,6 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gb18030",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -7437,6 +7562,7 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7452,11 +7578,11 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7479,6 +7605,7 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7501,8 +7628,15 @@ func TestParseEmailChineseMultipartMixedGb18030OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -7639,10 +7773,12 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -7691,17 +7827,16 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -7717,17 +7852,16 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7743,7 +7877,6 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gbk",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -7751,6 +7884,7 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -7764,7 +7898,6 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gbk",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -7772,6 +7905,7 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7787,11 +7921,11 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "gbk",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7814,6 +7948,7 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -7836,8 +7971,15 @@ func TestParseEmailChineseMultipartMixedGbkOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -7974,10 +8116,12 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -8026,17 +8170,16 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -8052,17 +8195,16 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 232, 154, 129, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -8078,7 +8220,6 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gbk",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -8086,6 +8227,7 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -8099,7 +8241,6 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "gbk",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -8107,6 +8248,7 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -8122,11 +8264,11 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "gbk",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -8149,6 +8291,7 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -8171,8 +8314,15 @@ func TestParseEmailChineseMultipartMixedGbkOverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -8311,10 +8461,12 @@ func TestParseEmailChineseMultipartSignedGb18030OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -8343,24 +8495,23 @@ func TestParseEmailChineseMultipartSignedGb18030OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 229, 153, 173, 117, 46, 239, 191, 189, 18, 97, 229, 175, 143,
-					233, 186, 169, 231, 135, 142, 25, 58, 229, 145, 147, 229, 185, 191, 233, 164, 133,
-					231, 146, 180, 31, 71, 27, 229, 140, 172, 239, 191, 189, 229, 145, 171, 239, 191,
-					189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189, 19, 233, 155, 178, 80, 239,
-					191, 189, 229, 145, 171, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191,
-					189, 19, 239, 191, 189, 49, 239, 191, 189, 127, 7, 28, 104, 33, 232, 145, 130, 71,
-					82, 230, 160, 179, 38, 30, 239, 191, 189, 232, 135, 179, 233, 173, 134, 230, 145,
-					165, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 230, 145, 165, 239,
-					191, 189, 38, 24, 231, 167, 139, 231, 187, 143, 108, 31, 71, 27, 229, 140, 172, 110,
-					120, 239, 191, 189, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189,
-					19, 239, 191, 189, 8, 114, 30, 17, 233, 153, 162, 58, 95, 232, 144, 159, 59, 26,
-					18, 6, 124, 119, 232, 177, 139, 239, 191, 189, 21, 65, 229, 185, 191, 233, 164, 133,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -8499,10 +8650,12 @@ func TestParseEmailChineseMultipartSignedGb18030OverQuotedprintable(t *testing.T
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "gb18030",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -8531,24 +8684,23 @@ func TestParseEmailChineseMultipartSignedGb18030OverQuotedprintable(t *testing.T
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gb18030",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 229, 153, 173, 117, 46, 239, 191, 189, 18, 97, 229, 175, 143,
-					233, 186, 169, 231, 135, 142, 25, 58, 229, 145, 147, 229, 185, 191, 233, 164, 133,
-					231, 146, 180, 31, 71, 27, 229, 140, 172, 239, 191, 189, 229, 145, 171, 239, 191,
-					189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189, 19, 233, 155, 178, 80, 239,
-					191, 189, 229, 145, 171, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191,
-					189, 19, 239, 191, 189, 49, 239, 191, 189, 127, 7, 28, 104, 33, 232, 145, 130, 71,
-					82, 230, 160, 179, 38, 30, 239, 191, 189, 232, 135, 179, 233, 173, 134, 230, 145,
-					165, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 230, 145, 165, 239,
-					191, 189, 38, 24, 231, 167, 139, 231, 187, 143, 108, 31, 71, 27, 229, 140, 172, 110,
-					120, 239, 191, 189, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189,
-					19, 239, 191, 189, 8, 114, 30, 17, 233, 153, 162, 58, 95, 232, 144, 159, 59, 26,
-					18, 6, 124, 119, 232, 177, 139, 239, 191, 189, 21, 65, 229, 185, 191, 233, 164, 133,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -8687,10 +8839,12 @@ func TestParseEmailChineseMultipartSignedGbkOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -8719,24 +8873,23 @@ func TestParseEmailChineseMultipartSignedGbkOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 229, 153, 173, 117, 46, 239, 191, 189, 18, 97, 229, 175, 143,
-					233, 186, 169, 231, 135, 142, 25, 58, 229, 145, 147, 229, 185, 191, 233, 164, 133,
-					231, 146, 180, 31, 71, 27, 229, 140, 172, 239, 191, 189, 229, 145, 171, 239, 191,
-					189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189, 19, 233, 155, 178, 80, 239,
-					191, 189, 229, 145, 171, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191,
-					189, 19, 239, 191, 189, 49, 239, 191, 189, 127, 7, 28, 104, 33, 232, 145, 130, 71,
-					82, 230, 160, 179, 38, 30, 239, 191, 189, 232, 135, 179, 233, 173, 134, 230, 145,
-					165, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 230, 145, 165, 239,
-					191, 189, 38, 24, 231, 167, 139, 231, 187, 143, 108, 31, 71, 27, 229, 140, 172, 110,
-					120, 239, 191, 189, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189,
-					19, 239, 191, 189, 8, 114, 30, 17, 233, 153, 162, 58, 95, 232, 144, 159, 59, 26,
-					18, 6, 124, 119, 232, 177, 139, 239, 191, 189, 21, 65, 229, 185, 191, 233, 164, 133,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -8875,10 +9028,12 @@ func TestParseEmailChineseMultipartSignedGbkOverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "gbk",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "石室诗士施氏，嗜狮，誓食十狮。\n" +
 			"氏时时适市视狮。\n" +
@@ -8907,24 +9062,23 @@ func TestParseEmailChineseMultipartSignedGbkOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "gbk",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 229, 153, 173, 117, 46, 239, 191, 189, 18, 97, 229, 175, 143,
-					233, 186, 169, 231, 135, 142, 25, 58, 229, 145, 147, 229, 185, 191, 233, 164, 133,
-					231, 146, 180, 31, 71, 27, 229, 140, 172, 239, 191, 189, 229, 145, 171, 239, 191,
-					189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189, 19, 233, 155, 178, 80, 239,
-					191, 189, 229, 145, 171, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191,
-					189, 19, 239, 191, 189, 49, 239, 191, 189, 127, 7, 28, 104, 33, 232, 145, 130, 71,
-					82, 230, 160, 179, 38, 30, 239, 191, 189, 232, 135, 179, 233, 173, 134, 230, 145,
-					165, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 230, 145, 165, 239,
-					191, 189, 38, 24, 231, 167, 139, 231, 187, 143, 108, 31, 71, 27, 229, 140, 172, 110,
-					120, 239, 191, 189, 239, 191, 189, 229, 137, 154, 239, 191, 189, 29, 239, 191, 189,
-					19, 239, 191, 189, 8, 114, 30, 17, 233, 153, 162, 58, 95, 232, 144, 159, 59, 26,
-					18, 6, 124, 119, 232, 177, 139, 239, 191, 189, 21, 65, 229, 185, 191, 233, 164, 133,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -9064,6 +9218,7 @@ func TestParseEmailFinnishPlaintextUtf8OverBase64(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -9213,6 +9368,7 @@ func TestParseEmailFinnishPlaintextUtf8OverQuotedprintable(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -9362,6 +9518,7 @@ func TestParseEmailFinnishPlaintextIso885915OverBase64(t *testing.T) {
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -9511,6 +9668,7 @@ func TestParseEmailFinnishPlaintextIso885915OverQuotedprintable(t *testing.T) {
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -9657,10 +9815,12 @@ func TestParseEmailFinnishMultipartRelatedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -9699,19 +9859,24 @@ func TestParseEmailFinnishMultipartRelatedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -9848,10 +10013,12 @@ func TestParseEmailFinnishMultipartRelatedUtf8OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -9890,19 +10057,24 @@ func TestParseEmailFinnishMultipartRelatedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -10039,10 +10211,12 @@ func TestParseEmailFinnishMultipartRelatedIso885915OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -10081,18 +10255,24 @@ func TestParseEmailFinnishMultipartRelatedIso885915OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -10229,10 +10409,12 @@ func TestParseEmailFinnishMultipartRelatedIso885915OverQuotedprintable(t *testin
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -10271,18 +10453,24 @@ func TestParseEmailFinnishMultipartRelatedIso885915OverQuotedprintable(t *testin
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -10419,10 +10607,12 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -10459,17 +10649,16 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -10485,17 +10674,16 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10511,7 +10699,6 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -10519,6 +10706,7 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -10532,7 +10720,6 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -10540,6 +10727,7 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10555,11 +10743,11 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10582,6 +10770,7 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10604,8 +10793,15 @@ func TestParseEmailFinnishMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -10742,10 +10938,12 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -10782,17 +10980,16 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -10808,17 +11005,16 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10834,7 +11030,6 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -10842,6 +11037,7 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -10855,7 +11051,6 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -10863,6 +11058,7 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10878,11 +11074,11 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10905,6 +11101,7 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -10927,8 +11124,15 @@ func TestParseEmailFinnishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -11065,10 +11269,12 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -11105,16 +11311,16 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-15",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -11130,16 +11336,16 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11155,7 +11361,6 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -11163,6 +11368,7 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -11176,7 +11382,6 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-15",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -11184,6 +11389,7 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11199,11 +11405,11 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11226,6 +11432,7 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11248,8 +11455,15 @@ func TestParseEmailFinnishMultipartMixedIso885915OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -11386,10 +11600,12 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -11426,16 +11642,16 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-15",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -11451,16 +11667,16 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11476,7 +11692,6 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -11484,6 +11699,7 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -11497,7 +11713,6 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-15",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -11505,6 +11720,7 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11520,11 +11736,11 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11547,6 +11763,7 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -11569,8 +11786,15 @@ func TestParseEmailFinnishMultipartMixedIso885915OverQuotedprintable(t *testing.
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -11709,10 +11933,12 @@ func TestParseEmailFinnishMultipartSignedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -11737,27 +11963,23 @@ func TestParseEmailFinnishMultipartSignedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -11896,10 +12118,12 @@ func TestParseEmailFinnishMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -11924,27 +12148,23 @@ func TestParseEmailFinnishMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -12083,10 +12303,12 @@ func TestParseEmailFinnishMultipartSignedIso885915OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -12111,27 +12333,23 @@ func TestParseEmailFinnishMultipartSignedIso885915OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 195, 187, 239, 191, 189, 195, 129, 195, 135, 25, 58, 195, 159,
-					197, 147, 194, 185, 195, 163, 195, 175, 239, 191, 189, 194, 173, 108, 31, 71, 27,
-					239, 191, 189, 80, 194, 165, 195, 188, 239, 191, 189, 195, 163, 194, 174, 195, 134,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 195, 171, 239, 191,
-					189, 80, 194, 165, 195, 188, 239, 191, 189, 195, 163, 194, 174, 195, 134, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 195, 170, 49, 195, 187, 195,
-					174, 127, 7, 28, 104, 33, 195, 136, 120, 71, 82, 195, 168, 195, 161, 38, 30, 195,
-					185, 195, 170, 195, 150, 195, 129, 195, 180, 113, 239, 191, 189, 194, 173, 195, 187,
-					195, 155, 239, 191, 189, 57, 195, 188, 28, 113, 239, 191, 189, 194, 173, 195, 187,
-					195, 161, 38, 24, 195, 135, 195, 175, 197, 184, 194, 173, 108, 31, 71, 27, 239, 191,
-					189, 80, 110, 120, 195, 187, 195, 167, 194, 174, 195, 134, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 29, 195, 182, 19, 195, 170, 8, 114, 30, 17, 195, 148, 194, 186,
-					58, 95, 195, 136, 94, 59, 26, 18, 6, 124, 119, 195, 152, 79, 194, 174, 21, 65, 194,
-					185, 195, 163, 195, 175, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -12270,10 +12488,12 @@ func TestParseEmailFinnishMultipartSignedIso885915OverQuotedprintable(t *testing
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-15",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Albert osti fagotin ja töräytti puhkuvan melodian.\n" +
 			"Lorun sangen pieneksi hyödyksi jäivät suomen kirjaimet.\n" +
@@ -12298,27 +12518,23 @@ func TestParseEmailFinnishMultipartSignedIso885915OverQuotedprintable(t *testing
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-15",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 195, 187, 239, 191, 189, 195, 129, 195, 135, 25, 58, 195, 159,
-					197, 147, 194, 185, 195, 163, 195, 175, 239, 191, 189, 194, 173, 108, 31, 71, 27,
-					239, 191, 189, 80, 194, 165, 195, 188, 239, 191, 189, 195, 163, 194, 174, 195, 134,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 195, 171, 239, 191,
-					189, 80, 194, 165, 195, 188, 239, 191, 189, 195, 163, 194, 174, 195, 134, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 195, 170, 49, 195, 187, 195,
-					174, 127, 7, 28, 104, 33, 195, 136, 120, 71, 82, 195, 168, 195, 161, 38, 30, 195,
-					185, 195, 170, 195, 150, 195, 129, 195, 180, 113, 239, 191, 189, 194, 173, 195, 187,
-					195, 155, 239, 191, 189, 57, 195, 188, 28, 113, 239, 191, 189, 194, 173, 195, 187,
-					195, 161, 38, 24, 195, 135, 195, 175, 197, 184, 194, 173, 108, 31, 71, 27, 239, 191,
-					189, 80, 110, 120, 195, 187, 195, 167, 194, 174, 195, 134, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 29, 195, 182, 19, 195, 170, 8, 114, 30, 17, 195, 148, 194, 186,
-					58, 95, 195, 136, 94, 59, 26, 18, 6, 124, 119, 195, 152, 79, 194, 174, 21, 65, 194,
-					185, 195, 163, 195, 175, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -12458,6 +12674,7 @@ func TestParseEmailIcelandicPlaintextUtf8OverBase64(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -12604,6 +12821,7 @@ func TestParseEmailIcelandicPlaintextUtf8OverQuotedprintable(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -12750,6 +12968,7 @@ func TestParseEmailIcelandicPlaintextIso88591OverBase64(t *testing.T) {
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -12896,6 +13115,7 @@ func TestParseEmailIcelandicPlaintextIso88591OverQuotedprintable(t *testing.T) {
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -13039,10 +13259,12 @@ func TestParseEmailIcelandicMultipartRelatedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -13072,19 +13294,24 @@ func TestParseEmailIcelandicMultipartRelatedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -13221,10 +13448,12 @@ func TestParseEmailIcelandicMultipartRelatedUtf8OverQuotedprintable(t *testing.T
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -13254,19 +13483,24 @@ func TestParseEmailIcelandicMultipartRelatedUtf8OverQuotedprintable(t *testing.T
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -13403,10 +13637,12 @@ func TestParseEmailIcelandicMultipartRelatedIso88591OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -13436,18 +13672,24 @@ func TestParseEmailIcelandicMultipartRelatedIso88591OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -13584,10 +13826,12 @@ func TestParseEmailIcelandicMultipartRelatedIso88591OverQuotedprintable(t *testi
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -13617,18 +13861,24 @@ func TestParseEmailIcelandicMultipartRelatedIso88591OverQuotedprintable(t *testi
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -13765,10 +14015,12 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -13796,17 +14048,16 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -13822,17 +14073,16 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -13848,7 +14098,6 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -13856,6 +14105,7 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -13869,7 +14119,6 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -13877,6 +14126,7 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -13892,11 +14142,11 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -13919,6 +14169,7 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -13941,8 +14192,15 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -14079,10 +14337,12 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -14110,17 +14370,16 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -14136,17 +14395,16 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14162,7 +14420,6 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -14170,6 +14427,7 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -14183,7 +14441,6 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -14191,6 +14448,7 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14206,11 +14464,11 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14233,6 +14491,7 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14255,8 +14514,15 @@ func TestParseEmailIcelandicMultipartMixedUtf8OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -14393,10 +14659,12 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -14424,16 +14692,16 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-1",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -14449,16 +14717,16 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14474,7 +14742,6 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -14482,6 +14749,7 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -14495,7 +14763,6 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-1",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -14503,6 +14770,7 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14518,11 +14786,11 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14545,6 +14813,7 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14567,8 +14836,15 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -14705,10 +14981,12 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -14736,16 +15014,16 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-1",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -14761,16 +15039,16 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
-					195, 191, 195, 152, 195, 191, 195, 155, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 195, 191, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 195,
-					191, 195, 140, 0, 6, 0, 16, 16, 5, 195, 191, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 195,
-					146, 195, 143, 32, 195, 191, 195, 153,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14786,7 +15064,6 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -14794,6 +15071,7 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -14807,7 +15085,6 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-1",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -14815,6 +15092,7 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14830,11 +15108,11 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14857,6 +15135,7 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -14879,8 +15158,15 @@ func TestParseEmailIcelandicMultipartMixedIso88591OverQuotedprintable(t *testing
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -15019,10 +15305,12 @@ func TestParseEmailIcelandicMultipartSignedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -15044,27 +15332,23 @@ func TestParseEmailIcelandicMultipartSignedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -15203,10 +15487,12 @@ func TestParseEmailIcelandicMultipartSignedUtf8OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -15228,27 +15514,23 @@ func TestParseEmailIcelandicMultipartSignedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -15387,10 +15669,12 @@ func TestParseEmailIcelandicMultipartSignedIso88591OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -15412,26 +15696,23 @@ func TestParseEmailIcelandicMultipartSignedIso88591OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
-					226, 128, 154, 28, 226, 128, 161, 226, 128, 158, 117, 46, 197, 189, 18, 97, 197,
-					146, 126, 195, 187, 197, 184, 195, 129, 195, 135, 25, 58, 195, 159, 194, 189, 194,
-					185, 195, 163, 195, 175, 197, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80,
-					194, 165, 195, 188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239,
-					191, 189, 197, 184, 29, 195, 182, 19, 195, 171, 226, 128, 166, 80, 194, 165, 195,
-					188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197,
-					184, 29, 195, 182, 19, 195, 170, 49, 195, 187, 195, 174, 127, 7, 28, 104, 33, 195,
-					136, 120, 71, 82, 195, 168, 195, 161, 38, 30, 195, 185, 195, 170, 195, 150, 195,
-					129, 195, 180, 113, 226, 128, 156, 194, 173, 195, 187, 195, 155, 197, 190, 57, 195,
-					188, 28, 113, 226, 128, 156, 194, 173, 195, 187, 195, 161, 38, 24, 195, 135, 195,
-					175, 194, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 195, 187,
-					195, 167, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197, 184, 29, 195, 182,
-					19, 195, 170, 8, 114, 30, 17, 195, 148, 194, 186, 58, 95, 195, 136, 94, 59, 26, 18,
-					6, 124, 119, 195, 152, 79, 194, 174, 21, 65, 194, 185, 195, 163, 195, 175, 197, 190,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -15570,10 +15851,12 @@ func TestParseEmailIcelandicMultipartSignedIso88591OverQuotedprintable(t *testin
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-1",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Kæmi ný öxi hér, ykist þjófum nú bæði víl og ádrepa.\n" +
 			"Svo hölt, yxna kýr þegði jú um dóp í fé á bæ.\n" +
@@ -15595,26 +15878,23 @@ func TestParseEmailIcelandicMultipartSignedIso88591OverQuotedprintable(t *testin
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-1",
 				},
 				Data: []byte{
-					226, 128, 154, 28, 226, 128, 161, 226, 128, 158, 117, 46, 197, 189, 18, 97, 197,
-					146, 126, 195, 187, 197, 184, 195, 129, 195, 135, 25, 58, 195, 159, 194, 189, 194,
-					185, 195, 163, 195, 175, 197, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80,
-					194, 165, 195, 188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239,
-					191, 189, 197, 184, 29, 195, 182, 19, 195, 171, 226, 128, 166, 80, 194, 165, 195,
-					188, 226, 128, 166, 195, 163, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197,
-					184, 29, 195, 182, 19, 195, 170, 49, 195, 187, 195, 174, 127, 7, 28, 104, 33, 195,
-					136, 120, 71, 82, 195, 168, 195, 161, 38, 30, 195, 185, 195, 170, 195, 150, 195,
-					129, 195, 180, 113, 226, 128, 156, 194, 173, 195, 187, 195, 155, 197, 190, 57, 195,
-					188, 28, 113, 226, 128, 156, 194, 173, 195, 187, 195, 161, 38, 24, 195, 135, 195,
-					175, 194, 190, 194, 173, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 195, 187,
-					195, 167, 194, 174, 195, 134, 226, 128, 158, 239, 191, 189, 197, 184, 29, 195, 182,
-					19, 195, 170, 8, 114, 30, 17, 195, 148, 194, 186, 58, 95, 195, 136, 94, 59, 26, 18,
-					6, 124, 119, 195, 152, 79, 194, 174, 21, 65, 194, 185, 195, 163, 195, 175, 197, 190,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -15754,6 +16034,7 @@ func TestParseEmailJapanesePlaintextUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -15918,6 +16199,7 @@ func TestParseEmailJapanesePlaintextUtf8OverBase64(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -16082,6 +16364,7 @@ func TestParseEmailJapanesePlaintextUtf8OverQuotedprintable(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -16246,6 +16529,7 @@ func TestParseEmailJapanesePlaintextIso2022jpOver7bit(t *testing.T) {
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -16410,6 +16694,7 @@ func TestParseEmailJapanesePlaintextIso2022jpOverBase64(t *testing.T) {
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -16574,6 +16859,7 @@ func TestParseEmailJapanesePlaintextIso2022jpOverQuotedprintable(t *testing.T) {
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -16738,6 +17024,7 @@ func TestParseEmailJapanesePlaintextEucjpOverBase64(t *testing.T) {
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -16902,6 +17189,7 @@ func TestParseEmailJapanesePlaintextEucjpOverQuotedprintable(t *testing.T) {
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -17067,6 +17355,7 @@ func TestParseEmailJapaneseMultipartRelatedUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -17150,20 +17439,22 @@ func TestParseEmailJapaneseMultipartRelatedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -17299,10 +17590,12 @@ func TestParseEmailJapaneseMultipartRelatedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -17386,19 +17679,24 @@ func TestParseEmailJapaneseMultipartRelatedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -17535,10 +17833,12 @@ func TestParseEmailJapaneseMultipartRelatedUtf8OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -17622,19 +17922,24 @@ func TestParseEmailJapaneseMultipartRelatedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -17775,6 +18080,7 @@ func TestParseEmailJapaneseMultipartRelatedIso2022jpOver7bit(t *testing.T) {
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -17858,20 +18164,22 @@ func TestParseEmailJapaneseMultipartRelatedIso2022jpOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -18007,10 +18315,12 @@ func TestParseEmailJapaneseMultipartRelatedIso2022jpOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -18094,19 +18404,24 @@ func TestParseEmailJapaneseMultipartRelatedIso2022jpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -18243,10 +18558,12 @@ func TestParseEmailJapaneseMultipartRelatedIso2022jpOverQuotedprintable(t *testi
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -18330,19 +18647,24 @@ func TestParseEmailJapaneseMultipartRelatedIso2022jpOverQuotedprintable(t *testi
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -18479,10 +18801,12 @@ func TestParseEmailJapaneseMultipartRelatedEucjpOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -18566,19 +18890,24 @@ func TestParseEmailJapaneseMultipartRelatedEucjpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 229, 142, 166, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -18715,10 +19044,12 @@ func TestParseEmailJapaneseMultipartRelatedEucjpOverQuotedprintable(t *testing.T
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -18802,19 +19133,24 @@ func TestParseEmailJapaneseMultipartRelatedEucjpOverQuotedprintable(t *testing.T
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 229, 142, 166, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -18955,6 +19291,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -19036,17 +19373,16 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -19062,17 +19398,16 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19088,7 +19423,6 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -19096,6 +19430,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -19109,7 +19444,6 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -19117,6 +19451,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19132,11 +19467,11 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19159,6 +19494,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19181,9 +19517,13 @@ func TestParseEmailJapaneseMultipartMixedUtf8Over7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -19319,10 +19659,12 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -19404,17 +19746,16 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -19430,17 +19771,16 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19456,7 +19796,6 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -19464,6 +19803,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -19477,7 +19817,6 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -19485,6 +19824,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19500,11 +19840,11 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19527,6 +19867,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19549,8 +19890,15 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -19687,10 +20035,12 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -19772,17 +20122,16 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -19798,17 +20147,16 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19824,7 +20172,6 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -19832,6 +20179,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -19845,7 +20193,6 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -19853,6 +20200,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19868,11 +20216,11 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19895,6 +20243,7 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -19917,8 +20266,15 @@ func TestParseEmailJapaneseMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -20059,6 +20415,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -20140,17 +20497,16 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -20166,17 +20522,16 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20192,7 +20547,6 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -20200,6 +20554,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -20213,7 +20568,6 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-2022-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -20221,6 +20575,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20236,11 +20591,11 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20263,6 +20618,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20285,9 +20641,13 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOver7bit(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
 	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
+	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
 
@@ -20423,10 +20783,12 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -20508,17 +20870,16 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -20534,17 +20895,16 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20560,7 +20920,6 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -20568,6 +20927,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -20581,7 +20941,6 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-2022-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -20589,6 +20948,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20604,11 +20964,11 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20631,6 +20991,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20653,8 +21014,15 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -20791,10 +21159,12 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -20876,17 +21246,16 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -20902,17 +21271,16 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20928,7 +21296,6 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -20936,6 +21303,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -20949,7 +21317,6 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-2022-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -20957,6 +21324,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20972,11 +21340,11 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -20999,6 +21367,7 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21021,8 +21390,15 @@ func TestParseEmailJapaneseMultipartMixedIso2022jpOverQuotedprintable(t *testing
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -21159,10 +21535,12 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -21244,17 +21622,16 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 229, 142, 166, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -21270,17 +21647,16 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 229, 142, 166, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21296,7 +21672,6 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -21304,6 +21679,7 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -21317,7 +21693,6 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -21325,6 +21700,7 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21340,11 +21716,11 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21367,6 +21743,7 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21389,8 +21766,15 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -21527,10 +21911,12 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -21612,17 +21998,16 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 229, 142, 166, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -21638,17 +22023,16 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 229, 142, 166, 32, 239, 191, 189,
-					239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21664,7 +22048,6 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -21672,6 +22055,7 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -21685,7 +22069,6 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-jp",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -21693,6 +22076,7 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21708,11 +22092,11 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21735,6 +22119,7 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -21757,8 +22142,15 @@ func TestParseEmailJapaneseMultipartMixedEucjpOverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -21901,6 +22293,7 @@ func TestParseEmailJapaneseMultipartSignedUtf8Over7bit(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -21940,25 +22333,18 @@ func TestParseEmailJapaneseMultipartSignedUtf8Over7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
 	}
@@ -22099,10 +22485,12 @@ func TestParseEmailJapaneseMultipartSignedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -22142,27 +22530,23 @@ func TestParseEmailJapaneseMultipartSignedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -22301,10 +22685,12 @@ func TestParseEmailJapaneseMultipartSignedUtf8OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -22344,27 +22730,23 @@ func TestParseEmailJapaneseMultipartSignedUtf8OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -22507,6 +22889,7 @@ func TestParseEmailJapaneseMultipartSignedIso2022jpOver7bit(t *testing.T) {
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -22546,29 +22929,18 @@ func TestParseEmailJapaneseMultipartSignedIso2022jpOver7bit(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 108, 31, 71, 239, 191, 189, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189,
-					19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127, 7, 28, 104, 33, 239, 191,
-					189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239, 191, 189, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 108, 31, 71, 239, 191, 189, 239, 191, 189, 80,
-					110, 120, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 8, 114, 30, 17,
-					239, 191, 189, 239, 191, 189, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119,
-					239, 191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
 	}
@@ -22709,10 +23081,12 @@ func TestParseEmailJapaneseMultipartSignedIso2022jpOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -22752,31 +23126,23 @@ func TestParseEmailJapaneseMultipartSignedIso2022jpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 108, 31, 71, 239, 191, 189, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189,
-					19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127, 7, 28, 104, 33, 239, 191,
-					189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239, 191, 189, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 108, 31, 71, 239, 191, 189, 239, 191, 189, 80,
-					110, 120, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 8, 114, 30, 17,
-					239, 191, 189, 239, 191, 189, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119,
-					239, 191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -22915,10 +23281,12 @@ func TestParseEmailJapaneseMultipartSignedIso2022jpOverQuotedprintable(t *testin
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-2022-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -22958,31 +23326,23 @@ func TestParseEmailJapaneseMultipartSignedIso2022jpOverQuotedprintable(t *testin
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-2022-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 108, 31, 71, 239, 191, 189, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189,
-					19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127, 7, 28, 104, 33, 239, 191,
-					189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239, 191, 189, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 108, 31, 71, 239, 191, 189, 239, 191, 189, 80,
-					110, 120, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 8, 114, 30, 17,
-					239, 191, 189, 239, 191, 189, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119,
-					239, 191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -23121,10 +23481,12 @@ func TestParseEmailJapaneseMultipartSignedEucjpOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -23164,27 +23526,23 @@ func TestParseEmailJapaneseMultipartSignedEucjpOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 231, 180, 160, 25, 58, 230, 191,
-					172, 233, 180, 187, 239, 191, 189, 239, 191, 189, 239, 191, 189, 108, 31, 71, 27,
-					239, 191, 189, 80, 239, 191, 189, 239, 191, 189, 231, 165, 159, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 231, 165, 159, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 232,
-					171, 182, 127, 7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 232, 145, 185, 38, 30,
-					229, 173, 150, 229, 180, 155, 239, 191, 189, 113, 239, 191, 189, 226, 136, 169, 239,
-					191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 239, 191, 189, 226, 136, 169,
-					239, 191, 189, 38, 24, 230, 139, 141, 229, 176, 134, 108, 31, 71, 27, 239, 191, 189,
-					80, 110, 120, 232, 168, 146, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 8, 114, 30, 17, 229, 158, 170, 58, 95,
-					239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239, 191, 189, 79, 239, 191, 189, 21,
-					65, 233, 180, 187, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -23323,10 +23681,12 @@ func TestParseEmailJapaneseMultipartSignedEucjpOverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "euc-jp",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "色は匂えど\n" +
 			"散りぬるを\n" +
@@ -23366,27 +23726,23 @@ func TestParseEmailJapaneseMultipartSignedEucjpOverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-jp",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 231, 180, 160, 25, 58, 230, 191,
-					172, 233, 180, 187, 239, 191, 189, 239, 191, 189, 239, 191, 189, 108, 31, 71, 27,
-					239, 191, 189, 80, 239, 191, 189, 239, 191, 189, 231, 165, 159, 239, 191, 189, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 231, 165, 159, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 232,
-					171, 182, 127, 7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 232, 145, 185, 38, 30,
-					229, 173, 150, 229, 180, 155, 239, 191, 189, 113, 239, 191, 189, 226, 136, 169, 239,
-					191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 239, 191, 189, 226, 136, 169,
-					239, 191, 189, 38, 24, 230, 139, 141, 229, 176, 134, 108, 31, 71, 27, 239, 191, 189,
-					80, 110, 120, 232, 168, 146, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 8, 114, 30, 17, 229, 158, 170, 58, 95,
-					239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239, 191, 189, 79, 239, 191, 189, 21,
-					65, 233, 180, 187, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -23526,6 +23882,7 @@ func TestParseEmailKoreanPlaintextUtf8OverBase64(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -23670,6 +24027,7 @@ func TestParseEmailKoreanPlaintextUtf8OverQuotedprintable(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -23814,6 +24172,7 @@ func TestParseEmailKoreanPlaintextEuckrOverBase64(t *testing.T) {
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -23958,6 +24317,7 @@ func TestParseEmailKoreanPlaintextEuckrOverQuotedprintable(t *testing.T) {
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -24099,10 +24459,12 @@ func TestParseEmailKoreanMultipartRelatedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -24126,19 +24488,24 @@ func TestParseEmailKoreanMultipartRelatedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -24275,10 +24642,12 @@ func TestParseEmailKoreanMultipartRelatedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -24302,19 +24671,24 @@ func TestParseEmailKoreanMultipartRelatedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -24451,10 +24825,12 @@ func TestParseEmailKoreanMultipartRelatedEuckrOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -24478,19 +24854,24 @@ func TestParseEmailKoreanMultipartRelatedEuckrOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2,
-					2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10,
-					12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18,
-					19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11, 8, 0, 1, 0,
-					1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239, 191, 189,
-					239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 164, 187, 32, 239, 191, 189, 239, 191,
-					189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -24627,10 +25008,12 @@ func TestParseEmailKoreanMultipartRelatedEuckrOverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -24654,19 +25037,24 @@ func TestParseEmailKoreanMultipartRelatedEuckrOverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2,
-					2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10,
-					12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18,
-					19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11, 8, 0, 1, 0,
-					1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239, 191, 189,
-					239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 164, 187, 32, 239, 191, 189, 239, 191,
-					189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -24803,10 +25191,12 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -24828,17 +25218,16 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -24854,17 +25243,16 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -24880,7 +25268,6 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -24888,6 +25275,7 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -24901,7 +25289,6 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -24909,6 +25296,7 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -24924,11 +25312,11 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -24951,6 +25339,7 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -24973,8 +25362,15 @@ func TestParseEmailKoreanMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -25111,10 +25507,12 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -25136,17 +25534,16 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -25162,17 +25559,16 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25188,7 +25584,6 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -25196,6 +25591,7 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -25209,7 +25605,6 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -25217,6 +25612,7 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25232,11 +25628,11 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25259,6 +25655,7 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25281,8 +25678,15 @@ func TestParseEmailKoreanMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -25419,10 +25823,12 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -25444,17 +25850,16 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2,
-					2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10,
-					12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18,
-					19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11, 8, 0, 1, 0,
-					1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239, 191, 189,
-					239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 164, 187, 32, 239, 191, 189, 239, 191,
-					189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -25470,17 +25875,16 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2,
-					2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10,
-					12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18,
-					19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11, 8, 0, 1, 0,
-					1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239, 191, 189,
-					239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 164, 187, 32, 239, 191, 189, 239, 191,
-					189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25496,7 +25900,6 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -25504,6 +25907,7 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -25517,7 +25921,6 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-kr",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -25525,6 +25928,7 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25540,11 +25944,11 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25567,6 +25971,7 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25589,8 +25994,15 @@ func TestParseEmailKoreanMultipartMixedEuckrOverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -25727,10 +26139,12 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "<bold>키스의</bold> <italic>고유조건은</italic> <fixed>입술끼리</fixed> <underline>만나야</underline> 하고 특별한 기술은 필요치 않다.",
@@ -25752,17 +26166,16 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2,
-					2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10,
-					12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18,
-					19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11, 8, 0, 1, 0,
-					1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239, 191, 189,
-					239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 164, 187, 32, 239, 191, 189, 239, 191,
-					189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -25778,17 +26191,16 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2,
-					2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10,
-					12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18,
-					19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11, 8, 0, 1, 0,
-					1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239, 191, 189,
-					239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 164, 187, 32, 239, 191, 189, 239, 191,
-					189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25804,7 +26216,6 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -25812,6 +26223,7 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -25825,7 +26237,6 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "euc-kr",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -25833,6 +26244,7 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25848,11 +26260,11 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25875,6 +26287,7 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -25897,8 +26310,15 @@ func TestParseEmailKoreanMultipartMixedEuckrOverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -26037,10 +26457,12 @@ func TestParseEmailKoreanMultipartSignedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -26060,27 +26482,23 @@ func TestParseEmailKoreanMultipartSignedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -26219,10 +26637,12 @@ func TestParseEmailKoreanMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -26242,27 +26662,23 @@ func TestParseEmailKoreanMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -26401,10 +26817,12 @@ func TestParseEmailKoreanMultipartSignedEuckrOverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -26424,25 +26842,23 @@ func TestParseEmailKoreanMultipartSignedEuckrOverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 235, 135, 153, 117, 46, 239, 191, 189, 18, 97, 239, 191, 189,
-					126, 239, 191, 189, 236, 162, 161, 25, 58, 233, 162, 175, 235, 176, 164, 239, 191,
-					189, 236, 182, 141, 31, 71, 27, 234, 191, 150, 239, 191, 189, 235, 129, 185, 239,
-					191, 189, 234, 188, 164, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80,
-					239, 191, 189, 235, 129, 185, 239, 191, 189, 234, 188, 164, 239, 191, 189, 29, 239,
-					191, 189, 19, 239, 191, 189, 49, 231, 172, 143, 127, 7, 28, 104, 33, 239, 191, 189,
-					120, 71, 82, 231, 159, 174, 38, 30, 229, 144, 136, 233, 128, 158, 239, 191, 189,
-					113, 235, 178, 138, 232, 153, 142, 239, 191, 189, 57, 239, 191, 189, 28, 113, 235,
-					178, 138, 233, 160, 128, 38, 24, 237, 151, 172, 236, 146, 148, 108, 31, 71, 27, 234,
-					191, 150, 110, 120, 230, 152, 143, 239, 191, 189, 234, 188, 164, 239, 191, 189, 29,
-					239, 191, 189, 19, 239, 191, 189, 8, 114, 30, 17, 231, 137, 152, 58, 95, 239, 191,
-					189, 94, 59, 26, 18, 6, 124, 119, 239, 191, 189, 79, 239, 191, 189, 21, 65, 235,
-					176, 164, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -26581,10 +26997,12 @@ func TestParseEmailKoreanMultipartSignedEuckrOverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "euc-kr",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text:         "키스의 고유조건은 입술끼리 만나야 하고 특별한 기술은 필요치 않다.",
 		EnrichedText: "",
@@ -26604,25 +27022,23 @@ func TestParseEmailKoreanMultipartSignedEuckrOverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "euc-kr",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 235, 135, 153, 117, 46, 239, 191, 189, 18, 97, 239, 191, 189,
-					126, 239, 191, 189, 236, 162, 161, 25, 58, 233, 162, 175, 235, 176, 164, 239, 191,
-					189, 236, 182, 141, 31, 71, 27, 234, 191, 150, 239, 191, 189, 235, 129, 185, 239,
-					191, 189, 234, 188, 164, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80,
-					239, 191, 189, 235, 129, 185, 239, 191, 189, 234, 188, 164, 239, 191, 189, 29, 239,
-					191, 189, 19, 239, 191, 189, 49, 231, 172, 143, 127, 7, 28, 104, 33, 239, 191, 189,
-					120, 71, 82, 231, 159, 174, 38, 30, 229, 144, 136, 233, 128, 158, 239, 191, 189,
-					113, 235, 178, 138, 232, 153, 142, 239, 191, 189, 57, 239, 191, 189, 28, 113, 235,
-					178, 138, 233, 160, 128, 38, 24, 237, 151, 172, 236, 146, 148, 108, 31, 71, 27, 234,
-					191, 150, 110, 120, 230, 152, 143, 239, 191, 189, 234, 188, 164, 239, 191, 189, 29,
-					239, 191, 189, 19, 239, 191, 189, 8, 114, 30, 17, 231, 137, 152, 58, 95, 239, 191,
-					189, 94, 59, 26, 18, 6, 124, 119, 239, 191, 189, 79, 239, 191, 189, 21, 65, 235,
-					176, 164, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -26762,6 +27178,7 @@ func TestParseEmailPolishPlaintextUtf8OverBase64(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -26913,6 +27330,7 @@ func TestParseEmailPolishPlaintextUtf8OverQuotedprintable(t *testing.T) {
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -27064,6 +27482,7 @@ func TestParseEmailPolishPlaintextIso88592OverBase64(t *testing.T) {
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -27215,6 +27634,7 @@ func TestParseEmailPolishPlaintextIso88592OverQuotedprintable(t *testing.T) {
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -27363,10 +27783,12 @@ func TestParseEmailPolishMultipartRelatedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -27411,19 +27833,24 @@ func TestParseEmailPolishMultipartRelatedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -27560,10 +27987,12 @@ func TestParseEmailPolishMultipartRelatedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -27608,19 +28037,24 @@ func TestParseEmailPolishMultipartRelatedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -27757,10 +28191,12 @@ func TestParseEmailPolishMultipartRelatedIso88592OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -27805,18 +28241,24 @@ func TestParseEmailPolishMultipartRelatedIso88592OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
-					203, 153, 197, 152, 203, 153, 197, 176, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 203, 153, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 203,
-					153, 196, 154, 0, 6, 0, 16, 16, 5, 203, 153, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 197,
-					135, 196, 142, 32, 203, 153, 197, 174,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -27953,10 +28395,12 @@ func TestParseEmailPolishMultipartRelatedIso88592OverQuotedprintable(t *testing.
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -28001,18 +28445,24 @@ func TestParseEmailPolishMultipartRelatedIso88592OverQuotedprintable(t *testing.
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
-					203, 153, 197, 152, 203, 153, 197, 176, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 203, 153, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 203,
-					153, 196, 154, 0, 6, 0, 16, 16, 5, 203, 153, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 197,
-					135, 196, 142, 32, 203, 153, 197, 174,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -28149,10 +28599,12 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -28195,17 +28647,16 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -28221,17 +28672,16 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28247,7 +28697,6 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -28255,6 +28704,7 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -28268,7 +28718,6 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -28276,6 +28725,7 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28291,11 +28741,11 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28318,6 +28768,7 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28340,8 +28791,15 @@ func TestParseEmailPolishMultipartMixedUtf8OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -28478,10 +28936,12 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -28524,17 +28984,16 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -28550,17 +29009,16 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 239, 191, 189, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 239, 191, 189, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 239, 191, 189, 0, 8, 1, 1, 0, 0, 63, 0, 239, 191, 189, 239, 191, 189, 32,
-					239, 191, 189, 239, 191, 189,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28576,7 +29034,6 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -28584,6 +29041,7 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -28597,7 +29055,6 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "utf-8",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -28605,6 +29062,7 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28620,11 +29078,11 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28647,6 +29105,7 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28669,8 +29128,15 @@ func TestParseEmailPolishMultipartMixedUtf8OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -28807,10 +29273,12 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -28853,16 +29321,16 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-2",
 				},
 				Data: []byte{
-					203, 153, 197, 152, 203, 153, 197, 176, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 203, 153, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 203,
-					153, 196, 154, 0, 6, 0, 16, 16, 5, 203, 153, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 197,
-					135, 196, 142, 32, 203, 153, 197, 174,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -28878,16 +29346,16 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
-					203, 153, 197, 152, 203, 153, 197, 176, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 203, 153, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 203,
-					153, 196, 154, 0, 6, 0, 16, 16, 5, 203, 153, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 197,
-					135, 196, 142, 32, 203, 153, 197, 174,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28903,7 +29371,6 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -28911,6 +29378,7 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -28924,7 +29392,6 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-2",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -28932,6 +29399,7 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28947,11 +29415,11 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28974,6 +29442,7 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -28996,8 +29465,15 @@ func TestParseEmailPolishMultipartMixedIso88592OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -29134,10 +29610,12 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -29180,16 +29658,16 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-2",
 				},
 				Data: []byte{
-					203, 153, 197, 152, 203, 153, 197, 176, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 203, 153, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 203,
-					153, 196, 154, 0, 6, 0, 16, 16, 5, 203, 153, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 197,
-					135, 196, 142, 32, 203, 153, 197, 174,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -29205,16 +29683,16 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
-					203, 153, 197, 152, 203, 153, 197, 176, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3,
-					3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9, 9, 10, 12, 15,
-					12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16, 10, 12, 18, 19, 18,
-					16, 19, 15, 16, 16, 16, 203, 153, 195, 137, 0, 11, 8, 0, 1, 0, 1, 1, 1, 17, 0, 203,
-					153, 196, 154, 0, 6, 0, 16, 16, 5, 203, 153, 195, 154, 0, 8, 1, 1, 0, 0, 63, 0, 197,
-					135, 196, 142, 32, 203, 153, 197, 174,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -29230,7 +29708,6 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -29238,6 +29715,7 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -29251,7 +29729,6 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-2",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -29259,6 +29736,7 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -29274,11 +29752,11 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -29301,6 +29779,7 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -29323,8 +29802,15 @@ func TestParseEmailPolishMultipartMixedIso88592OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -29463,10 +29949,12 @@ func TestParseEmailPolishMultipartSignedUtf8OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -29493,27 +29981,23 @@ func TestParseEmailPolishMultipartSignedUtf8OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -29652,10 +30136,12 @@ func TestParseEmailPolishMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "utf-8",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -29682,27 +30168,23 @@ func TestParseEmailPolishMultipartSignedUtf8OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "utf-8",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 25,
-					58, 223, 189, 239, 191, 189, 239, 191, 189, 239, 158, 173, 108, 31, 71, 27, 239,
-					191, 189, 80, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132,
-					239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189, 80, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 198, 132, 239, 191, 189, 239, 191,
-					189, 29, 239, 191, 189, 19, 239, 191, 189, 49, 239, 191, 189, 239, 191, 189, 127,
-					7, 28, 104, 33, 239, 191, 189, 120, 71, 82, 239, 191, 189, 239, 191, 189, 38, 30,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 219, 158, 57, 239, 191, 189, 28, 113, 239,
-					191, 189, 239, 191, 189, 239, 191, 189, 239, 191, 189, 38, 24, 239, 191, 189, 239,
-					190, 173, 108, 31, 71, 27, 239, 191, 189, 80, 110, 120, 239, 191, 189, 239, 191,
-					189, 198, 132, 239, 191, 189, 239, 191, 189, 29, 239, 191, 189, 19, 239, 191, 189,
-					8, 114, 30, 17, 212, 186, 58, 95, 239, 191, 189, 94, 59, 26, 18, 6, 124, 119, 239,
-					191, 189, 79, 239, 191, 189, 21, 65, 239, 191, 189, 239, 191, 189, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -29841,10 +30323,12 @@ func TestParseEmailPolishMultipartSignedIso88592OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -29871,27 +30355,23 @@ func TestParseEmailPolishMultipartSignedIso88592OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 197, 177, 239, 191, 189, 195, 129, 195, 135, 25, 58, 195, 159,
-					203, 157, 197, 161, 196, 131, 196, 143, 239, 191, 189, 194, 173, 108, 31, 71, 27,
-					239, 191, 189, 80, 196, 189, 195, 188, 239, 191, 189, 196, 131, 197, 189, 196, 134,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 195, 171, 239, 191,
-					189, 80, 196, 189, 195, 188, 239, 191, 189, 196, 131, 197, 189, 196, 134, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 196, 153, 49, 197, 177, 195,
-					174, 127, 7, 28, 104, 33, 196, 140, 120, 71, 82, 196, 141, 195, 161, 38, 30, 197,
-					175, 196, 153, 195, 150, 195, 129, 195, 180, 113, 239, 191, 189, 194, 173, 197, 177,
-					197, 176, 239, 191, 189, 57, 195, 188, 28, 113, 239, 191, 189, 194, 173, 197, 177,
-					195, 161, 38, 24, 195, 135, 196, 143, 197, 190, 194, 173, 108, 31, 71, 27, 239, 191,
-					189, 80, 110, 120, 197, 177, 195, 167, 197, 189, 196, 134, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 29, 195, 182, 19, 196, 153, 8, 114, 30, 17, 195, 148, 197, 159,
-					58, 95, 196, 140, 94, 59, 26, 18, 6, 124, 119, 197, 152, 79, 197, 189, 21, 65, 197,
-					161, 196, 131, 196, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -30030,10 +30510,12 @@ func TestParseEmailPolishMultipartSignedIso88592OverQuotedprintable(t *testing.T
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-2",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "Jeżu klątw, spłódź Finom część gry hańb!\n" +
 			"Pójdźże, kiń tę chmurność w głąb flaszy!\n" +
@@ -30060,27 +30542,23 @@ func TestParseEmailPolishMultipartSignedIso88592OverQuotedprintable(t *testing.T
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-2",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 197, 177, 239, 191, 189, 195, 129, 195, 135, 25, 58, 195, 159,
-					203, 157, 197, 161, 196, 131, 196, 143, 239, 191, 189, 194, 173, 108, 31, 71, 27,
-					239, 191, 189, 80, 196, 189, 195, 188, 239, 191, 189, 196, 131, 197, 189, 196, 134,
-					239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 195, 171, 239, 191,
-					189, 80, 196, 189, 195, 188, 239, 191, 189, 196, 131, 197, 189, 196, 134, 239, 191,
-					189, 239, 191, 189, 239, 191, 189, 29, 195, 182, 19, 196, 153, 49, 197, 177, 195,
-					174, 127, 7, 28, 104, 33, 196, 140, 120, 71, 82, 196, 141, 195, 161, 38, 30, 197,
-					175, 196, 153, 195, 150, 195, 129, 195, 180, 113, 239, 191, 189, 194, 173, 197, 177,
-					197, 176, 239, 191, 189, 57, 195, 188, 28, 113, 239, 191, 189, 194, 173, 197, 177,
-					195, 161, 38, 24, 195, 135, 196, 143, 197, 190, 194, 173, 108, 31, 71, 27, 239, 191,
-					189, 80, 110, 120, 197, 177, 195, 167, 197, 189, 196, 134, 239, 191, 189, 239, 191,
-					189, 239, 191, 189, 29, 195, 182, 19, 196, 153, 8, 114, 30, 17, 195, 148, 197, 159,
-					58, 95, 196, 140, 94, 59, 26, 18, 6, 124, 119, 197, 152, 79, 197, 189, 21, 65, 197,
-					161, 196, 131, 196, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -30216,6 +30694,7 @@ func TestParseEmailThaiPlaintextIso885911OverBase64(t *testing.T) {
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -30358,6 +30837,7 @@ func TestParseEmailThaiPlaintextIso885911OverQuotedprintable(t *testing.T) {
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -30500,6 +30980,7 @@ func TestParseEmailThaiPlaintextWindows874OverBase64(t *testing.T) {
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -30642,6 +31123,7 @@ func TestParseEmailThaiPlaintextWindows874OverQuotedprintable(t *testing.T) {
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -30784,6 +31266,7 @@ func TestParseEmailThaiPlaintextTis620OverBase64(t *testing.T) {
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -30926,6 +31409,7 @@ func TestParseEmailThaiPlaintextTis620OverQuotedprintable(t *testing.T) {
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -31065,10 +31549,12 @@ func TestParseEmailThaiMultipartRelatedIso885911OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -31098,19 +31584,24 @@ func TestParseEmailThaiMultipartRelatedIso885911OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -31243,10 +31734,12 @@ func TestParseEmailThaiMultipartRelatedIso885911OverQuotedprintable(t *testing.T
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -31276,19 +31769,24 @@ func TestParseEmailThaiMultipartRelatedIso885911OverQuotedprintable(t *testing.T
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -31421,10 +31919,12 @@ func TestParseEmailThaiMultipartRelatedWindows874OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -31454,19 +31954,24 @@ func TestParseEmailThaiMultipartRelatedWindows874OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -31599,10 +32104,12 @@ func TestParseEmailThaiMultipartRelatedWindows874OverQuotedprintable(t *testing.
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -31632,19 +32139,24 @@ func TestParseEmailThaiMultipartRelatedWindows874OverQuotedprintable(t *testing.
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -31777,10 +32289,12 @@ func TestParseEmailThaiMultipartRelatedTis620OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -31810,19 +32324,24 @@ func TestParseEmailThaiMultipartRelatedTis620OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -31955,10 +32474,12 @@ func TestParseEmailThaiMultipartRelatedTis620OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -31988,19 +32509,24 @@ func TestParseEmailThaiMultipartRelatedTis620OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 4,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/related`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[0],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -32133,10 +32659,12 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -32164,17 +32692,16 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -32190,17 +32717,16 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32216,7 +32742,6 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -32224,6 +32749,7 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -32237,7 +32763,6 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-11",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -32245,6 +32770,7 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32260,11 +32786,11 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32287,6 +32813,7 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32309,8 +32836,15 @@ func TestParseEmailThaiMultipartMixedIso885911OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -32443,10 +32977,12 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -32474,17 +33010,16 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -32500,17 +33035,16 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32526,7 +33060,6 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -32534,6 +33067,7 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -32547,7 +33081,6 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "iso-8859-11",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -32555,6 +33088,7 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32570,11 +33104,11 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32597,6 +33131,7 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32619,8 +33154,15 @@ func TestParseEmailThaiMultipartMixedIso885911OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -32753,10 +33295,12 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -32784,17 +33328,16 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -32810,17 +33353,16 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32836,7 +33378,6 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -32844,6 +33385,7 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -32857,7 +33399,6 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "windows-874",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -32865,6 +33406,7 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32880,11 +33422,11 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32907,6 +33449,7 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -32929,8 +33472,15 @@ func TestParseEmailThaiMultipartMixedWindows874OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -33063,10 +33613,12 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -33094,17 +33646,16 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -33120,17 +33671,16 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33146,7 +33696,6 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -33154,6 +33703,7 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -33167,7 +33717,6 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "windows-874",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -33175,6 +33724,7 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33190,11 +33740,11 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33217,6 +33767,7 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33239,8 +33790,15 @@ func TestParseEmailThaiMultipartMixedWindows874OverQuotedprintable(t *testing.T)
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -33373,10 +33931,12 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -33404,17 +33964,16 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -33430,17 +33989,16 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33456,7 +34014,6 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -33464,6 +34021,7 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -33477,7 +34035,6 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "tis-620",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -33485,6 +34042,7 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33500,11 +34058,11 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33527,6 +34085,7 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33549,8 +34108,15 @@ func TestParseEmailThaiMultipartMixedTis620OverBase64(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -33683,10 +34249,12 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -33714,17 +34282,16 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 5,
 			},
 			&email.File{
 				FileType: "inline",
@@ -33740,17 +34307,16 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "inline-jpg-image.jpg@example.com",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 224, 184, 184, 239, 191, 189, 239, 191, 189, 0, 67, 0, 3, 2, 2, 2,
-					2, 2, 3, 2, 2, 2, 3, 3, 3, 3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9,
-					8, 9, 9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
-					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 239, 191, 189, 224, 184, 169, 0, 11,
-					8, 0, 1, 0, 1, 1, 1, 17, 0, 239, 191, 189, 224, 184, 172, 0, 6, 0, 16, 16, 5, 239,
-					191, 189, 224, 184, 186, 0, 8, 1, 1, 0, 0, 63, 0, 224, 184, 178, 224, 184, 175, 32,
-					239, 191, 189, 224, 184, 185,
+					255, 216, 255, 219, 0, 67, 0, 3, 2, 2, 2, 2, 2, 3, 2, 2, 2, 3, 3, 3,
+					3, 4, 6, 4, 4, 4, 4, 4, 8, 6, 6, 5, 6, 9, 8, 10, 10, 9, 8, 9,
+					9, 10, 12, 15, 12, 10, 11, 14, 11, 9, 9, 13, 17, 13, 14, 15, 16, 16, 17, 16,
+					10, 12, 18, 19, 18, 16, 19, 15, 16, 16, 16, 255, 201, 0, 11, 8, 0, 1, 0, 1,
+					1, 1, 17, 0, 255, 204, 0, 6, 0, 16, 16, 5, 255, 218, 0, 8, 1, 1, 0, 0,
+					63, 0, 210, 207, 32, 255, 217,
 				},
+				Index: 6,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33766,7 +34332,6 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -33774,6 +34339,7 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 7,
 			},
 			&email.File{
 				FileType: "",
@@ -33787,7 +34353,6 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					DispositionParams: map[string]string(nil), // p0
 					TransferEncoding:  "base64",
 					ID:                "",
-					Charset:           "tis-620",
 				},
 				Data: []byte{
 					37, 80, 68, 70, 45, 49, 46, 13, 116, 114, 97, 105, 108, 101, 114, 60, 60, 47, 82,
@@ -33795,6 +34360,7 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					91, 60, 60, 47, 77, 101, 100, 105, 97, 66, 111, 120, 91, 48, 32, 48, 32, 51, 32,
 					51, 93, 62, 62, 93, 62, 62, 62, 62, 62, 62,
 				},
+				Index: 8,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33810,11 +34376,11 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "7bit",
 					ID:               "",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
 					123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125,
 				},
+				Index: 9,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33837,6 +34403,7 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 116, 120,
 					116, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 10,
 			},
 			&email.File{
 				FileType: "attachment",
@@ -33859,8 +34426,15 @@ func TestParseEmailThaiMultipartMixedTis620OverQuotedprintable(t *testing.T) {
 					32, 97, 115, 32, 97, 110, 32, 97, 116, 116, 97, 99, 104, 101, 100, 32, 46, 104, 116,
 					109, 108, 32, 102, 105, 108, 101, 46,
 				},
+				Index: 11,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/mixed`),
+		},
+	}
+	expectedEmail.ContentIDFiles = map[string]*email.File{
+		"inline-jpg-image.jpg@example.com": expectedEmail.Files[1],
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -33995,10 +34569,12 @@ func TestParseEmailThaiMultipartSignedIso885911OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -34020,30 +34596,23 @@ func TestParseEmailThaiMultipartSignedIso885911OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 224, 185, 155, 239, 191, 189, 224, 184, 161, 224, 184, 167, 25,
-					58, 224, 184, 191, 224, 184, 157, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239,
-					191, 189, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 224, 184, 133, 239,
-					191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224, 184, 166, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224, 185, 139, 226, 128, 166,
-					80, 224, 184, 133, 239, 191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224,
-					184, 166, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224,
-					185, 138, 49, 224, 185, 155, 224, 185, 142, 127, 7, 28, 104, 33, 224, 184, 168, 120,
-					71, 82, 224, 185, 136, 224, 185, 129, 38, 30, 224, 185, 153, 224, 185, 138, 224,
-					184, 182, 224, 184, 161, 224, 185, 148, 113, 226, 128, 156, 224, 184, 141, 224, 185,
-					155, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 226, 128, 156, 224,
-					184, 141, 224, 185, 155, 224, 185, 129, 38, 24, 224, 184, 167, 224, 185, 143, 224,
-					184, 158, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 224, 185,
-					155, 224, 185, 135, 224, 184, 142, 224, 184, 166, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 29, 224, 185, 150, 19, 224, 185, 138, 8, 114, 30, 17, 224, 184, 180, 224,
-					184, 154, 58, 95, 224, 184, 168, 94, 59, 26, 18, 6, 124, 119, 224, 184, 184, 79,
-					224, 184, 142, 21, 65, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -34178,10 +34747,12 @@ func TestParseEmailThaiMultipartSignedIso885911OverQuotedprintable(t *testing.T)
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "iso-8859-11",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -34203,30 +34774,23 @@ func TestParseEmailThaiMultipartSignedIso885911OverQuotedprintable(t *testing.T)
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "iso-8859-11",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 224, 185, 155, 239, 191, 189, 224, 184, 161, 224, 184, 167, 25,
-					58, 224, 184, 191, 224, 184, 157, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239,
-					191, 189, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 224, 184, 133, 239,
-					191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224, 184, 166, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224, 185, 139, 226, 128, 166,
-					80, 224, 184, 133, 239, 191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224,
-					184, 166, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224,
-					185, 138, 49, 224, 185, 155, 224, 185, 142, 127, 7, 28, 104, 33, 224, 184, 168, 120,
-					71, 82, 224, 185, 136, 224, 185, 129, 38, 30, 224, 185, 153, 224, 185, 138, 224,
-					184, 182, 224, 184, 161, 224, 185, 148, 113, 226, 128, 156, 224, 184, 141, 224, 185,
-					155, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 226, 128, 156, 224,
-					184, 141, 224, 185, 155, 224, 185, 129, 38, 24, 224, 184, 167, 224, 185, 143, 224,
-					184, 158, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 224, 185,
-					155, 224, 185, 135, 224, 184, 142, 224, 184, 166, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 29, 224, 185, 150, 19, 224, 185, 138, 8, 114, 30, 17, 224, 184, 180, 224,
-					184, 154, 58, 95, 224, 184, 168, 94, 59, 26, 18, 6, 124, 119, 224, 184, 184, 79,
-					224, 184, 142, 21, 65, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -34361,10 +34925,12 @@ func TestParseEmailThaiMultipartSignedWindows874OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -34386,30 +34952,23 @@ func TestParseEmailThaiMultipartSignedWindows874OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 224, 185, 155, 239, 191, 189, 224, 184, 161, 224, 184, 167, 25,
-					58, 224, 184, 191, 224, 184, 157, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239,
-					191, 189, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 224, 184, 133, 239,
-					191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224, 184, 166, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224, 185, 139, 226, 128, 166,
-					80, 224, 184, 133, 239, 191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224,
-					184, 166, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224,
-					185, 138, 49, 224, 185, 155, 224, 185, 142, 127, 7, 28, 104, 33, 224, 184, 168, 120,
-					71, 82, 224, 185, 136, 224, 185, 129, 38, 30, 224, 185, 153, 224, 185, 138, 224,
-					184, 182, 224, 184, 161, 224, 185, 148, 113, 226, 128, 156, 224, 184, 141, 224, 185,
-					155, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 226, 128, 156, 224,
-					184, 141, 224, 185, 155, 224, 185, 129, 38, 24, 224, 184, 167, 224, 185, 143, 224,
-					184, 158, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 224, 185,
-					155, 224, 185, 135, 224, 184, 142, 224, 184, 166, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 29, 224, 185, 150, 19, 224, 185, 138, 8, 114, 30, 17, 224, 184, 180, 224,
-					184, 154, 58, 95, 224, 184, 168, 94, 59, 26, 18, 6, 124, 119, 224, 184, 184, 79,
-					224, 184, 142, 21, 65, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -34544,10 +35103,12 @@ func TestParseEmailThaiMultipartSignedWindows874OverQuotedprintable(t *testing.T
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "windows-874",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -34569,30 +35130,23 @@ func TestParseEmailThaiMultipartSignedWindows874OverQuotedprintable(t *testing.T
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "windows-874",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 224, 185, 155, 239, 191, 189, 224, 184, 161, 224, 184, 167, 25,
-					58, 224, 184, 191, 224, 184, 157, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239,
-					191, 189, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 224, 184, 133, 239,
-					191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224, 184, 166, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224, 185, 139, 226, 128, 166,
-					80, 224, 184, 133, 239, 191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224,
-					184, 166, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224,
-					185, 138, 49, 224, 185, 155, 224, 185, 142, 127, 7, 28, 104, 33, 224, 184, 168, 120,
-					71, 82, 224, 185, 136, 224, 185, 129, 38, 30, 224, 185, 153, 224, 185, 138, 224,
-					184, 182, 224, 184, 161, 224, 185, 148, 113, 226, 128, 156, 224, 184, 141, 224, 185,
-					155, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 226, 128, 156, 224,
-					184, 141, 224, 185, 155, 224, 185, 129, 38, 24, 224, 184, 167, 224, 185, 143, 224,
-					184, 158, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 224, 185,
-					155, 224, 185, 135, 224, 184, 142, 224, 184, 166, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 29, 224, 185, 150, 19, 224, 185, 138, 8, 114, 30, 17, 224, 184, 180, 224,
-					184, 154, 58, 95, 224, 184, 168, 94, 59, 26, 18, 6, 124, 119, 224, 184, 184, 79,
-					224, 184, 142, 21, 65, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -34727,10 +35281,12 @@ func TestParseEmailThaiMultipartSignedTis620OverBase64(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "base64",
+				IgnoredEncoding:   "base64",
 				ID:                "",
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -34752,30 +35308,23 @@ func TestParseEmailThaiMultipartSignedTis620OverBase64(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 224, 185, 155, 239, 191, 189, 224, 184, 161, 224, 184, 167, 25,
-					58, 224, 184, 191, 224, 184, 157, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239,
-					191, 189, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 224, 184, 133, 239,
-					191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224, 184, 166, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224, 185, 139, 226, 128, 166,
-					80, 224, 184, 133, 239, 191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224,
-					184, 166, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224,
-					185, 138, 49, 224, 185, 155, 224, 185, 142, 127, 7, 28, 104, 33, 224, 184, 168, 120,
-					71, 82, 224, 185, 136, 224, 185, 129, 38, 30, 224, 185, 153, 224, 185, 138, 224,
-					184, 182, 224, 184, 161, 224, 185, 148, 113, 226, 128, 156, 224, 184, 141, 224, 185,
-					155, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 226, 128, 156, 224,
-					184, 141, 224, 185, 155, 224, 185, 129, 38, 24, 224, 184, 167, 224, 185, 143, 224,
-					184, 158, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 224, 185,
-					155, 224, 185, 135, 224, 184, 142, 224, 184, 166, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 29, 224, 185, 150, 19, 224, 185, 138, 8, 114, 30, 17, 224, 184, 180, 224,
-					184, 154, 58, 95, 224, 184, 168, 94, 59, 26, 18, 6, 124, 119, 224, 184, 184, 79,
-					224, 184, 142, 21, 65, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "base64" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -34910,10 +35459,12 @@ func TestParseEmailThaiMultipartSignedTis620OverQuotedprintable(t *testing.T) {
 				Disposition:       "",
 				DispositionParams: map[string]string(nil), // p0
 				TransferEncoding:  "quoted-printable",
+				IgnoredEncoding:   "quoted-printable",
 				ID:                "",
 				Charset:           "tis-620",
 			},
 			Received: nil,
+			Priority: email.PriorityNormal,
 		},
 		Text: "เป็นมนุษย์สุดประเสริฐเลิศคุณค่า กว่าบรรดาฝูงสัตว์เดรัจฉาน จงฝ่าฟันพัฒนาวิชาการ อย่าล้างผลาญฤๅเข่นฆ่าบีฑาใคร ไม่ถือโทษโกรธแช่งซัดฮึดฮัดด่า หัดอภัยเหมือนกีฬาอัชฌาสัย ปฏิบัติประพฤติกฎกำหนดใจ พูดจาให้จ๊ะๆ จ๋าๆ น่าฟังเอยฯ\n" +
 			"\n" +
@@ -34935,30 +35486,23 @@ func TestParseEmailThaiMultipartSignedTis620OverQuotedprintable(t *testing.T) {
 					},
 					TransferEncoding: "base64",
 					ID:               "",
-					Charset:          "tis-620",
 				},
 				Data: []byte{
-					239, 191, 189, 28, 239, 191, 189, 239, 191, 189, 117, 46, 239, 191, 189, 18, 97,
-					239, 191, 189, 126, 224, 185, 155, 239, 191, 189, 224, 184, 161, 224, 184, 167, 25,
-					58, 224, 184, 191, 224, 184, 157, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239,
-					191, 189, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 224, 184, 133, 239,
-					191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224, 184, 166, 239, 191, 189,
-					239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224, 185, 139, 226, 128, 166,
-					80, 224, 184, 133, 239, 191, 189, 226, 128, 166, 224, 185, 131, 224, 184, 142, 224,
-					184, 166, 239, 191, 189, 239, 191, 189, 239, 191, 189, 29, 224, 185, 150, 19, 224,
-					185, 138, 49, 224, 185, 155, 224, 185, 142, 127, 7, 28, 104, 33, 224, 184, 168, 120,
-					71, 82, 224, 185, 136, 224, 185, 129, 38, 30, 224, 185, 153, 224, 185, 138, 224,
-					184, 182, 224, 184, 161, 224, 185, 148, 113, 226, 128, 156, 224, 184, 141, 224, 185,
-					155, 239, 191, 189, 239, 191, 189, 57, 239, 191, 189, 28, 113, 226, 128, 156, 224,
-					184, 141, 224, 185, 155, 224, 185, 129, 38, 24, 224, 184, 167, 224, 185, 143, 224,
-					184, 158, 224, 184, 141, 108, 31, 71, 27, 226, 128, 166, 80, 110, 120, 224, 185,
-					155, 224, 185, 135, 224, 184, 142, 224, 184, 166, 239, 191, 189, 239, 191, 189, 239,
-					191, 189, 29, 224, 185, 150, 19, 224, 185, 138, 8, 114, 30, 17, 224, 184, 180, 224,
-					184, 154, 58, 95, 224, 184, 168, 94, 59, 26, 18, 6, 124, 119, 224, 184, 184, 79,
-					224, 184, 142, 21, 65, 224, 184, 153, 224, 185, 131, 224, 185, 143, 239, 191, 189,
+					130, 28, 135, 132, 117, 46, 142, 18, 97, 140, 126, 251, 159, 193, 199, 25, 58, 223, 189, 185,
+					227, 239, 158, 173, 108, 31, 71, 27, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29,
+					246, 19, 235, 133, 80, 165, 252, 133, 227, 174, 198, 132, 129, 159, 29, 246, 19, 234, 49, 251,
+					238, 127, 7, 28, 104, 33, 200, 120, 71, 82, 232, 225, 38, 30, 249, 234, 214, 193, 244, 113,
+					147, 173, 251, 219, 158, 57, 252, 28, 113, 147, 173, 251, 225, 38, 24, 199, 239, 190, 173, 108,
+					31, 71, 27, 133, 80, 110, 120, 251, 231, 174, 198, 132, 129, 159, 29, 246, 19, 234, 8, 114,
+					30, 17, 212, 186, 58, 95, 200, 94, 59, 26, 18, 6, 124, 119, 216, 79, 174, 21, 65, 185,
+					227, 239, 158,
 				},
+				Index: 1,
 			},
 		},
+		Warnings: []error{
+			errors.New(`ignored illegal Content-Transfer-Encoding "quoted-printable" on multipart/signed`),
+		},
 	}
 	testEmailFromFile(t, fp, expectedEmail)
 }
@@ -0,0 +1,230 @@
+// Package maildir walks the messages of a maildir, parsing each with a
+// letters parser. A maildir holds one message per file in its "new",
+// "cur" and "tmp" subdirectories, with flags such as "seen" or
+// "replied" recorded in the info section of the file name of messages
+// in "cur", such as "1700000000.M1P2.host:2,RS".
+//
+// See https://cr.yp.to/proto/maildir.html
+package maildir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rorycl/letters/email"
+	"github.com/rorycl/letters/parser"
+)
+
+// Maildir flags (the characters of the "2," info section)
+const (
+	FlagPassed  = 'P' // resent, forwarded or bounced
+	FlagReplied = 'R'
+	FlagSeen    = 'S'
+	FlagTrashed = 'T'
+	FlagDraft   = 'D'
+	FlagFlagged = 'F'
+)
+
+// ErrNotMaildir is returned if a directory lacks the "new" and "cur"
+// subdirectories of a maildir.
+var ErrNotMaildir = errors.New("not a maildir")
+
+// Message is a message of a maildir.
+type Message struct {
+	// Path is the path to the message file.
+	Path string
+	// Subdir is the subdirectory of the maildir holding the message,
+	// being "new", "cur" or "tmp".
+	Subdir string
+	// Key is the unique name of the message, being its file name
+	// without the info section.
+	Key string
+	// Flags are the flags of the info section in the order given, such
+	// as "RS", or empty if there are none.
+	Flags string
+	// Email is the parsed message, or nil if it could not be parsed.
+	Email *email.Email
+}
+
+// HasFlag reports if the message has the flag, such as FlagSeen.
+func (m *Message) HasFlag(flag rune) bool {
+	return strings.ContainsRune(m.Flags, flag)
+}
+
+// parseFileName returns the key and flags of a message file name. The
+// info section follows a ":" separator, or the ";" used where colons
+// are not permitted in file names, and only "2," experimental
+// semantics info is recognised.
+func parseFileName(name string) (key, flags string) {
+	i := strings.LastIndexAny(name, ":;")
+	if i < 0 {
+		return name, ""
+	}
+	key, info := name[:i], name[i+1:]
+	if f, ok := strings.CutPrefix(info, "2,"); ok {
+		flags = f
+	}
+	return key, flags
+}
+
+// walker holds the settings of a walk.
+type walker struct {
+	parser      *parser.Parser
+	concurrency int
+	tmp         bool
+}
+
+// Opt is an option for Walk.
+type Opt func(w *walker)
+
+// WithConcurrency sets the number of messages parsed concurrently
+// (default 1). Messages are yielded in the order they finish parsing,
+// which is only the directory order if n is 1.
+func WithConcurrency(n int) Opt {
+	return func(w *walker) {
+		if n > 0 {
+			w.concurrency = n
+		}
+	}
+}
+
+// WithTmp includes the messages of the "tmp" subdirectory, which are
+// normally excluded as they may be partially delivered.
+func WithTmp() Opt {
+	return func(w *walker) {
+		w.tmp = true
+	}
+}
+
+// Walk walks the messages of the maildir dir, returning an iterator of
+// the messages parsed with p, in the "new" then "cur" subdirectories
+// sorted by file name. A message which fails to parse yields its error,
+// identifying the message by its path, with the Email of the Message
+// nil, and iteration continues with the next message. Iteration ends
+// after yielding an error reading the maildir, or ErrNotMaildir if dir
+// is not a maildir. Ending iteration early cancels the parsing of any
+// messages in progress, and the emails of messages parsed but not
+// yielded are closed, removing any temporary files of a parser using
+// WithLazyFiles, before iteration returns.
+func Walk(dir string, p *parser.Parser, opts ...Opt) iter.Seq2[*Message, error] {
+	w := &walker{parser: p, concurrency: 1}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return func(yield func(*Message, error) bool) {
+		messages, err := w.list(dir)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		type result struct {
+			msg *Message
+			err error
+		}
+		jobs := make(chan *Message)
+		results := make(chan result)
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		go func() {
+			defer close(jobs)
+			for _, m := range messages {
+				select {
+				case jobs <- m:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		for range w.concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for m := range jobs {
+					err := w.parse(ctx, m)
+					select {
+					case results <- result{m, err}:
+					case <-ctx.Done():
+						// the result is dropped
+						if m.Email != nil {
+							_ = m.Email.Close()
+						}
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for r := range results {
+			if !yield(r.msg, r.err) {
+				return
+			}
+		}
+	}
+}
+
+// list returns the unparsed messages of the maildir.
+func (w *walker) list(dir string) ([]*Message, error) {
+	subdirs := []string{"new", "cur"}
+	if w.tmp {
+		subdirs = append(subdirs, "tmp")
+	}
+	messages := []*Message{}
+	for _, sub := range subdirs {
+		// ReadDir sorts entries by file name
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		switch {
+		case errors.Is(err, os.ErrNotExist) && sub == "tmp":
+			continue
+		case errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("%w: %s lacks %q", ErrNotMaildir, dir, sub)
+		case err != nil:
+			return nil, fmt.Errorf("cannot read maildir: %w", err)
+		}
+		for _, e := range entries {
+			// skip subdirectories and hidden files
+			if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			key, flags := parseFileName(e.Name())
+			messages = append(messages, &Message{
+				Path:   filepath.Join(dir, sub, e.Name()),
+				Subdir: sub,
+				Key:    key,
+				Flags:  flags,
+			})
+		}
+	}
+	return messages, nil
+}
+
+// parse parses the message file into m.Email, aborting if ctx is
+// cancelled.
+func (w *walker) parse(ctx context.Context, m *Message) error {
+	f, err := os.Open(m.Path)
+	if err != nil {
+		return fmt.Errorf("cannot open message: %w", err)
+	}
+	defer f.Close()
+	m.Email, err = w.parser.ParseContext(ctx, f)
+	if err != nil {
+		return fmt.Errorf("message %s: %w", m.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package maildir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rorycl/letters/parser"
+)
+
+// makeMaildir makes a maildir in a temporary directory with the given
+// files, keyed by their path in the maildir.
+func makeMaildir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, sub := range []string{"new", "cur", "tmp"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func message(subject string) string {
+	return "From: a@example.com\r\nSubject: " + subject + "\r\n\r\nHello\r\n"
+}
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		flags string
+	}{
+		{"1700000000.M1P2.host", "1700000000.M1P2.host", ""},
+		{"1700000000.M1P2.host:2,", "1700000000.M1P2.host", ""},
+		{"1700000000.M1P2.host:2,RS", "1700000000.M1P2.host", "RS"},
+		{"1700000000.M1P2.host;2,FS", "1700000000.M1P2.host", "FS"},
+		{"1700000000.M1P2.host:1,xyz", "1700000000.M1P2.host", ""},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			key, flags := parseFileName(tt.name)
+			if got, want := key, tt.key; got != want {
+				t.Errorf("got key %q want %q", got, want)
+			}
+			if got, want := flags, tt.flags; got != want {
+				t.Errorf("got flags %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	dir := makeMaildir(t, map[string]string{
+		"new/2.host":        message("two"),
+		"new/1.host":        message("one"),
+		"cur/3.host:2,S":    message("three"),
+		"cur/4.host:2,FRS":  message("four"),
+		"cur/.hidden":       message("hidden"),
+		"tmp/5.host":        message("five"),
+		"cur/6.host:2,T":    "not an email",
+		"cur/7.host:2,PS":   message("seven"),
+		"new/0.host.broken": "\x00",
+	})
+
+	subjects, errs := []string{}, []error{}
+	var four *Message
+	for m, err := range Walk(dir, parser.NewParser()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		subjects = append(subjects, m.Email.Headers.Subject)
+		if m.Key == "4.host" {
+			four = m
+		}
+	}
+	if got, want := strings.Join(subjects, " "), "one two three four seven"; got != want {
+		t.Errorf("got subjects %q want %q", got, want)
+	}
+	if got, want := len(errs), 2; got != want {
+		t.Fatalf("got %d errors want %d: %v", got, want, errs)
+	}
+	if got, want := errs[0].Error(), filepath.Join(dir, "new", "0.host.broken"); !strings.Contains(got, want) {
+		t.Errorf("got error %q want it to contain %q", got, want)
+	}
+	if four == nil {
+		t.Fatal("message 4.host not found")
+	}
+	if got, want := four.Subdir, "cur"; got != want {
+		t.Errorf("got subdir %q want %q", got, want)
+	}
+	if got, want := four.Flags, "FRS"; got != want {
+		t.Errorf("got flags %q want %q", got, want)
+	}
+	if !four.HasFlag(FlagFlagged) || !four.HasFlag(FlagSeen) || four.HasFlag(FlagTrashed) {
+		t.Errorf("unexpected flags for %q", four.Flags)
+	}
+}
+
+func TestWalkTmp(t *testing.T) {
+	dir := makeMaildir(t, map[string]string{
+		"new/1.host": message("one"),
+		"tmp/2.host": message("two"),
+	})
+	count := 0
+	for m, err := range Walk(dir, parser.NewParser(), WithTmp()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Key == "2.host" && m.Subdir != "tmp" {
+			t.Errorf("got subdir %q want tmp", m.Subdir)
+		}
+		count++
+	}
+	if got, want := count, 2; got != want {
+		t.Errorf("got %d messages want %d", got, want)
+	}
+}
+
+func TestWalkConcurrent(t *testing.T) {
+	files := map[string]string{}
+	want := []string{}
+	for i := range 100 {
+		subject := fmt.Sprintf("message %03d", i)
+		files[fmt.Sprintf("cur/%03d.host:2,S", i)] = message(subject)
+		want = append(want, subject)
+	}
+	dir := makeMaildir(t, files)
+
+	got := []string{}
+	for m, err := range Walk(dir, parser.NewParser(), WithConcurrency(8)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m.Email.Headers.Subject)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("got %d subjects want %d", len(got), len(want))
+	}
+}
+
+func TestWalkEarlyStop(t *testing.T) {
+	files := map[string]string{}
+	for i := range 20 {
+		files[fmt.Sprintf("new/%02d.host", i)] = message("x")
+	}
+	dir := makeMaildir(t, files)
+
+	count := 0
+	for range Walk(dir, parser.NewParser(), WithConcurrency(4)) {
+		count++
+		break
+	}
+	if got, want := count, 1; got != want {
+		t.Errorf("got %d messages want %d", got, want)
+	}
+}
+
+func TestWalkEarlyStopLazyFiles(t *testing.T) {
+	msg := "From: a@example.com\r\nSubject: x\r\n" +
+		"Content-Type: multipart/mixed; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nHello\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n" +
+		"Content-Disposition: attachment; filename=\"a.bin\"\r\n\r\n" +
+		"data\r\n--b--\r\n"
+	files := map[string]string{}
+	for i := range 20 {
+		files[fmt.Sprintf("new/%02d.host", i)] = msg
+	}
+	dir := makeMaildir(t, files)
+	spool := t.TempDir()
+
+	p := parser.NewParser(parser.WithLazyFiles(0, spool))
+	for m, err := range Walk(dir, p, WithConcurrency(4)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Email.Close(); err != nil {
+			t.Fatal(err)
+		}
+		break
+	}
+	entries, err := os.ReadDir(spool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(entries), 0; got != want {
+		t.Errorf("got %d spool files want %d", got, want)
+	}
+}
+
+func TestWalkNotMaildir(t *testing.T) {
+	count := 0
+	for m, err := range Walk(t.TempDir(), parser.NewParser()) {
+		count++
+		if m != nil {
+			t.Errorf("got message %v want nil", m)
+		}
+		if !errors.Is(err, ErrNotMaildir) {
+			t.Errorf("got error %v want %v", err, ErrNotMaildir)
+		}
+	}
+	if got, want := count, 1; got != want {
+		t.Errorf("got %d results want %d", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// The parts of a multipart/alternative are equivalent representations
+// of the same content in increasing order of preference (RFC 2046
+// section 5.1.4), so rather than being concatenated as for the parts of
+// a multipart/mixed, only the last body of each type is kept.
+
+// bodyMarks records the lengths of the email's bodies and ordered parts
+// at a point in parsing.
+type bodyMarks struct {
+	text, enriched, html, ordered int
+}
+
+// markBodies returns the current bodyMarks of the email.
+func (se *stagedEmail) markBodies() bodyMarks {
+	return bodyMarks{
+		text:     len(se.email.Text),
+		enriched: len(se.email.EnrichedText),
+		html:     len(se.email.HTML),
+		ordered:  len(se.email.OrderedParts),
+	}
+}
+
+// keepLastAlternative removes the bodies added by the earlier parts of
+// a multipart/alternative, which started at the start marks, that are
+// superseded by a body of the same type added by the part which started
+// at the before marks.
+func (se *stagedEmail) keepLastAlternative(start, before bodyMarks) {
+	e := se.email
+	superseded := []string{}
+	if len(e.Text) > before.text && before.text > start.text {
+		e.Text = e.Text[:start.text] + e.Text[before.text:]
+		if start.text == 0 {
+			e.Text = strings.TrimPrefix(e.Text, "\n\n") // separator
+		}
+		superseded = append(superseded, email.OrderedPartText)
+	}
+	if len(e.EnrichedText) > before.enriched && before.enriched > start.enriched {
+		e.EnrichedText = e.EnrichedText[:start.enriched] + e.EnrichedText[before.enriched:]
+		superseded = append(superseded, email.OrderedPartEnriched)
+	}
+	if len(e.HTML) > before.html && before.html > start.html {
+		e.HTML = e.HTML[:start.html] + e.HTML[before.html:]
+		superseded = append(superseded, email.OrderedPartHTML)
+	}
+	if len(superseded) == 0 || before.ordered == start.ordered {
+		return
+	}
+	parts := slices.Clone(e.OrderedParts[:start.ordered])
+	for _, p := range e.OrderedParts[start.ordered:before.ordered] {
+		if !slices.Contains(superseded, p.Kind) {
+			parts = append(parts, p)
+		}
+	}
+	e.OrderedParts = append(parts, e.OrderedParts[before.ordered:]...)
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAlternativeKeepsLastBody(t *testing.T) {
+
+	part := func(typ, body string) string {
+		return "Content-Type: " + typ + "; charset=utf-8\r\n\r\n" + body + "\r\n"
+	}
+	multipart := func(typ, boundary string, parts ...string) string {
+		s := "Content-Type: " + typ + "; boundary=\"" + boundary + "\"\r\n\r\n"
+		for _, p := range parts {
+			s += "--" + boundary + "\r\n" + p
+		}
+		return s + "--" + boundary + "--\r\n"
+	}
+
+	tests := []struct {
+		body    string
+		text    string
+		html    string
+		ordered int
+	}{
+		{
+			// two plain text alternatives
+			body: multipart("multipart/alternative", "alt",
+				part("text/plain", "Plain."),
+				part("text/plain", "# Markdown."),
+			),
+			text:    "# Markdown.",
+			ordered: 1,
+		},
+		{
+			// the parts of a mixed are concatenated
+			body: multipart("multipart/mixed", "mixed",
+				part("text/plain", "First."),
+				part("text/plain", "Second."),
+			),
+			text:    "First.\n\nSecond.",
+			ordered: 2,
+		},
+		{
+			// an alternative within a mixed
+			body: multipart("multipart/mixed", "mixed",
+				part("text/plain", "Preamble."),
+				multipart("multipart/alternative", "alt",
+					part("text/plain", "Plain."),
+					part("text/html", "<p>HTML.</p>"),
+					part("text/plain", "# Markdown."),
+				),
+				part("text/plain", "Footer."),
+			),
+			text:    "Preamble.\n\n# Markdown.\n\nFooter.",
+			html:    "<p>HTML.</p>",
+			ordered: 4,
+		},
+		{
+			// a nested multipart supersedes an earlier alternative
+			body: multipart("multipart/alternative", "alt",
+				part("text/plain", "Plain."),
+				part("text/html", "<p>HTML.</p>"),
+				multipart("multipart/related", "rel",
+					part("text/html", "<p>Related HTML.</p>"),
+				),
+			),
+			text:    "Plain.",
+			html:    "<p>Related HTML.</p>",
+			ordered: 2,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			msg := "Subject: alternatives\r\nMIME-Version: 1.0\r\n" + tt.body
+			em, err := NewParser(WithOrderedParts()).Parse(strings.NewReader(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, tt.text; got != want {
+				t.Errorf("got text %q want %q", got, want)
+			}
+			if got, want := em.HTML, tt.html; got != want {
+				t.Errorf("got html %q want %q", got, want)
+			}
+			if got, want := len(em.OrderedParts), tt.ordered; got != want {
+				t.Errorf("got %d want %d ordered parts", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rorycl/letters/email"
+)
+
+// parseDKIMSignature parses a DKIM-Signature header value, a
+// semicolon separated list of "tag=value" pairs, into its tags. Folding
+// whitespace within values, such as in a long signature, is removed.
+// Parsing never fails: malformed tags are ignored.
+func parseDKIMSignature(s string) email.DKIMSignature {
+	ds := email.DKIMSignature{Raw: s, Tags: map[string]string{}}
+	for _, tag := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(tag, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			continue
+		}
+		ds.Tags[name] = strings.Join(strings.Fields(value), "")
+	}
+
+	unixTime := func(v string) time.Time {
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seconds < 0 {
+			return time.Time{}
+		}
+		return time.Unix(seconds, 0).UTC()
+	}
+
+	ds.Version = ds.Tags["v"]
+	ds.Algorithm = ds.Tags["a"]
+	ds.Canonicalization = ds.Tags["c"]
+	ds.Domain = ds.Tags["d"]
+	ds.Selector = ds.Tags["s"]
+	ds.Identity = ds.Tags["i"]
+	for _, h := range strings.Split(ds.Tags["h"], ":") {
+		if h != "" {
+			ds.Headers = append(ds.Headers, h)
+		}
+	}
+	ds.BodyHash = ds.Tags["bh"]
+	ds.Signature = ds.Tags["b"]
+	ds.Timestamp = unixTime(ds.Tags["t"])
+	ds.Expiration = unixTime(ds.Tags["x"])
+	return ds
+}
+
+// authFields splits an Authentication-Results header value into
+// semicolon separated statements, each split into whitespace separated
+// fields. Comments are removed and quoted strings are unquoted, while
+// semicolons and whitespace within them are retained.
+func authFields(s string) [][]string {
+	statements := [][]string{}
+	fields := []string{}
+	var b strings.Builder
+	quoted := false // the current field includes a quoted string
+	endField := func() {
+		if b.Len() > 0 || quoted {
+			fields = append(fields, b.String())
+		}
+		b.Reset()
+		quoted = false
+	}
+	endStatement := func() {
+		endField()
+		statements = append(statements, fields)
+		fields = []string{}
+	}
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case '(':
+			_, i = readComment(s, i)
+			endField()
+			continue
+		case '"':
+			quoted = true
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+		case ';':
+			endStatement()
+		case ' ', '\t', '\r', '\n':
+			endField()
+		default:
+			b.WriteByte(c)
+		}
+		i++
+	}
+	endStatement()
+	return statements
+}
+
+// joinAssignments rejoins the fields of a statement split around the
+// "=" of a method result or property, which RFC 8601 permits to be
+// surrounded by whitespace and comments, as in "spf = pass".
+func joinAssignments(fields []string) []string {
+	joined := []string{}
+	for _, f := range fields {
+		if n := len(joined); n > 0 && (strings.HasSuffix(joined[n-1], "=") || strings.HasPrefix(f, "=")) {
+			joined[n-1] += f
+			continue
+		}
+		joined = append(joined, f)
+	}
+	return joined
+}
+
+// parseAuthResults parses an Authentication-Results header value into
+// the results of each authentication method it reports. The header
+// starts with the identifier of the reporting server, followed by a
+// semicolon separated list of results such as "dkim=pass
+// header.d=example.com", or "none" if no methods were applied.
+// Malformed results are ignored.
+func parseAuthResults(s string) []email.AuthResult {
+	statements := authFields(s)
+	if len(statements) == 0 || len(statements[0]) == 0 {
+		return nil
+	}
+	servID := statements[0][0]
+	var results []email.AuthResult
+	for _, fields := range statements[1:] {
+		fields = joinAssignments(fields)
+		if len(fields) == 0 {
+			continue
+		}
+		method, result, ok := strings.Cut(fields[0], "=")
+		if !ok || method == "" {
+			continue
+		}
+		method, _, _ = strings.Cut(method, "/") // remove any version
+		ar := email.AuthResult{
+			AuthServID: servID,
+			Method:     strings.ToLower(method),
+			Result:     strings.ToLower(result),
+			Properties: map[string]string{},
+		}
+		for _, f := range fields[1:] {
+			name, value, ok := strings.Cut(f, "=")
+			if !ok {
+				continue
+			}
+			if strings.EqualFold(name, "reason") {
+				ar.Reason = value
+				continue
+			}
+			ar.Properties[strings.ToLower(name)] = value
+		}
+		results = append(results, ar)
+	}
+	return results
+}
+
+// parseARCAuthResults parses an ARC-Authentication-Results header
+// value, which is an Authentication-Results value preceded by the ARC
+// instance tag, such as "i=1; mx.example.net; spf=pass". Results are
+// returned with their Instance set, or 0 if the instance tag is missing
+// or invalid.
+func parseARCAuthResults(s string) []email.AuthResult {
+	instance := 0
+	if tag, rest, ok := strings.Cut(s, ";"); ok {
+		name, value, found := strings.Cut(tag, "=")
+		if found && strings.EqualFold(strings.TrimSpace(name), "i") {
+			if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n > 0 {
+				instance, s = n, rest
+			}
+		}
+	}
+	results := parseAuthResults(s)
+	for i := range results {
+		results[i].Instance = instance
+	}
+	return results
+}
@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestParseDKIMSignature(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want email.DKIMSignature
+	}{
+		{
+			raw: "v=1; a=rsa-sha256; c=relaxed/simple; d=example.com; s=sel1;\r\n\ti=@example.com; t=1744106400; x=1744711200;\r\n\th=From : To:Subject; bh=abc=; b=de\r\n\t f=",
+			want: email.DKIMSignature{
+				Tags: map[string]string{
+					"v": "1", "a": "rsa-sha256", "c": "relaxed/simple", "d": "example.com",
+					"s": "sel1", "i": "@example.com", "t": "1744106400", "x": "1744711200",
+					"h": "From:To:Subject", "bh": "abc=", "b": "def=",
+				},
+				Version: "1", Algorithm: "rsa-sha256", Canonicalization: "relaxed/simple",
+				Domain: "example.com", Selector: "sel1", Identity: "@example.com",
+				Headers:  []string{"From", "To", "Subject"},
+				BodyHash: "abc=", Signature: "def=",
+				Timestamp:  time.Date(2025, 4, 8, 10, 0, 0, 0, time.UTC),
+				Expiration: time.Date(2025, 4, 15, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// malformed tags and an invalid timestamp
+			raw: "v=1; garbage; =x; d=example.com; t=yesterday;",
+			want: email.DKIMSignature{
+				Tags:    map[string]string{"v": "1", "d": "example.com", "t": "yesterday"},
+				Version: "1", Domain: "example.com",
+			},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			tt.want.Raw = tt.raw
+			got := parseDKIMSignature(tt.raw)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected signature (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAuthResults(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []email.AuthResult
+	}{
+		{
+			raw: `mx.example.net 1; dkim/1=PASS reason="good; valid" header.d=example.com (comment); spf=none`,
+			want: []email.AuthResult{
+				{
+					AuthServID: "mx.example.net", Method: "dkim", Result: "pass", Reason: "good; valid",
+					Properties: map[string]string{"header.d": "example.com"},
+				},
+				{
+					AuthServID: "mx.example.net", Method: "spf", Result: "none",
+					Properties: map[string]string{},
+				},
+			},
+		},
+		{
+			raw: `mx.example.net; spf = pass (sender ok) smtp.mailfrom = example.com; dkim (signed)= fail reason = "bad sig"`,
+			want: []email.AuthResult{
+				{
+					AuthServID: "mx.example.net", Method: "spf", Result: "pass",
+					Properties: map[string]string{"smtp.mailfrom": "example.com"},
+				},
+				{
+					AuthServID: "mx.example.net", Method: "dkim", Result: "fail", Reason: "bad sig",
+					Properties: map[string]string{},
+				},
+			},
+		},
+		{
+			raw:  "mx.example.net; none",
+			want: nil,
+		},
+		{
+			raw:  "",
+			want: nil,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			got := parseAuthResults(tt.raw)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected results (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseARCAuthResults(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []email.AuthResult
+	}{
+		{
+			raw: "i=2; relay.example.org; arc=pass (as.2.example.net=pass)",
+			want: []email.AuthResult{
+				{
+					AuthServID: "relay.example.org", Method: "arc", Result: "pass",
+					Properties: map[string]string{}, Instance: 2,
+				},
+			},
+		},
+		{
+			raw: " I = 1 ;mx.example.net; spf=pass smtp.mailfrom=example.com",
+			want: []email.AuthResult{
+				{
+					AuthServID: "mx.example.net", Method: "spf", Result: "pass",
+					Properties: map[string]string{"smtp.mailfrom": "example.com"}, Instance: 1,
+				},
+			},
+		},
+		{
+			// without an instance tag the value is parsed as
+			// Authentication-Results
+			raw: "mx.example.net; spf=pass",
+			want: []email.AuthResult{
+				{
+					AuthServID: "mx.example.net", Method: "spf", Result: "pass",
+					Properties: map[string]string{},
+				},
+			},
+		},
+		{
+			raw:  "i=x; mx.example.net; none",
+			want: nil,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			got := parseARCAuthResults(tt.raw)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected results (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAuthHeaders(t *testing.T) {
+
+	f, err := os.Open("testdata/dkim.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := em.Headers
+
+	if got, want := len(h.DKIMSignatures), 2; got != want {
+		t.Fatalf("got %d signatures want %d", got, want)
+	}
+	first, second := h.DKIMSignatures[0], h.DKIMSignatures[1]
+	if got, want := first.Selector+" "+second.Selector, "sel1 sel2"; got != want {
+		t.Errorf("got selectors %q want %q", got, want)
+	}
+	if got, want := first.Signature, "AuTJdGhpcyBpcyBub3QgYSByZWFsIHNpZ25hdHVyZQ==c2lnbmF0dXJlIGNvbnRpbnVlZA=="; got != want {
+		t.Errorf("got signature %q want %q", got, want)
+	}
+	if diff := cmp.Diff([]string{"from", "to", "subject", "date", "message-id"}, first.Headers); diff != "" {
+		t.Errorf("unexpected signed headers (-want +got):\n%s", diff)
+	}
+
+	got := []string{}
+	for _, ar := range h.AuthResults {
+		got = append(got, fmt.Sprintf("%s %s=%s %v", ar.AuthServID, ar.Method, ar.Result, ar.Properties))
+	}
+	want := []string{
+		"mx.example.net dkim=pass map[header.b:AuTJ header.d:example.com header.s:sel1]",
+		"mx.example.net spf=fail map[smtp.mailfrom:alice@example.com]",
+		"mx.example.net dmarc=pass map[header.from:example.com]",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+
+	got = []string{}
+	for _, ar := range h.ARCAuthResults {
+		got = append(got, fmt.Sprintf("%d %s %s=%s", ar.Instance, ar.AuthServID, ar.Method, ar.Result))
+	}
+	want = []string{
+		"1 mx.example.net dkim=pass",
+		"1 mx.example.net spf=fail",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected arc results (-want +got):\n%s", diff)
+	}
+}
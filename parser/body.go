@@ -6,20 +6,28 @@ import (
 	"io"
 	"strings"
 
-	"github.com/rorycl/letters/decoders"
 	"github.com/rorycl/letters/email"
 )
 
-// parseBody parses the body of an email
+// parseBody parses the body of an email. Only plain text bodies are
+// parsed if the parser is set to process text only.
 func (se *stagedEmail) parseBody() error {
 
 	var err error
+	if se.parser.processType == textOnly && se.contentInfo.Type != "text/plain" {
+		return nil
+	}
 	switch se.contentInfo.Type {
 	case "text/plain":
 		se.email.Text, err = se.parseText(se.msg.Body, se.contentInfo)
 		if err != nil {
 			return fmt.Errorf("cannot parse plain text: %w", err)
 		}
+		if se.dropEmptyText(se.contentInfo, se.email.Text) {
+			return nil
+		}
+		se.addOrderedPart(email.OrderedPartText, se.email.Text, nil)
+		se.detectInlinePGP(se.email.Text)
 		return nil
 
 	case "text/enriched":
@@ -27,6 +35,10 @@ func (se *stagedEmail) parseBody() error {
 		if err != nil {
 			return fmt.Errorf("cannot parse enriched text: %w", err)
 		}
+		if se.dropEmptyText(se.contentInfo, se.email.EnrichedText) {
+			return nil
+		}
+		se.addOrderedPart(email.OrderedPartEnriched, se.email.EnrichedText, nil)
 		return nil
 
 	case "text/html":
@@ -34,6 +46,10 @@ func (se *stagedEmail) parseBody() error {
 		if err != nil {
 			return fmt.Errorf("cannot parse html text: %w", err)
 		}
+		if se.dropEmptyText(se.contentInfo, se.email.HTML) {
+			return nil
+		}
+		se.addOrderedPart(email.OrderedPartHTML, se.email.HTML, nil)
 		return nil
 	}
 	return fmt.Errorf("parse body content type %q not known", se.contentInfo.Type)
@@ -43,7 +59,7 @@ func (se *stagedEmail) parseBody() error {
 // parseText parses the text content of an email body or mime part. Note
 // that mime parts can be nested inside other mime parts.
 func (se *stagedEmail) parseText(t io.Reader, ci *email.ContentInfo) (string, error) {
-	reader := decoders.DecodeContent(t, ci)
+	reader := se.decodeContent(t, ci)
 	textBody, err := io.ReadAll(reader)
 	if err != nil {
 		return "", fmt.Errorf("cannot read plain text content: %w", err)
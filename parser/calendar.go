@@ -0,0 +1,224 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rorycl/letters/email"
+)
+
+// errNotCalendar is returned when text/calendar content lacks a
+// VCALENDAR object.
+var errNotCalendar = errors.New("no VCALENDAR object found")
+
+// calendarLine is an unfolded iCalendar content line of the form
+// name;param=value:value.
+type calendarLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldCalendar returns the unfolded content lines of an iCalendar
+// object. Lines beginning with a space or tab continue the previous
+// line.
+func unfoldCalendar(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitCalendarLine splits a content line into its uppercased name,
+// parameters and value, respecting quoted parameter values which may
+// contain colons and semicolons.
+func splitCalendarLine(s string) (calendarLine, bool) {
+	cl := calendarLine{params: map[string]string{}}
+	inQuote := false
+	fields := []string{}
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuote = !inQuote
+		case ';', ':':
+			if inQuote {
+				continue
+			}
+			fields = append(fields, s[start:i])
+			start = i + 1
+			if s[i] == ':' {
+				cl.value = s[start:]
+				cl.name = strings.ToUpper(fields[0])
+				for _, p := range fields[1:] {
+					k, v, _ := strings.Cut(p, "=")
+					cl.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+				}
+				return cl, cl.name != ""
+			}
+		}
+	}
+	return cl, false
+}
+
+// unescapeCalendarText unescapes an iCalendar TEXT value.
+func unescapeCalendarText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				b.WriteByte('\n')
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseCalendarTime parses an iCalendar DATE or DATE-TIME value,
+// reporting if it is a date.
+func parseCalendarTime(cl calendarLine) (time.Time, bool) {
+	if cl.params["VALUE"] == "DATE" || len(cl.value) == len("20060102") {
+		t, err := time.Parse("20060102", cl.value)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+	if t, err := time.Parse("20060102T150405Z", cl.value); err == nil {
+		return t, false
+	}
+	loc := time.UTC
+	if tzid := cl.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", cl.value, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, false
+}
+
+// calendarAttendee returns the organizer or attendee of a content line.
+func calendarAttendee(cl calendarLine) email.CalendarAttendee {
+	address := cl.value
+	if len(address) >= len("mailto:") && strings.EqualFold(address[:len("mailto:")], "mailto:") {
+		address = address[len("mailto:"):]
+	}
+	return email.CalendarAttendee{
+		Name:     cl.params["CN"],
+		Address:  address,
+		Role:     strings.ToUpper(cl.params["ROLE"]),
+		PartStat: strings.ToUpper(cl.params["PARTSTAT"]),
+	}
+}
+
+// parseCalendar summarises the iCalendar object in r. The properties
+// of components nested within events, such as alarms, are ignored.
+func parseCalendar(r io.Reader) (*email.Calendar, error) {
+	lines, err := unfoldCalendar(r)
+	if err != nil {
+		return nil, err
+	}
+	var cal *email.Calendar
+	var event *email.CalendarEvent
+	components := []string{}
+	for _, line := range lines {
+		cl, ok := splitCalendarLine(line)
+		if !ok {
+			continue
+		}
+		switch cl.name {
+		case "BEGIN":
+			component := strings.ToUpper(cl.value)
+			components = append(components, component)
+			switch {
+			case component == "VCALENDAR" && cal == nil:
+				cal = &email.Calendar{}
+			case component == "VEVENT" && cal != nil && len(components) == 2:
+				event = &email.CalendarEvent{}
+			}
+			continue
+		case "END":
+			if len(components) > 0 {
+				components = components[:len(components)-1]
+			}
+			if event != nil && len(components) == 1 {
+				cal.Events = append(cal.Events, *event)
+				event = nil
+			}
+			continue
+		}
+
+		switch {
+		case cal != nil && len(components) == 1 && cl.name == "METHOD":
+			cal.Method = strings.ToUpper(cl.value)
+		case event != nil && len(components) == 2:
+			applyEventProperty(event, cl)
+		}
+	}
+	if cal == nil {
+		return nil, errNotCalendar
+	}
+	return cal, nil
+}
+
+// applyEventProperty sets the event property of a content line.
+func applyEventProperty(event *email.CalendarEvent, cl calendarLine) {
+	switch cl.name {
+	case "UID":
+		event.UID = cl.value
+	case "SUMMARY":
+		event.Summary = unescapeCalendarText(cl.value)
+	case "DESCRIPTION":
+		event.Description = unescapeCalendarText(cl.value)
+	case "LOCATION":
+		event.Location = unescapeCalendarText(cl.value)
+	case "STATUS":
+		event.Status = strings.ToUpper(cl.value)
+	case "SEQUENCE":
+		event.Sequence, _ = strconv.Atoi(cl.value)
+	case "DTSTART":
+		event.Start, event.AllDay = parseCalendarTime(cl)
+	case "DTEND":
+		event.End, _ = parseCalendarTime(cl)
+	case "ORGANIZER":
+		organizer := calendarAttendee(cl)
+		event.Organizer = &organizer
+	case "ATTENDEE":
+		event.Attendees = append(event.Attendees, calendarAttendee(cl))
+	}
+}
+
+// addCalendar parses the content of a text/calendar file into
+// se.email.Calendars. Malformed calendars are recorded as warnings.
+func (se *stagedEmail) addCalendar(r io.Reader, file *email.File) {
+	cal, err := parseCalendar(r)
+	if err != nil {
+		se.warn(fmt.Errorf("cannot parse calendar %q: %w", file.Name, err))
+		return
+	}
+	se.email.Calendars = append(se.email.Calendars, cal)
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestSplitCalendarLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		name   string
+		params map[string]string
+		value  string
+		ok     bool
+	}{
+		{"SUMMARY:Lunch", "SUMMARY", map[string]string{}, "Lunch", true},
+		{"summary:a: b", "SUMMARY", map[string]string{}, "a: b", true},
+		{
+			`ATTENDEE;CN="Doe; John: Jr";ROLE=CHAIR:mailto:john@example.com`,
+			"ATTENDEE",
+			map[string]string{"CN": "Doe; John: Jr", "ROLE": "CHAIR"},
+			"mailto:john@example.com",
+			true,
+		},
+		{"DTSTART;TZID=Europe/London:20250411T120000", "DTSTART", map[string]string{"TZID": "Europe/London"}, "20250411T120000", true},
+		{"no colon", "", nil, "", false},
+		{":value", "", nil, "", false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			cl, ok := splitCalendarLine(tt.line)
+			if got, want := ok, tt.ok; got != want {
+				t.Fatalf("got ok %t want %t", got, want)
+			}
+			if !ok {
+				return
+			}
+			if got, want := cl.name, tt.name; got != want {
+				t.Errorf("got name %q want %q", got, want)
+			}
+			if got, want := cl.value, tt.value; got != want {
+				t.Errorf("got value %q want %q", got, want)
+			}
+			if diff := cmp.Diff(tt.params, cl.params); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseCalendarObject(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//Example//Calendar//EN",
+		"METHOD:request",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/London",
+		"BEGIN:STANDARD",
+		"DTSTART:19701025T020000",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:event-1@example.com",
+		"SEQUENCE:2",
+		"STATUS:CONFIRMED",
+		`SUMMARY:Planning\, budget\; and`,
+		"  roadmap",
+		"DESCRIPTION:Line one\\nLine two",
+		"LOCATION:Room 1",
+		"DTSTART;TZID=Europe/London:20250411T120000",
+		"DTEND:20250411T123000Z",
+		`ORGANIZER;CN="Alice Example":MAILTO:alice@example.com`,
+		"ATTENDEE;CN=Bob;ROLE=REQ-PARTICIPANT;PARTSTAT=needs-action:mailto:bob@",
+		" example.net",
+		"BEGIN:VALARM",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:event-2@example.com",
+		"DTSTART;VALUE=DATE:20250412",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	cal, err := parseCalendar(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("time zone data unavailable")
+	}
+	want := &email.Calendar{
+		Method: "REQUEST",
+		Events: []email.CalendarEvent{
+			{
+				UID:         "event-1@example.com",
+				Summary:     "Planning, budget; and roadmap",
+				Description: "Line one\nLine two",
+				Location:    "Room 1",
+				Status:      "CONFIRMED",
+				Sequence:    2,
+				Start:       time.Date(2025, 4, 11, 12, 0, 0, 0, london),
+				End:         time.Date(2025, 4, 11, 12, 30, 0, 0, time.UTC),
+				Organizer:   &email.CalendarAttendee{Name: "Alice Example", Address: "alice@example.com"},
+				Attendees: []email.CalendarAttendee{
+					{Name: "Bob", Address: "bob@example.net", Role: "REQ-PARTICIPANT", PartStat: "NEEDS-ACTION"},
+				},
+			},
+			{
+				UID:    "event-2@example.com",
+				Start:  time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, cal); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := parseCalendar(strings.NewReader("SUMMARY:not a calendar")); !errors.Is(err, errNotCalendar) {
+		t.Errorf("got error %v want %v", err, errNotCalendar)
+	}
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+// "comments" provides a lenient address list func used by
+// WithAddressComments which removes RFC 5322 parenthetical comments
+// from addresses before parsing, recording the comments of each
+// address.
+
+// commentedAddress is an address from an address list with its
+// comments removed
+type commentedAddress struct {
+	address  string
+	comments []string
+	trailing string // comment ending the address, if any
+}
+
+// readComment reads the comment starting at s[i], which should be the
+// opening '(', returning the comment text and the index after the
+// closing ')'. Nested comments are retained in the text, quoted-pairs
+// are unescaped and whitespace is collapsed. An unterminated comment
+// runs to the end of s.
+func readComment(s string, i int) (string, int) {
+	var b strings.Builder
+	depth := 0
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == '(':
+			if depth > 0 {
+				b.WriteByte(c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return strings.Join(strings.Fields(b.String()), " "), i + 1
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " "), i
+}
+
+// splitCommentedAddresses splits an address list on top-level commas,
+// removing the comments from each address. Quoted strings and angle
+// brackets are respected. Group names, such as "Team:" in "Team:
+// a@example.com, b@example.com;", are discarded so that group members
+// are returned as individual addresses.
+func splitCommentedAddresses(list string) []commentedAddress {
+	addresses := []commentedAddress{}
+	var b strings.Builder
+	var comments []string
+	commentEnd := -1 // position in b after the last comment
+	add := func() {
+		a := strings.TrimSpace(b.String())
+		if a != "" {
+			ca := commentedAddress{address: a, comments: comments}
+			if commentEnd >= 0 && strings.TrimSpace(b.String()[commentEnd:]) == "" {
+				ca.trailing = comments[len(comments)-1]
+			}
+			addresses = append(addresses, ca)
+		}
+		b.Reset()
+		comments, commentEnd = nil, -1
+	}
+	inQuote, inAngle := false, false
+	for i := 0; i < len(list); i++ {
+		switch c := list[i]; {
+		case inQuote && c == '\\' && i+1 < len(list):
+			b.WriteString(list[i : i+2])
+			i++
+		case c == '"':
+			inQuote = !inQuote
+			b.WriteByte(c)
+		case inQuote:
+			b.WriteByte(c)
+		case c == '(':
+			comment, next := readComment(list, i)
+			b.WriteByte(' ')
+			if comment != "" {
+				comments = append(comments, comment)
+				commentEnd = b.Len()
+			}
+			i = next - 1
+		case c == '<':
+			inAngle = true
+			b.WriteByte(c)
+		case c == '>':
+			inAngle = false
+			b.WriteByte(c)
+		case c == ':' && !inAngle:
+			b.Reset()
+			comments, commentEnd = nil, -1
+		case (c == ',' || c == ';') && !inAngle:
+			add()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	add()
+	return addresses
+}
+
+// commentedParseAddressList parses a list of addresses after removing
+// their comments. Where an address lacks a display name a comment
+// following it is used as the name, as is done by net/mail.
+func commentedParseAddressList(list string) ([]*mail.Address, error) {
+	addresses := []*mail.Address{}
+	for _, ca := range splitCommentedAddresses(list) {
+		a, err := mail.ParseAddress(ca.address)
+		if err != nil {
+			return nil, err
+		}
+		if a.Name == "" {
+			a.Name = ca.trailing
+		}
+		addresses = append(addresses, a)
+	}
+	if len(addresses) == 0 {
+		return nil, errors.New("mail: no address")
+	}
+	return addresses, nil
+}
+
+// addressComments returns the comments of the addresses in a list
+// keyed by address, or nil if there are none. Addresses which cannot be
+// parsed are ignored.
+func addressComments(list string) map[string][]string {
+	comments := map[string][]string{}
+	for _, ca := range splitCommentedAddresses(list) {
+		if len(ca.comments) == 0 {
+			continue
+		}
+		a, err := mail.ParseAddress(ca.address)
+		if err != nil {
+			continue
+		}
+		comments[a.Address] = append(comments[a.Address], ca.comments...)
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	return comments
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCommentedParseAddressList(t *testing.T) {
+	tests := []struct {
+		list     string
+		want     []*mail.Address
+		comments map[string][]string
+	}{
+		{
+			list:     "alice@example.com",
+			want:     []*mail.Address{{Address: "alice@example.com"}},
+			comments: nil,
+		},
+		{
+			list:     "(Primary) john@example.com (work)",
+			want:     []*mail.Address{{Name: "work", Address: "john@example.com"}},
+			comments: map[string][]string{"john@example.com": {"Primary", "work"}},
+		},
+		{
+			list:     `John (the) Smith <john@example.com>, "Smith (not a comment)" <js@example.com>`,
+			want:     []*mail.Address{{Name: "John Smith", Address: "john@example.com"}, {Name: "Smith (not a comment)", Address: "js@example.com"}},
+			comments: map[string][]string{"john@example.com": {"the"}},
+		},
+		{
+			list:     `alice@example.com (nested (comment) \) here)`,
+			want:     []*mail.Address{{Name: "nested (comment) ) here", Address: "alice@example.com"}},
+			comments: map[string][]string{"alice@example.com": {"nested (comment) ) here"}},
+		},
+		{
+			list:     "Team: (lead) alice@example.com, bob@example.com (deputy);",
+			want:     []*mail.Address{{Address: "alice@example.com"}, {Name: "deputy", Address: "bob@example.com"}},
+			comments: map[string][]string{"alice@example.com": {"lead"}, "bob@example.com": {"deputy"}},
+		},
+		{
+			list:     "alice@example.com (unterminated",
+			want:     []*mail.Address{{Name: "unterminated", Address: "alice@example.com"}},
+			comments: map[string][]string{"alice@example.com": {"unterminated"}},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			got, err := commentedParseAddressList(tt.list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.comments, addressComments(tt.list)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestOptAddressComments(t *testing.T) {
+
+	open := func() *os.File {
+		f, err := os.Open("testdata/address_comments.eml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	// net/mail rejects the leading comment
+	f := open()
+	defer f.Close()
+	if _, err := NewParser().Parse(f); err == nil {
+		t.Fatal("expected default parsing error")
+	}
+
+	g := open()
+	defer g.Close()
+	em, err := NewParser(WithAddressComments()).Parse(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*mail.Address{
+		{Name: "work", Address: "john@example.com"},
+		{Name: "Doe, Jane", Address: "jane@example.com"},
+		{Address: "records@example.org"},
+	}
+	if diff := cmp.Diff(want, em.Headers.From); diff != "" {
+		t.Error(diff)
+	}
+	wantComments := map[string][]string{
+		"john@example.com":    {"Primary", "work"},
+		"jane@example.com":    {"home (main)"},
+		"records@example.org": {"audit copy"},
+	}
+	if diff := cmp.Diff(wantComments, em.Headers.FromComments); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOptAddressCommentsPrecedence(t *testing.T) {
+
+	custom := func(list string) ([]*mail.Address, error) {
+		return []*mail.Address{{Address: "custom@example.com"}}, nil
+	}
+	tests := []struct {
+		opts []Opt
+		from string
+	}{
+		{[]Opt{WithAddressComments(), WithCustomAddressesFunc(custom)}, "custom@example.com"},
+		{[]Opt{WithCustomAddressesFunc(custom), WithAddressComments()}, "john@example.com"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/address_comments.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			em, err := NewParser(tt.opts...).Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Headers.From[0].Address, tt.from; got != want {
+				t.Errorf("got from %q want %q", got, want)
+			}
+			// comments are captured regardless of the order
+			if got, want := len(em.Headers.FromComments), 3; got != want {
+				t.Errorf("got %d from comments want %d", got, want)
+			}
+		})
+	}
+}
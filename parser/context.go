@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrTimeout is returned when parsing is aborted as it exceeded the
+// duration set by WithTimeout.
+var ErrTimeout = errors.New("parse timed out")
+
+// contextReader is an io.Reader which fails with the context's error
+// once the context is cancelled or its deadline exceeded. Since all
+// reads of the email, including those of attachments, pass through
+// the reader provided to the parser, this interrupts long running
+// reads.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAttachmentTooLarge is returned when the decoded content of a file
+// exceeds the size set by WithMaxAttachmentSize.
+var ErrAttachmentTooLarge = errors.New("attachment too large")
+
+// ErrPartTooLarge is returned when the decoded content of a part
+// exceeds the size set by WithMaxPartSize.
+var ErrPartTooLarge = errors.New("part too large")
+
+// ErrMessageTooLarge is returned when a message exceeds the size set by
+// WithMaxMessageSize.
+var ErrMessageTooLarge = errors.New("message too large")
+
+// SizeLimitExceededError is returned when a message, part or file
+// exceeds a size limit. It matches ErrMessageTooLarge, ErrPartTooLarge
+// or ErrAttachmentTooLarge, according to the limit exceeded, with
+// errors.Is.
+type SizeLimitExceededError struct {
+	// What describes the content exceeding the limit, such as
+	// "message" or `part of type "text/plain"`.
+	What string
+	// Limit is the limit exceeded, in bytes.
+	Limit int64
+	// tooLarge is the sentinel error matched by Is.
+	tooLarge error
+}
+
+func (e *SizeLimitExceededError) Error() string {
+	return fmt.Sprintf("%v: %s exceeds %d bytes", e.tooLarge, e.What, e.Limit)
+}
+
+func (e *SizeLimitExceededError) Is(target error) bool {
+	return target == e.tooLarge
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// externalBodyFileType is the email.File FileType of
+// message/external-body parts
+const externalBodyFileType string = "external"
+
+// parseExternalBody parses a message/external-body part into an
+// email.File without data describing the externally stored content.
+// The content is not fetched. The body of the part, which holds the
+// headers of the external content, is discarded.
+func (se *stagedEmail) parseExternalBody(r io.Reader, ci *email.ContentInfo) error {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return fmt.Errorf("cannot read external body: %w", err)
+	}
+
+	params := ci.TypeParams
+	ext := &email.ExternalBody{
+		AccessType: strings.ToLower(params["access-type"]),
+		URL:        strings.Join(strings.Fields(params["url"]), ""),
+		Size:       params["size"],
+		Expiration: params["expiration"],
+	}
+
+	name := params["name"]
+	if name == "" && ext.URL != "" {
+		if u, err := url.Parse(ext.URL); err == nil {
+			if base := path.Base(u.Path); base != "/" && base != "." {
+				name = base
+			}
+		}
+	}
+	if name == "" {
+		name = fmt.Sprintf("external_%d", len(se.email.Files))
+	}
+
+	file := &email.File{
+		FileType:     externalBodyFileType,
+		Name:         path.Base(path.Clean(name)),
+		Index:        se.partIndex,
+		Location:     ci.Location,
+		ContentInfo:  ci,
+		ExternalBody: ext,
+	}
+	se.email.Files = append(se.email.Files, file)
+	se.addOrderedPart(email.OrderedPartFile, "", file)
+	return nil
+}
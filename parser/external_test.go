@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestParseExternalBody(t *testing.T) {
+
+	f, err := os.Open("testdata/external_body.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Text, "The dataset is too large to attach and is available below."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := len(em.Files), 2; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+
+	tests := []struct {
+		name string
+		ext  *email.ExternalBody
+	}{
+		{
+			name: "survey-2024.tar.gz",
+			ext: &email.ExternalBody{
+				AccessType: "url",
+				URL:        "https://data.example.org/sets/survey-2024.tar.gz",
+				Size:       "1073741824",
+				Expiration: "Thu, 31 Dec 2026 23:59:59 +0000",
+			},
+		},
+		{
+			name: "readme.txt",
+			ext: &email.ExternalBody{
+				AccessType: "anon-ftp",
+			},
+		},
+	}
+	for i, tt := range tests {
+		file := em.Files[i]
+		if got, want := file.FileType, "external"; got != want {
+			t.Errorf("file %d got type %q want %q", i, got, want)
+		}
+		if got, want := file.Name, tt.name; got != want {
+			t.Errorf("file %d got name %q want %q", i, got, want)
+		}
+		if diff := cmp.Diff(tt.ext, file.ExternalBody); diff != "" {
+			t.Errorf("file %d: %s", i, diff)
+		}
+		if len(file.Data) != 0 {
+			t.Errorf("file %d should have no data", i)
+		}
+	}
+}
+
+func TestParseExternalBodyTopLevel(t *testing.T) {
+	msg := "From: a@example.com\r\nSubject: data\r\n" +
+		"Content-Type: message/external-body; access-type=URL;\r\n" +
+		" URL=\"https://data.example.org/sets/survey.tar.gz\"\r\n\r\n" +
+		"Content-Type: application/x-tar\r\n\r\n"
+
+	tests := []struct {
+		opts  []Opt
+		files int
+	}{
+		{nil, 1},
+		{[]Opt{WithoutAttachments()}, 0},
+		{[]Opt{WithTextOnly()}, 0},
+		{[]Opt{WithHeadersOnly()}, 0},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(em.Files), tt.files; got != want {
+				t.Errorf("got %d files want %d", got, want)
+			}
+		})
+	}
+}
@@ -1,14 +1,57 @@
 package parser
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"mime"
 	"path/filepath"
+	"strconv"
+	"strings"
 
-	"github.com/rorycl/letters/decoders"
 	"github.com/rorycl/letters/email"
 )
 
+// extensionTypes are fallback media types for common file extensions
+// which may not be registered with the mime package on all systems.
+var extensionTypes = map[string]string{
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".gz":   "application/gzip",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".pdf":  "application/pdf",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".rtf":  "application/rtf",
+	".tar":  "application/x-tar",
+	".txt":  "text/plain",
+	".xls":  "application/vnd.ms-excel",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".zip":  "application/zip",
+}
+
+// inferTypeByExtension infers the media type, without parameters, of a
+// file from its file name extension, returning an empty string if the
+// extension is not known.
+func inferTypeByExtension(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" {
+		return ""
+	}
+	if t, ok := extensionTypes[ext]; ok {
+		return t
+	}
+	t, _, err := mime.ParseMediaType(mime.TypeByExtension(ext))
+	if err != nil {
+		return ""
+	}
+	return t
+}
+
 // parseFile parses inline and attached files from email parts, using
 // the parser.fileFunc to process the io.Reader returned by
 // decoders.DecodeContent. By default this func will write the reader
@@ -19,13 +62,22 @@ import (
 // results for the consumer.
 //
 // Files that are successfully parsed are added to parser.email.Files.
+// Enclosed message/rfc822 files, such as forwarded emails, are also
+// parsed into parser.email.SubMessages from the content retained by
+// the file func, or spooled if it retains none, and text/calendar
+// files into parser.email.Calendars.
 func (se *stagedEmail) parseFile(r io.Reader, ci *email.ContentInfo) error {
 
 	var err error
 	file := &email.File{
 		FileType:    ci.Disposition,
+		Index:       se.partIndex,
+		Location:    ci.Location,
 		ContentInfo: ci,
 	}
+	if file.FileType == "" && ci.Type == "text/calendar" {
+		file.FileType = "calendar"
+	}
 
 	// extract file name from filename or name field
 	// RFC 2183 limits filenames to the US-ASCII printable range only.
@@ -49,21 +101,199 @@ func (se *stagedEmail) parseFile(r io.Reader, ci *email.ContentInfo) error {
 			tmpFileName = name
 		} else {
 			// Make up a unique name if none exists. Todo: Suffix ideally needed.
-			tmpFileName = fmt.Sprintf("attachment_%d_%s", len(se.email.Files), ci.Disposition)
+			tmpFileName = fmt.Sprintf("attachment_%d_%s", len(se.email.Files), file.FileType)
 		}
 	}
 	file.Name = filepath.Base(filepath.Clean(tmpFileName))
 
-	file.Reader = decoders.DecodeContent(r, ci)
+	if ci.Description != "" {
+		file.Caption, err = se.parser.decodeHeader(ci.Description)
+		if err != nil {
+			file.Caption = ci.Description
+			se.warn(fmt.Errorf("cannot decode description of %q: %w", file.Name, err))
+		}
+	}
+	file.Duration = ci.Duration
+
+	if se.parser.inferContentType && ci.Type == "application/octet-stream" {
+		file.InferredType = inferTypeByExtension(file.Name)
+	}
+
+	file.Reader = se.decodeContent(r, ci)
+	if se.parser.detectMagicBytes {
+		detectMagicBytes(file)
+	}
+	if limit := se.parser.maxAttachmentSize; limit > 0 {
+		file.MaxSize = limit
+		file.Reader = &sizeLimitReader{
+			r:        file.Reader,
+			desc:     strconv.Quote(file.Name),
+			tooLarge: ErrAttachmentTooLarge,
+			max:      limit,
+		}
+	}
+	var checksum hash.Hash
+	if se.parser.fileChecksums {
+		checksum = sha256.New()
+		file.Reader = io.TeeReader(file.Reader, checksum)
+	}
+	// spool an enclosed message, such as a forwarded email, for
+	// parsing into SubMessages if it is not retained by the file func
+	var enclosed *spoolWriter
+	if ci.Type == "message/rfc822" && !se.parser.fileFuncRetains && !se.messageDepthExceeded() {
+		enclosed = &spoolWriter{threshold: enclosedSpoolThreshold}
+		defer enclosed.discard()
+		file.Reader = io.TeeReader(file.Reader, enclosed)
+	}
+	// capture a calendar, such as an invitation, for parsing into
+	// Calendars
+	var calendar *bytes.Buffer
+	if ci.Type == "text/calendar" {
+		calendar = &bytes.Buffer{}
+		file.Reader = io.TeeReader(file.Reader, calendar)
+	}
+	// check for cancellation before reading the file
+	if err := se.ctx.Err(); err != nil {
+		return err
+	}
 	// parser.fileFunc is a pluggable file reader with the signature
 	// func(*email.File) error.
 	// The fileFunc may be customised through parser.NewParser(...opts).
 	err = se.parser.fileFunc(file)
 	if err != nil {
-		return fmt.Errorf("could not read attachment data: %w", err)
+		if !se.isolatePartError(fmt.Errorf("could not read attachment %q data: %w", file.Name, err), file.ContentInfo) {
+			return fmt.Errorf("could not read attachment data: %w", err)
+		}
+		file.DecodeError = err
+	}
+	// release any content retained by the file func, such as the
+	// temporary file of WithLazyFiles, if the file is not added to
+	// the email's Files, which are closed if the parse fails
+	discard := func(err error) error {
+		if file.Lazy != nil {
+			_ = file.Lazy.Close()
+		}
+		return err
+	}
+	if (checksum != nil || enclosed != nil || calendar != nil) && file.DecodeError == nil {
+		// complete the checksum, enclosed message or calendar with
+		// content not read by the file func
+		if _, err := io.Copy(io.Discard, file.Reader); err != nil {
+			return discard(fmt.Errorf("could not read remaining attachment data: %w", err))
+		}
+	}
+	if checksum != nil && file.DecodeError == nil {
+		file.SHA256 = hex.EncodeToString(checksum.Sum(nil))
+	}
+	if ci.Type == "message/rfc822" && file.DecodeError == nil {
+		if err := se.parseEnclosedFile(file, enclosed); err != nil {
+			return discard(err)
+		}
+	}
+	if calendar != nil && file.DecodeError == nil {
+		se.addCalendar(calendar, file)
 	}
 
 	se.email.Files = append(se.email.Files, file)
+	if id := strings.Trim(ci.ID, idTrimCutset); id != "" {
+		if se.email.ContentIDFiles == nil {
+			se.email.ContentIDFiles = map[string]*email.File{}
+		}
+		se.email.ContentIDFiles[id] = file
+	}
+	se.addOrderedPart(email.OrderedPartFile, "", file)
 	return nil
 
 }
+
+// enclosedSpoolThreshold is the size in bytes beyond which an enclosed
+// message not retained by the file func is spooled to a temporary file
+// for parsing.
+const enclosedSpoolThreshold int64 = 1 << 20
+
+// parseEnclosedFile parses the enclosed message of a message/rfc822
+// file into SubMessages, reading it from the content retained by the
+// file func or, failing that, from the spool. A malformed enclosed
+// message does not fail the parse, as the file is retained, but is
+// recorded as a warning.
+func (se *stagedEmail) parseEnclosedFile(file *email.File, spool *spoolWriter) error {
+	var r io.Reader
+	switch {
+	case spool != nil:
+		lazy, err := spool.lazyData(nil)
+		if err != nil {
+			se.warn(fmt.Errorf("cannot spool enclosed message %q: %w", file.Name, err))
+			return nil
+		}
+		defer func() {
+			_ = lazy.Close()
+		}()
+		rc, err := lazy.Open()
+		if err != nil {
+			se.warn(fmt.Errorf("cannot read enclosed message %q: %w", file.Name, err))
+			return nil
+		}
+		defer func() {
+			_ = rc.Close()
+		}()
+		r = rc
+	case file.Lazy != nil:
+		rc, err := file.Lazy.Open()
+		if err != nil {
+			se.warn(fmt.Errorf("cannot read enclosed message %q: %w", file.Name, err))
+			return nil
+		}
+		defer func() {
+			_ = rc.Close()
+		}()
+		r = rc
+	default:
+		r = bytes.NewReader(file.Data)
+	}
+	err := se.parseSubMessage(r)
+	if errors.Is(err, ErrMaxPartDepthExceeded) {
+		return err
+	}
+	if err != nil {
+		se.warn(fmt.Errorf("cannot parse enclosed message %q: %w", file.Name, err))
+	}
+	return nil
+}
+
+// parseUnhandledPart records a part of an unknown content type in
+// parser.email.UnhandledParts. The raw content of the part is read
+// into file.Data only when processing the whole email, limited to the
+// parser's maximum attachment and part sizes, if any.
+func (se *stagedEmail) parseUnhandledPart(r io.Reader, ci *email.ContentInfo) error {
+	file := &email.File{
+		FileType:    ci.Disposition,
+		Index:       se.partIndex,
+		Location:    ci.Location,
+		ContentInfo: ci,
+	}
+	name, ok := ci.DispositionParams["filename"]
+	if !ok {
+		name = ci.TypeParams["name"]
+	}
+	if name != "" {
+		file.Name = filepath.Base(filepath.Clean(name))
+	}
+	if se.parser.processType == wholeEmail {
+		if limit := se.parser.maxAttachmentSize; limit > 0 {
+			r = &sizeLimitReader{
+				r:        r,
+				desc:     "unhandled " + strconv.Quote(ci.Type) + " part",
+				tooLarge: ErrAttachmentTooLarge,
+				max:      limit,
+			}
+		}
+		r = se.limitPart(r, ci)
+		var err error
+		if file.Data, err = io.ReadAll(r); err != nil {
+			return err
+		}
+	}
+	se.email.UnhandledParts = append(se.email.UnhandledParts, file)
+	se.email.DecodeStats.UnknownSkipped++
+	return nil
+}
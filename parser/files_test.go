@@ -2,9 +2,12 @@ package parser
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/rorycl/letters/email"
 )
 
@@ -98,3 +101,187 @@ VGV4dC9wbGFpbiBjb250ZW50IGFzIGFuIGF0dGFjaGVkIC50eHQgZmlsZS4=`,
 		})
 	}
 }
+
+func TestInferContentType(t *testing.T) {
+
+	tests := []struct {
+		contentType  string
+		fileName     string
+		inferredType string
+	}{
+		{"application/octet-stream", "report.pdf", "application/pdf"},
+		{"application/octet-stream", "Letter.DOCX", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"application/octet-stream", "archive.zip", "application/zip"},
+		{"application/octet-stream", "cat.png", "image/png"},
+		{"application/octet-stream", "no_extension", ""},
+		{"application/octet-stream", "unknown.xyzzy", ""},
+		{"application/pdf", "report.zip", ""}, // declared type not generic
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			p := NewParser(WithInferContentType())
+			se := newStagedEmail(p)
+			ci := &email.ContentInfo{
+				Type:              tt.contentType,
+				Disposition:       "attachment",
+				DispositionParams: map[string]string{"filename": tt.fileName},
+				TransferEncoding:  "8bit",
+			}
+			err := se.parseFile(strings.NewReader("content"), ci)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := se.email.Files[0]
+			if got, want := file.InferredType, tt.inferredType; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+			if got, want := file.ContentInfo.Type, tt.contentType; got != want {
+				t.Errorf("declared type got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFileMediaMetadata(t *testing.T) {
+
+	f, err := os.Open("testdata/voicemail.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Files), 1; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	file := em.Files[0]
+	if got, want := file.Duration, 33*time.Second; got != want {
+		t.Errorf("got duration %s want %s", got, want)
+	}
+	if got, want := file.Caption, "Voicemail from André"; got != want {
+		t.Errorf("got caption %q want %q", got, want)
+	}
+}
+
+func TestFileCaptionUndecodable(t *testing.T) {
+	description := "=?x-unknown?Q?Voicemail?="
+	msg := "From: a@example.com\r\nSubject: x\r\n" +
+		"Content-Type: multipart/mixed; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nHello\r\n" +
+		"--b\r\nContent-Type: audio/wav\r\n" +
+		"Content-Description: " + description + "\r\n" +
+		"Content-Disposition: attachment; filename=\"vm.wav\"\r\n\r\n" +
+		"data\r\n--b--\r\n"
+
+	// without a charset fallback the unknown charset cannot be decoded
+	em, err := NewParser(WithCharsetFallback("")).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Files), 1; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	if got, want := em.Files[0].Caption, description; got != want {
+		t.Errorf("got caption %q want %q", got, want)
+	}
+	if got, want := len(em.Warnings), 1; got != want {
+		t.Fatalf("got %d warnings want %d: %v", got, want, em.Warnings)
+	}
+	if !strings.Contains(em.Warnings[0].Error(), `"vm.wav"`) {
+		t.Errorf("warning %q does not name the file", em.Warnings[0])
+	}
+}
+
+func TestContentIDFiles(t *testing.T) {
+
+	f, err := os.Open("testdata/content_ids.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Files), 5; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	got := map[string]string{}
+	for id, file := range em.ContentIDFiles {
+		got[id] = string(file.Data)
+	}
+	// the last of duplicate Content-IDs wins, and attachments with a
+	// Content-ID are indexed
+	want := map[string]string{
+		"logo@example.com":   "logo",
+		"dup@example.com":    "second",
+		"report@example.com": "report",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected content id files (-want +got):\n%s", diff)
+	}
+	if got, want := em.ContentIDFiles["report@example.com"], em.Files[3]; got != want {
+		t.Errorf("expected indexed file to be the same as in Files")
+	}
+}
+
+func TestRFC2231FileNames(t *testing.T) {
+
+	f, err := os.Open("testdata/rfc2231_filenames.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, file := range em.Files {
+		names = append(names, file.Name)
+	}
+	if diff := cmp.Diff([]string{"รายงาน.pdf", "Übersicht März.pdf"}, names); diff != "" {
+		t.Errorf("unexpected file names (-want +got):\n%s", diff)
+	}
+	if got, want := em.Files[0].ContentInfo.TypeParams["name"], "รายงาน.pdf"; got != want {
+		t.Errorf("got type name %q want %q", got, want)
+	}
+}
+
+func TestFileIndexAndLocation(t *testing.T) {
+
+	f, err := os.Open("testdata/related_locations.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type indexedFile struct {
+		Index    int
+		Type     string
+		Location string
+	}
+	got := []indexedFile{}
+	for _, file := range em.Files {
+		got = append(got, indexedFile{file.Index, file.ContentInfo.Type, file.Location})
+	}
+	// the alternative, plain text, related and html parts precede the
+	// images in depth-first order
+	want := []indexedFile{
+		{4, "image/png", "images/logo.png"},
+		{5, "image/png", "http://example.com/news/images/banner.png"},
+		{6, "application/pdf", ""},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+}
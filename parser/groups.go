@@ -0,0 +1,55 @@
+package parser
+
+import "net/mail"
+
+// "groups" provides the default address list func, which supports RFC
+// 5322 group syntax such as "Team: alice@example.com, bob@example.com;"
+// including groups sent without their terminating semicolon.
+
+// hasUnterminatedGroup reports if an address list opens a group, such
+// as "undisclosed-recipients:", which is not terminated by a semicolon.
+func hasUnterminatedGroup(s string) bool {
+	inAngle, inGroup := false, false
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '"', '(':
+			next, err := skipQuoted(s, i, 0)
+			if err != nil {
+				return false
+			}
+			i = next
+			continue
+		case '<':
+			inAngle = true
+		case '>':
+			inAngle = false
+		case ':':
+			if !inAngle {
+				inGroup = true
+			}
+		case ';':
+			if !inAngle {
+				inGroup = false
+			}
+		}
+		i++
+	}
+	return inGroup
+}
+
+// groupParseAddressList parses a list of addresses with
+// net/mail.ParseAddressList, which flattens the members of groups into
+// the list and returns no addresses for an empty group such as
+// "Undisclosed recipients:;". A list which fails to parse as its last
+// group lacks a terminating semicolon is parsed again with the group
+// terminated.
+func groupParseAddressList(list string) ([]*mail.Address, error) {
+	addresses, err := mail.ParseAddressList(list)
+	if err == nil || !hasUnterminatedGroup(list) {
+		return addresses, err
+	}
+	if terminated, terr := mail.ParseAddressList(list + ";"); terr == nil {
+		return terminated, nil
+	}
+	return addresses, err
+}
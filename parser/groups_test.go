@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroupParseAddressList(t *testing.T) {
+	tests := []struct {
+		list      string
+		addresses []string
+		isErr     bool
+	}{
+		{list: "alice@example.com", addresses: []string{"alice@example.com"}},
+		{list: "Team: alice@example.com, Bob <bob@example.net>;", addresses: []string{"alice@example.com", "bob@example.net"}},
+		{list: "A: a@example.com;, B: b@example.com;, c@example.com", addresses: []string{"a@example.com", "b@example.com", "c@example.com"}},
+		{list: "Undisclosed recipients:;", addresses: []string{}},
+		{list: "undisclosed-recipients:", addresses: []string{}},
+		{list: "alice@example.com, Team: bob@example.net, carol@example.org", addresses: []string{"alice@example.com", "bob@example.net", "carol@example.org"}},
+		{list: `"Team: West" <west@example.com>`, addresses: []string{"west@example.com"}},
+		{list: "Team: alice@", isErr: true},
+		{list: "alice@example.com,bob", isErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			list, err := groupParseAddressList(tt.list)
+			if got, want := err != nil, tt.isErr; got != want {
+				t.Fatalf("got error %t want %t (%v)", got, want, err)
+			}
+			if tt.isErr {
+				return
+			}
+			got := []string{}
+			for _, a := range list {
+				got = append(got, a.Address)
+			}
+			if diff := cmp.Diff(tt.addresses, got); diff != "" {
+				t.Errorf("unexpected addresses (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseGroupHeaders(t *testing.T) {
+
+	msg := "From: alice@example.com\r\n" +
+		"To: undisclosed-recipients:\r\n" +
+		"Cc: Team: bob@example.net, carol@example.org;\r\n" +
+		"Subject: groups\r\n" +
+		"\r\nBody.\r\n"
+
+	em, err := NewParser().Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Headers.To), 0; got != want {
+		t.Errorf("got %d to addresses want %d", got, want)
+	}
+	if got, want := len(em.Headers.Cc), 2; got != want {
+		t.Errorf("got %d cc addresses want %d", got, want)
+	}
+	if got, want := len(em.Warnings), 0; got != want {
+		t.Errorf("got %d warnings want %d: %v", got, want, em.Warnings)
+	}
+}
@@ -3,16 +3,25 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/rorycl/letters/decoders"
+	"github.com/rorycl/letters/email"
 )
 
 var (
 	errorEmptyAddress error = errors.New("empty address")
 	errorEmptyDate    error = errors.New("empty date")
+
+	// ErrTooManyHeaders is returned when the number of header lines
+	// exceeds that set by WithMaxHeaders.
+	ErrTooManyHeaders error = errors.New("too many headers")
 )
 
 // explicitHeaders are those headers stored in their own field in
@@ -39,9 +48,15 @@ var explicitHeaders = []string{
 	"Resent-Cc",
 	"Resent-Bcc",
 	"Resent-Message-Id",
+	"Original-Message-Id",
+	"X-Original-Message-Id",
+	"Auto-Submitted",
+	"Archived-At",
+	"List-Archive",
 	"Content-Transfer-Encoding",
 	"Content-Type",
 	"Content-Disposition",
+	"Content-Language",
 }
 
 // isExplicitHeader checks if the header is to be registered as a field.
@@ -56,22 +71,167 @@ func isExplicitHeader(s string) bool {
 	return false
 }
 
+// zonelessDateLayouts are the layouts tried when interpreting a date
+// lacking a timezone in a default location.
+var zonelessDateLayouts = []string{
+	"Mon, 2 Jan 2006 15:04:05",
+	"Mon, 2 Jan 2006 15:04",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+}
+
+// parseDateInLocation parses a date without a timezone in the provided
+// location.
+func parseDateInLocation(s string, loc *time.Location) (time.Time, error) {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, layout := range zonelessDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse date %q in location %s", s, loc)
+}
+
+// getKeyword returns the lowercased keyword from a header value such
+// as Auto-Submitted, removing any parameters and comments.
+func getKeyword(s string) string {
+	s, _, _ = strings.Cut(s, ";")
+	s, _, _ = strings.Cut(s, "(")
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// getURIs returns the valid absolute URIs from a header such as
+// List-Archive holding a comma separated list of URIs in angle
+// brackets. A value without angle brackets is treated as a single URI.
+func getURIs(s string) []string {
+	candidates := []string{}
+	for rest := s; ; {
+		start := strings.IndexByte(rest, '<')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '>')
+		if end < 0 {
+			break
+		}
+		candidates = append(candidates, rest[start+1:start+end])
+		rest = rest[start+end+1:]
+	}
+	if len(candidates) == 0 && strings.TrimSpace(s) != "" {
+		candidates = append(candidates, s)
+	}
+	uris := []string{}
+	for _, c := range candidates {
+		c = strings.Join(strings.Fields(c), "")
+		if u, err := url.Parse(c); err == nil && u.Scheme != "" {
+			uris = append(uris, c)
+		}
+	}
+	return uris
+}
+
+// headerWhitespaceRegexp matches runs of spaces and tabs
+var headerWhitespaceRegexp = regexp.MustCompile(`[ \t]+`)
+
+// collapseWhitespace collapses runs of spaces and tabs in a decoded
+// header value to a single space, trimming surrounding whitespace.
+func collapseWhitespace(s string) string {
+	return strings.TrimSpace(headerWhitespaceRegexp.ReplaceAllString(s, " "))
+}
+
+// decodeHeader decodes MIME encoded-words in a header value, using the
+// parser's fallback charset for words declaring an unknown charset.
+func (p *Parser) decodeHeader(s string) (string, error) {
+	return decoders.DecodeHeaderFallback(s, p.charsetFallback)
+}
+
+// repairEncodedWord decodes a header value consisting of a single
+// overlong RFC 2047 encoded-word which has been broken by folding
+// whitespace, as sent by clients ignoring the 75 character limit on
+// encoded-words, reporting if a repair was made. Such values are
+// otherwise left undecoded as the whitespace invalidates the word.
+func repairEncodedWord(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "=?") || !strings.HasSuffix(s, "?=") || !strings.ContainsAny(s, " \t") {
+		return "", false
+	}
+	word := strings.Join(strings.Fields(s), "")
+	if strings.Count(word, "?") != 4 {
+		return "", false
+	}
+	decoded, err := decoders.DecodeHeader(word)
+	if err != nil || decoded == word {
+		return "", false
+	}
+	return decoded, true
+}
+
 // idTrimCutset is the set of characters to trim around a message ID
 const idTrimCutset string = "<> \n"
 
+// lazyAddressHeaders are the address list headers which are parsed on
+// first access if parsing with WithLazyAddresses
+var lazyAddressHeaders = []string{
+	"From",
+	"Reply-To",
+	"To",
+	"Cc",
+	"Bcc",
+	"Resent-From",
+	"Resent-To",
+	"Resent-Cc",
+	"Resent-Bcc",
+}
+
 // parseAddresses parses a list of email addresses. Note that
 // net/mail.Header[param] gets a list of addresses rather than slice.
+// If the parser is lenient the addresses of a list which fails to parse
+// are salvaged individually, while the error is still returned.
 func (se *stagedEmail) parseAddresses(s string) ([]*mail.Address, error) {
+	addresses, err := se.parser.parseAddressList(s)
+	if err != nil && se.parser.lenient && !errors.Is(err, errorEmptyAddress) {
+		addresses = se.parser.salvageAddressList(s)
+	}
+	return addresses, err
+}
+
+// salvageAddressList parses each address of a list separately,
+// returning those which can be parsed.
+func (p *Parser) salvageAddressList(s string) []*mail.Address {
+	tokens, err := splitAddressList(s)
+	if err != nil {
+		return nil
+	}
+	var addresses []*mail.Address
+	for _, t := range tokens {
+		if list, err := p.parseAddressList(strings.TrimSpace(t.s)); err == nil {
+			addresses = append(addresses, list...)
+		}
+	}
+	return addresses
+}
+
+// parseAddressList decodes and parses a list of email addresses using
+// the parser's addressesFunc.
+func (p *Parser) parseAddressList(s string) ([]*mail.Address, error) {
 	if s == "" {
 		return nil, errorEmptyAddress
 	}
 	addresses := []*mail.Address{}
-	decodedHeader, err := decoders.DecodeHeader(s)
+	decodedHeader, err := p.decodeHeader(s)
 	if err != nil {
 		return addresses, fmt.Errorf("cannot decode address %q: %w", s, err)
 	}
 	// plug point for custom address parsing
-	return se.parser.addressesFunc(decodedHeader)
+	addresses, err = p.addressesFunc(decodedHeader)
+	if p.collapseHeaderWhitespace {
+		for _, a := range addresses {
+			if a != nil {
+				a.Name = collapseWhitespace(a.Name)
+			}
+		}
+	}
+	return addresses, err
 }
 
 // parseAddress parses a single *mail.Address from a string using
@@ -80,12 +240,43 @@ func (se *stagedEmail) parseAddress(s string) (*mail.Address, error) {
 	if s == "" {
 		return nil, errorEmptyAddress
 	}
-	decodedHeader, err := decoders.DecodeHeader(s)
+	decodedHeader, err := se.parser.decodeHeader(s)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode address %q: %w", s, err)
 	}
 	// plug point for custom address parsing
-	return se.parser.addressFunc(decodedHeader)
+	address, err := se.parser.addressFunc(decodedHeader)
+	if se.parser.collapseHeaderWhitespace && address != nil {
+		address.Name = collapseWhitespace(address.Name)
+	}
+	return address, err
+}
+
+// checkHeaderInjection flags headers with embedded line breaks which
+// are not part of legitimate folding, which may be an attempt to forge
+// additional headers. Such line breaks are either bare carriage returns
+// in the raw value, since folded lines are unfolded by net/mail, line
+// breaks in the value once MIME encoded-words are decoded, or bare line
+// feeds in a header block otherwise ending its lines with CRLF, which
+// net/mail reads as the start of a new header.
+func (se *stagedEmail) checkHeaderInjection() {
+	for _, key := range se.forgedHeaders {
+		se.email.HeaderInjectionSuspected = true
+		se.warn(fmt.Errorf("header %s follows a bare line feed in a CRLF header block", key))
+	}
+	for _, key := range slices.Sorted(maps.Keys(se.msg.Header)) {
+		for _, v := range se.msg.Header[key] {
+			injected := strings.ContainsAny(v, "\r\n")
+			if !injected && strings.Contains(v, "=?") {
+				decoded, _ := se.parser.decodeHeader(v)
+				injected = strings.ContainsAny(decoded, "\r\n")
+			}
+			if injected {
+				se.email.HeaderInjectionSuspected = true
+				se.warn(fmt.Errorf("header %s contains an embedded line break: %q", key, v))
+			}
+		}
+	}
 }
 
 // parseHeaders parses the headers in the net/mail.Header at se.msg into
@@ -125,13 +316,38 @@ func (se *stagedEmail) parseHeaders() error {
 		if s == "" {
 			return time.Time{}, errorEmptyDate
 		}
-		// plug point for custom address parsing
-		return se.parser.dateFunc(s)
+		// plug point for custom date parsing
+		t, err := se.parser.dateFunc(s)
+		if err == nil || se.parser.defaultTimezone == nil {
+			return t, err
+		}
+		// fallback to interpreting a zoneless date in the default
+		// location
+		t, lerr := parseDateInLocation(s, se.parser.defaultTimezone)
+		if lerr != nil {
+			return t, err
+		}
+		se.warn(fmt.Errorf("date %q has no timezone, using %s", s, se.parser.defaultTimezone))
+		se.email.DecodeStats.LenientRepairs++
+		return t, nil
 	}
 
-	// getDecodedString decodes and trims a string header
+	// getDecodedString decodes and trims a string header, repairing
+	// broken overlong encoded-words if lenient and collapsing whitespace
+	// if required
 	getDecodedString := func(s string) (string, error) {
-		return decoders.DecodeHeader(strings.TrimSpace(s))
+		decoded, err := se.parser.decodeHeader(strings.TrimSpace(s))
+		if err == nil && se.parser.lenient && decoded == strings.TrimSpace(s) {
+			if repaired, ok := repairEncodedWord(s); ok {
+				se.warn(fmt.Errorf("decoded overlong encoded-word broken by whitespace %q", s))
+				se.email.DecodeStats.LenientRepairs++
+				decoded = repaired
+			}
+		}
+		if se.parser.collapseHeaderWhitespace {
+			decoded = collapseWhitespace(decoded)
+		}
+		return decoded, err
 	}
 
 	// getCSV gets parts of a comma delimited string
@@ -147,115 +363,186 @@ func (se *stagedEmail) parseHeaders() error {
 		return o
 	}
 
+	se.checkHeaderInjection()
+
 	// alias headers for easy reference
 	h := &se.email.Headers
 
 	// set contentInfo from stagedEmail
 	h.ContentInfo = se.contentInfo
+	if se.contentInfo != nil {
+		h.ContentLanguage = slices.Clone(se.contentInfo.Languages)
+	}
+
+	// set the raw headers from stagedEmail
+	if len(se.rawHeaders) > 0 {
+		h.RawOrdered = se.rawHeaders
+	}
 
+	// register extra headers, calling the header callback (if any) for
+	// all headers
+	callback := se.parser.headerCallback
 	h.ExtraHeaders = map[string][]string{}
 	for key, value := range se.msg.Header {
-		if isExplicitHeader(key) {
+		explicit := isExplicitHeader(key)
+		if explicit && callback == nil {
 			continue
 		}
-		h.ExtraHeaders[key] = []string{}
+		if !explicit {
+			h.ExtraHeaders[key] = []string{}
+		}
 		for _, val := range value {
-			val, _ := decoders.DecodeHeader(val)
-			h.ExtraHeaders[key] = append(h.ExtraHeaders[key], val)
+			val, _ := se.parser.decodeHeader(val)
+			if callback != nil {
+				callback(key, val)
+			}
+			if !explicit {
+				h.ExtraHeaders[key] = append(h.ExtraHeaders[key], val)
+			}
 		}
 	}
 
+	// parseList parses address lists, unless they are to be parsed
+	// lazily on first access
+	parseList := se.parseAddresses
+	if se.parser.lazyAddresses {
+		raw := map[string]string{}
+		for _, field := range lazyAddressHeaders {
+			if se.parser.skipResentHeaders && strings.HasPrefix(field, "Resent-") {
+				continue
+			}
+			if v := get(field); v != "" {
+				raw[field] = v
+			}
+		}
+		h.LazyAddresses = email.NewLazyAddresses(raw, se.parser.parseAddressList)
+		parseList = func(string) ([]*mail.Address, error) { return nil, nil }
+	}
+
 	var err error
-	if h.Sender, err = se.parseAddress(get("Sender")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("cannot parse Sender header: %w", err)
+	h.Sender, err = se.parseAddress(get("Sender"))
+	if err := se.recoverHeader(err, "sender", get("Sender")); err != nil {
+		return err
+	}
+
+	if se.parser.addressComments {
+		if decoded, err := se.parser.decodeHeader(get("From")); err == nil {
+			h.FromComments = addressComments(decoded)
 		}
 	}
 
 	// Get email address lists via get. See get function comments.
-	if h.From, err = se.parseAddresses(get("From")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("from header: (%s) %w", get("From"), err)
-		}
+	h.From, err = parseList(get("From"))
+	if err := se.recoverHeader(err, "from", get("From")); err != nil {
+		return err
 	}
 
-	if h.ReplyTo, err = se.parseAddresses(get("Reply-To")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("reply-To header: (%s) %w", get("Reply-To"), err)
-		}
+	h.ReplyTo, err = parseList(get("Reply-To"))
+	if err := se.recoverHeader(err, "reply-To", get("Reply-To")); err != nil {
+		return err
 	}
 
-	if h.To, err = se.parseAddresses(get("To")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("to header: (%s) %w", get("To"), err)
-		}
+	h.To, err = parseList(get("To"))
+	if err := se.recoverHeader(err, "to", get("To")); err != nil {
+		return err
 	}
 
-	if h.Cc, err = se.parseAddresses(get("Cc")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("cc header: (%s) %w", get("Cc"), err)
-		}
+	h.Cc, err = parseList(get("Cc"))
+	if err := se.recoverHeader(err, "cc", get("Cc")); err != nil {
+		return err
 	}
 
-	if h.Bcc, err = se.parseAddresses(get("Bcc")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("bcc header: (%s) %w", get("Bcc"), err)
-		}
+	h.Bcc, err = parseList(get("Bcc"))
+	if err := se.recoverHeader(err, "bcc", get("Bcc")); err != nil {
+		return err
 	}
 
-	if h.ResentFrom, err = se.parseAddresses(get("Resent-From")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("resent-from header: (%s) %w", get("Resent-From"), err)
+	// the Resent block is skipped entirely if not required
+	if !se.parser.skipResentHeaders {
+		h.ResentFrom, err = parseList(get("Resent-From"))
+		if err := se.recoverHeader(err, "resent-from", get("Resent-From")); err != nil {
+			return err
 		}
-	}
 
-	if h.ResentSender, err = se.parseAddress(get("Resent-Sender")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("resent-sender header: (%s) %w", get("Resent-Sender"), err)
+		h.ResentSender, err = se.parseAddress(get("Resent-Sender"))
+		if err := se.recoverHeader(err, "resent-sender", get("Resent-Sender")); err != nil {
+			return err
 		}
-	}
 
-	if h.ResentTo, err = se.parseAddresses(get("Resent-To")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("resent-to header: (%s) %w", get("Resent-To"), err)
+		h.ResentTo, err = parseList(get("Resent-To"))
+		if err := se.recoverHeader(err, "resent-to", get("Resent-To")); err != nil {
+			return err
 		}
-	}
 
-	if h.ResentCc, err = se.parseAddresses(get("Resent-Cc")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("resent-cc header: (%s) %w", get("Resent-Cc"), err)
+		h.ResentCc, err = parseList(get("Resent-Cc"))
+		if err := se.recoverHeader(err, "resent-cc", get("Resent-Cc")); err != nil {
+			return err
 		}
-	}
 
-	if h.ResentBcc, err = se.parseAddresses(get("Resent-Bcc")); err != nil {
-		if !errors.Is(errorEmptyAddress, err) {
-			return fmt.Errorf("resent-bcc header: (%s) %w", get("Resent-Bcc"), err)
+		h.ResentBcc, err = parseList(get("Resent-Bcc"))
+		if err := se.recoverHeader(err, "resent-bcc", get("Resent-Bcc")); err != nil {
+			return err
 		}
-	}
 
-	if h.Date, err = callDateFunc(get("Date")); err != nil {
-		if !errors.Is(errorEmptyDate, err) {
-			return fmt.Errorf("date header: (%s) %w", get("Date"), err)
+		h.ResentDate, err = callDateFunc(get("Resent-Date"))
+		if err := se.recoverHeader(err, "resent-date", get("Resent-Date")); err != nil {
+			return err
 		}
-	}
 
-	if h.ResentDate, err = callDateFunc(get("Resent-Date")); err != nil {
-		if !errors.Is(errorEmptyDate, err) {
-			return fmt.Errorf("resent-date header: (%s) %w", get("Resent-Date"), err)
+		if id := getID(get("Resent-Message-ID")); id != "" {
+			h.ResentMessageID = id
 		}
 	}
 
+	h.Date, err = callDateFunc(get("Date"))
+	if err := se.recoverHeader(err, "date", get("Date")); err != nil {
+		return err
+	}
+
 	if h.Subject, err = getDecodedString(get("Subject")); err != nil {
-		return fmt.Errorf("subject header: (%s) %w", get("Subject"), err)
+		if err := se.recoverHeader(err, "subject", get("Subject")); err != nil {
+			return err
+		}
+		// retain the undecoded subject
+		h.Subject = strings.TrimSpace(get("Subject"))
 	}
 
-	if h.Comments, err = getDecodedString(get("Comments")); err != nil {
-		return fmt.Errorf("comments header: (%s) %w", get("Comments"), err)
+	h.RawSubject = se.rawSubject
+
+	// multiple Comments headers are joined by newlines
+	comments := []string{}
+	for _, c := range getAll("Comments") {
+		decoded, err := getDecodedString(c)
+		if err != nil {
+			if err := se.recoverHeader(err, "comments", c); err != nil {
+				return err
+			}
+			decoded = strings.TrimSpace(c)
+		}
+		if decoded != "" {
+			comments = append(comments, decoded)
+		}
 	}
+	h.Comments = strings.Join(comments, "\n")
 
-	// consider parsing this into []Received
 	if re := getAll("Received"); len(re) > 0 {
 		h.Received = re
+		h.ParsedReceived = make([]email.ReceivedHeader, len(re))
+		for i, r := range re {
+			h.ParsedReceived[i] = parseReceived(r)
+		}
+	}
+
+	for _, ds := range getAll("Dkim-Signature") {
+		h.DKIMSignatures = append(h.DKIMSignatures, parseDKIMSignature(ds))
+	}
+
+	for _, ar := range getAll("Authentication-Results") {
+		h.AuthResults = append(h.AuthResults, parseAuthResults(ar)...)
+	}
+
+	for _, aar := range getAll("Arc-Authentication-Results") {
+		h.ARCAuthResults = append(h.ARCAuthResults, parseARCAuthResults(aar)...)
 	}
 
 	if id := getID(get("Message-ID")); id != "" {
@@ -270,12 +557,30 @@ func (se *stagedEmail) parseHeaders() error {
 		h.References = ids
 	}
 
-	if kw := getCSV(get("Keywords")); len(kw) > 0 {
-		h.Keywords = kw
+	// the keywords of multiple Keywords headers are merged
+	for _, k := range getAll("Keywords") {
+		h.Keywords = append(h.Keywords, getCSV(k)...)
+	}
+
+	if id := getID(get("Original-Message-ID")); id != "" {
+		h.OriginalMessageID = id
+	} else if id := getID(get("X-Original-Message-ID")); id != "" {
+		h.OriginalMessageID = id
+	}
+
+	if kw := getKeyword(get("Auto-Submitted")); kw != "" {
+		h.AutoSubmitted = kw
+	}
+
+	se.parsePriority(h)
+	se.parseSpamStatus(h)
+
+	if uris := getURIs(get("Archived-At")); len(uris) > 0 {
+		h.ArchivedAt = uris[0]
 	}
 
-	if id := getID(get("Resent-Message-ID")); id != "" {
-		h.ResentMessageID = id
+	if uris := getURIs(get("List-Archive")); len(uris) > 0 {
+		h.ListArchive = uris
 	}
 
 	return nil
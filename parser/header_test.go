@@ -1,8 +1,11 @@
 package parser
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"net/mail"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -294,6 +297,28 @@ Received: from [10.1.1.1] (helo=[192.168.0.1])
 			"from securemail-y17.example.com ([196.35.198.77]) by anotherexample.net with esmtps (TLS1.2:ECDHE_RSA_AES_256_GCM_SHA384:256) (envelope-from <amazing@examaple.com>) id 1jdYH3-00057X-TF for user@anotherexample.net; Mon, 01 Apr 2019 12:01:38 +0000",
 			"from [10.1.1.1] (helo=[192.168.0.1]) by securemail-pl-omx12.eample.com with esmtpa (envelope-from <amazing@example.com>) id 1jdYGW-000aQH-Lx; Mon, 01 Apr 2019 14:01:05 +0200",
 		},
+		ParsedReceived: []email.ReceivedHeader{
+			{
+				Raw:       "from securemail-y17.example.com ([196.35.198.77]) by anotherexample.net with esmtps (TLS1.2:ECDHE_RSA_AES_256_GCM_SHA384:256) (envelope-from <amazing@examaple.com>) id 1jdYH3-00057X-TF for user@anotherexample.net; Mon, 01 Apr 2019 12:01:38 +0000",
+				From:      "securemail-y17.example.com",
+				By:        "anotherexample.net",
+				With:      "esmtps",
+				ID:        "1jdYH3-00057X-TF",
+				For:       "user@anotherexample.net",
+				Date:      toTime("2019-04-01 12:01:38 +0000 UTC"),
+				Timestamp: "Mon, 01 Apr 2019 12:01:38 +0000",
+			},
+			{
+				Raw:       "from [10.1.1.1] (helo=[192.168.0.1]) by securemail-pl-omx12.eample.com with esmtpa (envelope-from <amazing@example.com>) id 1jdYGW-000aQH-Lx; Mon, 01 Apr 2019 14:01:05 +0200",
+				From:      "[10.1.1.1]",
+				By:        "securemail-pl-omx12.eample.com",
+				With:      "esmtpa",
+				ID:        "1jdYGW-000aQH-Lx",
+				Date:      toTime("2019-04-01 14:01:05 +0200 CEST"),
+				Timestamp: "Mon, 01 Apr 2019 14:01:05 +0200",
+			},
+		},
+		Priority: email.PriorityNormal,
 		ExtraHeaders: map[string][]string{
 			"Delivery-Date": {"Tue, 26 May 2020 12:01:38 +0000"},
 		},
@@ -305,3 +330,278 @@ Received: from [10.1.1.1] (helo=[192.168.0.1])
 	}
 
 }
+
+func TestParseHeadersMaxHeaders(t *testing.T) {
+
+	// make an email with an absurd number of headers
+	var b strings.Builder
+	b.WriteString("From: someone@example.com\n")
+	for i := range 10000 {
+		fmt.Fprintf(&b, "X-Absurd-%d: value\n", i)
+	}
+	b.WriteString("Received: from a by b; Mon, 01 Apr 2019 12:01:38 +0000\n")
+	b.WriteString("Received: from c by d; Mon, 01 Apr 2019 12:01:39 +0000\n")
+	b.WriteString("\nBody.\n")
+	rawEmail := b.String()
+
+	tests := []struct {
+		maxHeaders int
+		isErr      bool
+	}{
+		{0, false},
+		{10003, false},
+		{10002, true},
+		{50, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			for _, opts := range [][]Opt{nil, {WithRawSubject()}} {
+				p := NewParser(append(opts, WithMaxHeaders(tt.maxHeaders))...)
+				r := strings.NewReader(rawEmail)
+				_, err := p.Parse(r)
+				if got, want := errors.Is(err, ErrTooManyHeaders), tt.isErr; got != want {
+					t.Errorf("got too many headers error %t want %t (%v)", got, want, err)
+				}
+				// the headers beyond the limit are not read
+				if tt.maxHeaders == 50 && r.Len() < len(rawEmail)/2 {
+					t.Errorf("read %d of %d bytes", len(rawEmail)-r.Len(), len(rawEmail))
+				}
+			}
+		})
+	}
+}
+
+func TestRepairEncodedWord(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"=?utf-8?B?SGVsbG8g d29ybGQ=?=", "Hello world", true},
+		{"=?utf-8?Q?Gr=C3=BC =C3=9Fe?=", "Grüße", true},
+		{"=?utf-8?B?SGVsbG8gd29ybGQ=?=", "", false},              // not broken
+		{"=?utf-8?B?SGVsbG8=?= =?utf-8?B?d29ybGQ=?=", "", false}, // two words
+		{"plain text", "", false},
+		{"=?utf-8?X?SGVsbG8g d29ybGQ=?=", "", false}, // unknown encoding
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			got, ok := repairEncodedWord(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("got ok %t want %t", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetURIs(t *testing.T) {
+	tests := []struct {
+		header string
+		uris   []string
+	}{
+		{"<http://www.example.com/mailarch/12345.html>", []string{"http://www.example.com/mailarch/12345.html"}},
+		{"<mailto:list-request@example.com?subject=index>, <https://lists.example.com/archive/>",
+			[]string{"mailto:list-request@example.com?subject=index", "https://lists.example.com/archive/"}},
+		{"<https://lists.example.com/\n archive/> (web archive)", []string{"https://lists.example.com/archive/"}},
+		{"https://example.com/bare", []string{"https://example.com/bare"}},
+		{"<not a uri>", []string{}},
+		{"", []string{}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if diff := cmp.Diff(tt.uris, getURIs(tt.header)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseHeadersArchive(t *testing.T) {
+	rawEmail := `From: list@example.com
+Subject: Archived
+Archived-At: <https://mail-archive.example.com/golang-nuts/msg01234.html>
+List-Archive: <https://groups.example.com/g/golang-nuts>,
+ <mailto:golang-nuts+archive@example.com>
+List-Id: <golang-nuts.example.com>
+
+Body.
+`
+	em, err := NewParser().Parse(strings.NewReader(rawEmail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Headers.ArchivedAt, "https://mail-archive.example.com/golang-nuts/msg01234.html"; got != want {
+		t.Errorf("archived at got %s want %s", got, want)
+	}
+	want := []string{"https://groups.example.com/g/golang-nuts", "mailto:golang-nuts+archive@example.com"}
+	if diff := cmp.Diff(want, em.Headers.ListArchive); diff != "" {
+		t.Error(diff)
+	}
+	if _, ok := em.Headers.ExtraHeaders["Archived-At"]; ok {
+		t.Error("Archived-At should not be an extra header")
+	}
+	if _, ok := em.Headers.ExtraHeaders["List-Id"]; !ok {
+		t.Error("List-Id should be an extra header")
+	}
+}
+
+func TestParseHeadersRepeatedKeywords(t *testing.T) {
+	f, err := os.Open("testdata/repeated_keywords.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"draft", "chapter 7", "review", `"due Friday"`}
+	if diff := cmp.Diff(want, em.Headers.Keywords); diff != "" {
+		t.Error(diff)
+	}
+	if got, want := em.Headers.Comments, "Second revision\nBitte prüfen"; got != want {
+		t.Errorf("comments got %q want %q", got, want)
+	}
+}
+
+func TestParseHeadersOriginalMessageID(t *testing.T) {
+
+	tests := []struct {
+		headers string
+		want    string
+	}{
+		{"Original-Message-ID: <original@example.com>\n", "original@example.com"},
+		{"X-Original-Message-ID: < x-original@example.com >\n", "x-original@example.com"},
+		{ // Original-Message-ID takes precedence
+			"X-Original-Message-ID: <x-original@example.com>\nOriginal-Message-ID: <original@example.com>\n",
+			"original@example.com",
+		},
+		{"", ""},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			rawEmail := "From: resender@example.com\nMessage-ID: <resent@example.com>\n" + tt.headers + "\nBody.\n"
+			em, err := NewParser().Parse(strings.NewReader(rawEmail))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Headers.OriginalMessageID, tt.want; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+			if got, want := len(em.Headers.ExtraHeaders), 0; got != want {
+				t.Errorf("got %d extra headers want %d", got, want)
+			}
+		})
+	}
+}
+
+func benchmarkParseHeaders(b *testing.B, opts ...Opt) {
+	recipients := []string{}
+	for i := range 50 {
+		recipients = append(recipients, fmt.Sprintf(`"Recipient %d" <recipient%d@example.com>`, i, i))
+	}
+	msg := []byte("From: Alice <alice@example.com>\nTo: " + strings.Join(recipients, ",\n ") +
+		"\nCc: " + strings.Join(recipients, ",\n ") + "\nSubject: Benchmark\n\nBody.\n")
+	p := NewParser(append(opts, WithHeadersOnly())...)
+	for b.Loop() {
+		if _, err := p.Parse(bytes.NewReader(msg)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseHeadersEagerAddresses(b *testing.B) {
+	benchmarkParseHeaders(b)
+}
+
+func BenchmarkParseHeadersLazyAddresses(b *testing.B) {
+	benchmarkParseHeaders(b, WithLazyAddresses())
+}
+
+func benchmarkParseResentHeaders(b *testing.B, opts ...Opt) {
+	recipients := []string{}
+	for i := range 50 {
+		recipients = append(recipients, fmt.Sprintf(`"Recipient %d" <recipient%d@example.com>`, i, i))
+	}
+	msg := []byte("From: Alice <alice@example.com>\nTo: Bob <bob@example.com>\n" +
+		"Resent-From: Bob <bob@example.com>\nResent-To: " + strings.Join(recipients, ",\n ") +
+		"\nResent-Cc: " + strings.Join(recipients, ",\n ") +
+		"\nResent-Date: Mon, 27 Apr 2026 11:00:00 +0000\nSubject: Benchmark\n\nBody.\n")
+	p := NewParser(append(opts, WithHeadersOnly())...)
+	for b.Loop() {
+		if _, err := p.Parse(bytes.NewReader(msg)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseHeadersResent(b *testing.B) {
+	benchmarkParseResentHeaders(b)
+}
+
+func BenchmarkParseHeadersSkipResent(b *testing.B) {
+	benchmarkParseResentHeaders(b, WithSkipResentHeaders())
+}
+
+func TestParseHeadersInjection(t *testing.T) {
+
+	tests := []struct {
+		file     string
+		suspect  bool
+		warnings int
+	}{
+		{"testdata/header_injection.eml", true, 2},
+		{"testdata/header_injection_lf.eml", true, 1},
+		{"testdata/vacation.eml", false, 0},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			em, err := NewParser().Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.HeaderInjectionSuspected, tt.suspect; got != want {
+				t.Errorf("got %t want %t", got, want)
+			}
+			if got, want := len(em.Warnings), tt.warnings; got != want {
+				t.Errorf("got %d warnings want %d: %v", got, want, em.Warnings)
+			}
+		})
+	}
+}
+
+func TestParseContentLanguage(t *testing.T) {
+	tests := []struct {
+		header    string
+		languages []string
+	}{
+		{"", nil},
+		{"Content-Language: TH, en\n", []string{"th", "en"}},
+		{"Content-Language: de-CH\n", []string{"de-ch"}},
+		{"Content-Language: ???\n", nil},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			msg := "From: someone@example.com\nSubject: language\n" + tt.header + "\nBody.\n"
+			em, err := NewParser().Parse(strings.NewReader(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.languages, em.Headers.ContentLanguage); diff != "" {
+				t.Error(diff)
+			}
+			if _, ok := em.Headers.ExtraHeaders["Content-Language"]; ok {
+				t.Error("Content-Language should not be an extra header")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+// headerLimitReader is an io.Reader which fails with ErrTooManyHeaders
+// once the header section of a message has more than max header
+// fields, counting each field once however it is folded, so that an
+// excessive header block is rejected as it is read rather than after
+// it has been buffered. The header section ends at the first empty
+// line, after which reads pass through unchanged. The error is
+// returned by all subsequent reads.
+type headerLimitReader struct {
+	r       io.Reader
+	max     int
+	count   int  // number of header fields started
+	empty   bool // the current physical line is empty
+	lineLen int  // bytes read of the current physical line
+	inBody  bool // the header section has ended
+	err     error
+}
+
+// newHeaderLimitReader returns a headerLimitReader reading from r.
+func newHeaderLimitReader(r io.Reader, max int) *headerLimitReader {
+	return &headerLimitReader{r: r, max: max, empty: true}
+}
+
+func (h *headerLimitReader) Read(p []byte) (int, error) {
+	if h.err != nil {
+		return 0, h.err
+	}
+	n, err := h.r.Read(p)
+	if h.inBody {
+		return n, err
+	}
+	for i, c := range p[:n] {
+		switch c {
+		case '\n':
+			if h.empty {
+				h.inBody = true
+				return n, err
+			}
+			h.empty, h.lineLen = true, 0
+			continue
+		case '\r':
+			continue
+		}
+		// a line not starting with whitespace starts a new field
+		if h.lineLen == 0 && c != ' ' && c != '\t' {
+			h.count++
+			if h.count > h.max {
+				h.err = fmt.Errorf("%w: more than %d headers", ErrTooManyHeaders, h.max)
+				return i, h.err
+			}
+		}
+		h.empty = false
+		h.lineLen++
+	}
+	return n, err
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// "leaves" provides a flat view of the leaf parts of an email,
+// independent of the structure of its nested multiparts.
+
+// ParseLeaves parses the leaf (non-multipart) parts of an email into a
+// flat slice of email.Leaf in document order, each with the path of
+// part indexes through the nested multiparts leading to it and its
+// decoded content read into Leaf.Data. Unlike Parse, no distinction is
+// made between body text and files, and the parts of alternative and
+// related multiparts are all returned. A message which is not multipart
+// has a single leaf with an empty path.
+func (p *Parser) ParseLeaves(r io.Reader) ([]email.Leaf, error) {
+	leaves := []email.Leaf{}
+	err := p.ParseLeavesFunc(context.Background(), r, func(l email.Leaf) error {
+		var err error
+		l.Data, err = io.ReadAll(l.Reader)
+		if err != nil {
+			return fmt.Errorf("cannot read part %v: %w", l.Path, err)
+		}
+		l.Reader = nil
+		leaves = append(leaves, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// ParseLeavesFunc parses the leaf parts of an email as ParseLeaves does,
+// calling fn with each leaf in document order as it is read. The
+// decoded content of each leaf is provided by Leaf.Reader rather than
+// being buffered, and is skipped if not read. Parsing stops with the
+// error returned by fn, if any, unless it is ErrStopStream in which
+// case ParseLeavesFunc returns nil. Parsing is also aborted with the
+// context's error if the context is cancelled, which is checked
+// between parts and by each read from r.
+//
+// The parser's maximum message size, line length, part depth and part
+// size are respected.
+func (p *Parser) ParseLeavesFunc(ctx context.Context, r io.Reader, fn func(email.Leaf) error) error {
+	msg, err := mail.ReadMessage(p.limitReader(&contextReader{ctx: ctx, r: r}))
+	if err != nil {
+		return fmt.Errorf("cannot read message: %w", err)
+	}
+	ci, err := email.ExtractContentInfo(msg.Header, nil)
+	if err != nil {
+		return fmt.Errorf("cannot extract content: %w", err)
+	}
+	se := newStagedEmail(p)
+	se.ctx = ctx
+	se.contentInfo = ci
+	err = se.parseLeaves(msg.Body, ci, []int{}, fn)
+	if errors.Is(err, ErrStopStream) {
+		return nil
+	}
+	return err
+}
+
+// parseLeaves calls fn with the part with content r at path,
+// recursively parsing the sub-parts of multipart parts.
+func (se *stagedEmail) parseLeaves(r io.Reader, ci *email.ContentInfo, path []int, fn func(email.Leaf) error) error {
+	if !strings.HasPrefix(ci.Type, "multipart/") {
+		return fn(email.Leaf{Path: path, ContentInfo: ci, Reader: se.decodeContent(r, ci)})
+	}
+
+	if err := se.checkPartDepth(len(path) + 1); err != nil {
+		return err
+	}
+	r, boundary := se.sniffBoundary(r, ci, ci.TypeParams["boundary"])
+	multipartReader := multipart.NewReader(r, boundary)
+	for i := 0; ; i++ {
+		// check for cancellation between parts
+		if err := se.ctx.Err(); err != nil {
+			return err
+		}
+		// use raw parts to avoid decoding quoted-printable twice
+		part, err := multipartReader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read part: %w", err)
+		}
+		partCI, err := email.ExtractContentInfo(part.Header, ci)
+		if err != nil {
+			return fmt.Errorf("content extraction error: %w", err)
+		}
+		err = se.parseLeaves(part, partCI, append(slices.Clone(path), i), fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestParseLeaves(t *testing.T) {
+
+	c, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaves, err := NewParser().ParseLeaves(bytes.NewReader(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, l := range leaves {
+		got = append(got, fmt.Sprintf("%v %s", l.Path, l.ContentInfo.Type))
+	}
+	want := []string{
+		"[0 0] text/plain",
+		"[0 1] text/html",
+		"[1] image/png",
+		"[2] image/jpeg",
+		"[3] image/jpeg",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+
+	// leaf content matches that of a full parse
+	em, err := NewParser().Parse(bytes.NewReader(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.ReplaceAll(string(leaves[0].Data), "\r\n", "\n")
+	if got, want := strings.TrimSpace(text), em.Text; got != want {
+		t.Errorf("text got %q want %q", got, want)
+	}
+	for i, f := range em.Files {
+		if !bytes.Equal(leaves[i+2].Data, f.Data) {
+			t.Errorf("leaf %v data differs from file %s", leaves[i+2].Path, f.Name)
+		}
+	}
+}
+
+func TestParseLeavesSinglePart(t *testing.T) {
+
+	msg := "Subject: plain\nContent-Transfer-Encoding: quoted-printable\n\nCaf=C3=A9\n"
+	leaves, err := NewParser().ParseLeaves(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(leaves), 1; got != want {
+		t.Fatalf("got %d want %d leaves", got, want)
+	}
+	if got, want := len(leaves[0].Path), 0; got != want {
+		t.Errorf("got path length %d want %d", got, want)
+	}
+	if got, want := string(leaves[0].Data), "Café\n"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestParseLeavesFunc(t *testing.T) {
+
+	c, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// leaves are streamed, and need not be read
+	var paths []string
+	var text []byte
+	err = NewParser().ParseLeavesFunc(context.Background(), bytes.NewReader(c), func(l email.Leaf) error {
+		paths = append(paths, fmt.Sprint(l.Path))
+		if l.ContentInfo.Type == "text/plain" {
+			var err error
+			text, err = io.ReadAll(l.Reader)
+			return err
+		}
+		if l.ContentInfo.Type == "image/png" {
+			return ErrStopStream
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"[0 0]", "[0 1]", "[1]"}, paths); diff != "" {
+		t.Error(diff)
+	}
+	if !bytes.Contains(text, []byte("cats")) {
+		t.Errorf("unexpected text %q", text)
+	}
+
+	// the parser's limits apply
+	_, err = NewParser(WithMaxMessageSize(1024)).ParseLeaves(bytes.NewReader(c))
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("got error %v want %v", err, ErrMessageTooLarge)
+	}
+	long := "Subject: long\nContent-Type: multipart/mixed; boundary=b\n\n" +
+		"--b\n\n" + strings.Repeat("x", 100) + "\n--b--\n"
+	_, err = NewParser(WithMaxLineLength(80)).ParseLeaves(strings.NewReader(long))
+	if !errors.Is(err, ErrLineTooLong) {
+		t.Errorf("got error %v want %v", err, ErrLineTooLong)
+	}
+
+	// parsing stops when the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	err = NewParser().ParseLeavesFunc(ctx, bytes.NewReader(c), func(l email.Leaf) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrLineTooLong is returned when a line of an email exceeds the length
+// set by WithMaxLineLength.
+var ErrLineTooLong = errors.New("line too long")
+
+// lineLimitReader is an io.Reader which fails with ErrLineTooLong once
+// a line exceeds max bytes, excluding line endings. In the headers a
+// folded header is measured as a single line, while in the body each
+// line is measured separately. The header section ends at the first
+// empty line. The error is returned by all subsequent reads, since
+// bufio.Reader.ReadLine discards an error following a partial line.
+type lineLimitReader struct {
+	r       io.Reader
+	max     int
+	length  int  // length of the current (unfolded header) line
+	empty   bool // the current physical line is empty
+	newline bool // the last byte was a line feed
+	inBody  bool // the header section has ended
+	err     error
+}
+
+// newLineLimitReader returns a lineLimitReader reading from r.
+func newLineLimitReader(r io.Reader, max int) *lineLimitReader {
+	return &lineLimitReader{r: r, max: max, empty: true}
+}
+
+func (l *lineLimitReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	n, err := l.r.Read(p)
+	for i, c := range p[:n] {
+		if l.newline {
+			l.newline = false
+			// continue measuring a folded header line
+			if l.inBody || (c != ' ' && c != '\t') {
+				l.length = 0
+			}
+		}
+		switch c {
+		case '\n':
+			if l.empty && !l.inBody {
+				l.inBody = true
+			}
+			l.newline, l.empty = true, true
+			continue
+		case '\r':
+			continue
+		}
+		l.empty = false
+		l.length++
+		if l.length > l.max {
+			l.err = fmt.Errorf("%w: line exceeds %d bytes", ErrLineTooLong, l.max)
+			return i, l.err
+		}
+	}
+	return n, err
+}
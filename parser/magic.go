@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// sniffLen is the number of bytes considered by http.DetectContentType
+const sniffLen = 512
+
+// sniffableTypes are the media types which http.DetectContentType
+// recognises from their magic bytes. A file declared as one of these
+// types whose content is not recognised has a mismatched type.
+var sniffableTypes = []string{
+	"application/ogg",
+	"application/pdf",
+	"application/postscript",
+	"application/vnd.rar",
+	"application/x-gzip",
+	"application/x-rar-compressed",
+	"application/zip",
+	"audio/mpeg",
+	"audio/wave",
+	"image/bmp",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"image/x-icon",
+	"video/mp4",
+	"video/webm",
+}
+
+// typeAliases map common alternative media types to those returned by
+// http.DetectContentType
+var typeAliases = map[string]string{
+	"application/gzip":             "application/x-gzip",
+	"application/vnd.rar":          "application/x-rar-compressed",
+	"application/x-zip-compressed": "application/zip",
+	"audio/mp3":                    "audio/mpeg",
+	"audio/wav":                    "audio/wave",
+	"audio/x-wav":                  "audio/wave",
+	"image/jpg":                    "image/jpeg",
+	"image/pjpeg":                  "image/jpeg",
+	"image/vnd.microsoft.icon":     "image/x-icon",
+}
+
+// isZipContainer reports if a media type is a format stored in a zip
+// container, such as an Office Open XML or OpenDocument file.
+func isZipContainer(t string) bool {
+	switch {
+	case strings.HasPrefix(t, "application/vnd.openxmlformats-officedocument."),
+		strings.HasPrefix(t, "application/vnd.oasis.opendocument."),
+		t == "application/epub+zip", t == "application/java-archive":
+		return true
+	}
+	return false
+}
+
+// typeMatches reports if a sniffed media type is consistent with an
+// expected media type, both without parameters. Formats stored in zip
+// containers match a sniffed zip file and any textual type matches
+// sniffed plain text. Content which could not be identified only
+// mismatches a type which would have been identified.
+func typeMatches(expected, sniffed string) bool {
+	if alias, ok := typeAliases[expected]; ok {
+		expected = alias
+	}
+	switch {
+	case expected == "" || expected == sniffed:
+		return true
+	case sniffed == "application/zip" && isZipContainer(expected):
+		return true
+	case sniffed == "text/plain" && !slices.Contains(sniffableTypes, expected):
+		return true
+	case sniffed == "application/octet-stream":
+		return !slices.Contains(sniffableTypes, expected)
+	}
+	return false
+}
+
+// detectMagicBytes sniffs the media type of a file from the first
+// decoded bytes of its reader, without consuming them, recording it in
+// file.SniffedType. Empty and unreadable files are not sniffed.
+// file.TypeMismatch is set if the sniffed type disagrees with either
+// the declared type, or inferred type for a file declared as
+// application/octet-stream, or the type implied by its file name
+// extension.
+func detectMagicBytes(file *email.File) {
+	br := bufio.NewReaderSize(file.Reader, sniffLen)
+	file.Reader = br
+	head, err := br.Peek(sniffLen)
+	if len(head) == 0 || (err != nil && err != io.EOF) {
+		return
+	}
+	file.SniffedType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+
+	declared := file.ContentInfo.Type
+	if declared == "application/octet-stream" {
+		declared = file.InferredType
+	}
+	file.TypeMismatch = !typeMatches(declared, file.SniffedType) ||
+		!typeMatches(inferTypeByExtension(file.Name), file.SniffedType)
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTypeMatches(t *testing.T) {
+	tests := []struct {
+		expected string
+		sniffed  string
+		want     bool
+	}{
+		{"application/pdf", "application/pdf", true},
+		{"", "application/pdf", true},
+		{"image/jpg", "image/jpeg", true},
+		{"application/gzip", "application/x-gzip", true},
+		{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/zip", true},
+		{"text/csv", "text/plain", true},
+		{"application/json", "text/plain", true},
+		{"application/x-msdownload", "application/octet-stream", true},
+		{"application/pdf", "application/octet-stream", false},
+		{"image/png", "text/plain", false},
+		{"text/plain", "application/pdf", false},
+		{"image/png", "image/jpeg", false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := typeMatches(tt.expected, tt.sniffed), tt.want; got != want {
+				t.Errorf("got %t want %t", got, want)
+			}
+		})
+	}
+}
+
+func TestOptDetectMagicBytes(t *testing.T) {
+
+	type result struct {
+		Name     string
+		Sniffed  string
+		Mismatch bool
+		Size     int
+	}
+
+	tests := []struct {
+		opts []Opt
+		want []result
+	}{
+		{
+			opts: nil,
+			want: []result{
+				{Name: "invoice.pdf", Size: 107},
+				{Name: "photo.png", Size: 70},
+				{Name: "report.docx", Size: 49},
+				{Name: "statement.pdf", Size: 107},
+			},
+		},
+		{
+			opts: []Opt{WithDetectMagicBytes()},
+			want: []result{
+				{Name: "invoice.pdf", Sniffed: "application/octet-stream", Mismatch: true, Size: 107},
+				{Name: "photo.png", Sniffed: "image/png", Size: 70},
+				{Name: "report.docx", Sniffed: "application/zip", Size: 49},
+				{Name: "statement.pdf", Sniffed: "application/octet-stream", Mismatch: true, Size: 107},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/masquerade.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			em, err := NewParser(tt.opts...).Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []result{}
+			for _, file := range em.Files {
+				got = append(got, result{file.Name, file.SniffedType, file.TypeMismatch, len(file.Data)})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/rorycl/letters/email"
+)
+
+// ErrNotMbox is returned by ParseMbox if the stream does not start with
+// an mbox "From " separator line.
+var ErrNotMbox = errors.New("not an mbox stream")
+
+// mboxSeparator is the start of the line separating mbox messages
+var mboxSeparator = []byte("From ")
+
+// isMboxSeparator reports if the line separates mbox messages.
+func isMboxSeparator(line []byte) bool {
+	return bytes.HasPrefix(line, mboxSeparator)
+}
+
+// isMboxEscaped reports if the line is a quoted "From " line, such as
+// ">From " or ">>From ", in the body of an mbox message.
+func isMboxEscaped(line []byte) bool {
+	unquoted := bytes.TrimLeft(line, ">")
+	return len(unquoted) < len(line) && bytes.HasPrefix(unquoted, mboxSeparator)
+}
+
+// mboxMessageReader is an io.Reader of a single message of an mbox
+// stream, reading lines until the next "From " separator line, which
+// is consumed, or the end of the stream. One level of ">" quoting is
+// removed from quoted "From " lines, following the mboxrd format.
+// Lines longer than the buffer of br are read in pieces rather than
+// being held in memory, so that the parser's line length and message
+// size limits apply to them.
+type mboxMessageReader struct {
+	br      *bufio.Reader
+	line    []byte // unread remainder of the current piece of line
+	midLine bool   // the current line has only been read in part
+	done    bool   // the end of the message has been reached
+	next    bool   // a separator line was read, so a message follows
+	err     error  // a read error other than io.EOF
+}
+
+func (m *mboxMessageReader) Read(p []byte) (int, error) {
+	for len(m.line) == 0 {
+		if m.done {
+			if m.err != nil {
+				return 0, m.err
+			}
+			return 0, io.EOF
+		}
+		line, err := m.br.ReadSlice('\n')
+		lineStart := !m.midLine
+		m.midLine = errors.Is(err, bufio.ErrBufferFull)
+		if err != nil && !m.midLine {
+			m.done = true
+			if !errors.Is(err, io.EOF) {
+				m.err = err
+			}
+		}
+		// only the start of a line may separate or quote
+		switch {
+		case !lineStart:
+		case isMboxSeparator(line):
+			m.done, m.next = true, true
+			line = nil
+			// skip the remainder of an overlong separator line
+			for m.midLine {
+				_, err = m.br.ReadSlice('\n')
+				m.midLine = errors.Is(err, bufio.ErrBufferFull)
+			}
+			if err != nil && !errors.Is(err, io.EOF) {
+				m.err = err
+			}
+		case isMboxEscaped(line):
+			line = line[1:]
+		}
+		m.line = line
+	}
+	// the line is consumed before the next ReadSlice overwrites it
+	n := copy(p, m.line)
+	m.line = m.line[n:]
+	return n, nil
+}
+
+// ParseMbox parses the messages of a Unix mbox archive from r, in
+// which each message is preceded by a "From " separator line,
+// returning an iterator of the parsed emails. Messages are read from
+// the stream and parsed one at a time with the parser's options, and
+// quoted ">From " lines are unquoted. A message which fails to parse
+// yields its error, identifying the message by its position in the
+// archive starting at 1, and iteration continues with the next
+// message. Iteration ends after yielding a read error of r, or
+// ErrNotMbox if r does not start with a separator line.
+func (p *Parser) ParseMbox(r io.Reader) iter.Seq2[*email.Email, error] {
+	return func(yield func(*email.Email, error) bool) {
+		br := bufio.NewReader(r)
+
+		// find the first separator, skipping any leading blank lines
+		for {
+			line, err := br.ReadSlice('\n')
+			if isMboxSeparator(line) {
+				// skip the remainder of an overlong separator line
+				for errors.Is(err, bufio.ErrBufferFull) {
+					_, err = br.ReadSlice('\n')
+				}
+				if err != nil && !errors.Is(err, io.EOF) {
+					yield(nil, fmt.Errorf("cannot read mbox: %w", err))
+					return
+				}
+				break
+			}
+			if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+				yield(nil, fmt.Errorf("cannot read mbox: %w", err))
+				return
+			}
+			if len(bytes.TrimSpace(line)) > 0 {
+				yield(nil, ErrNotMbox)
+				return
+			}
+			if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+				return // empty stream
+			}
+		}
+
+		for n := 1; ; n++ {
+			mr := &mboxMessageReader{br: br}
+			e, err := p.Parse(mr)
+			// read any remainder of the message not consumed by parsing
+			_, _ = io.Copy(io.Discard, mr)
+			if mr.err != nil {
+				yield(nil, fmt.Errorf("cannot read mbox message %d: %w", n, mr.err))
+				return
+			}
+			if err != nil {
+				err = fmt.Errorf("mbox message %d: %w", n, err)
+			}
+			if !yield(e, err) || !mr.next {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseMbox(t *testing.T) {
+
+	f, err := os.Open("testdata/archive.mbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ids, errs := []string{}, []error{}
+	texts := []string{}
+	for e, err := range NewParser().ParseMbox(f) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, e.Headers.MessageID)
+		texts = append(texts, e.Text)
+	}
+	if got, want := strings.Join(ids, " "), "lunch-1@example.com reply-1@example.net"; got != want {
+		t.Errorf("got ids %q want %q", got, want)
+	}
+	if got, want := len(errs), 1; got != want {
+		t.Fatalf("got %d errors want %d", got, want)
+	}
+	if got, want := errs[0].Error(), "mbox message 2: "; !strings.HasPrefix(got, want) {
+		t.Errorf("got error %q want prefix %q", got, want)
+	}
+	want := "Lunch on Friday?\nFrom the new place on the corner.\n>From a quoted line."
+	if got := texts[0]; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+}
+
+func TestParseMboxEarlyStop(t *testing.T) {
+
+	f, err := os.Open("testdata/archive.mbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	count := 0
+	for range NewParser().ParseMbox(f) {
+		count++
+		break
+	}
+	if got, want := count, 1; got != want {
+		t.Errorf("got %d messages want %d", got, want)
+	}
+}
+
+func TestParseMboxStreams(t *testing.T) {
+
+	tests := []struct {
+		stream   string
+		messages int
+		err      error
+	}{
+		{"", 0, nil},
+		{"\n\n", 0, nil},
+		{"Subject: not mbox\n\nBody.\n", 0, ErrNotMbox},
+		{"From a@example.com\nSubject: one\n\nBody.\nFrom b@example.com\nSubject: two\n\nBody.", 2, nil},
+		{"\nFrom a@example.com\r\nSubject: crlf\r\n\r\nBody.\r\n", 1, nil},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			messages := 0
+			var lastErr error
+			for e, err := range NewParser().ParseMbox(strings.NewReader(tt.stream)) {
+				if err != nil {
+					lastErr = err
+					continue
+				}
+				if e.Headers.Subject == "" {
+					t.Error("expected a subject")
+				}
+				messages++
+			}
+			if got, want := messages, tt.messages; got != want {
+				t.Errorf("got %d messages want %d", got, want)
+			}
+			if !errors.Is(lastErr, tt.err) {
+				t.Errorf("got error %v want %v", lastErr, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseMboxLongLines(t *testing.T) {
+
+	// a line longer than the read buffer, in which a "From " beyond
+	// the start of the line neither separates nor is unquoted
+	long := strings.Repeat("x", 10000) + ">From the middle of a line"
+	stream := "From a@example.com\nSubject: long\n\n" + long + "\n" +
+		"From b@example.com\nSubject: short\n\nBody.\n"
+
+	texts := []string{}
+	for e, err := range NewParser().ParseMbox(strings.NewReader(stream)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		texts = append(texts, e.Text)
+	}
+	if got, want := len(texts), 2; got != want {
+		t.Fatalf("got %d messages want %d", got, want)
+	}
+	if got, want := texts[0], long; got != want {
+		t.Errorf("got text of %d bytes want %d", len(got), len(want))
+	}
+
+	// an overlong line fails its message under the line length limit,
+	// without preventing the next message from being parsed
+	var errs []error
+	messages := 0
+	for _, err := range NewParser(WithMaxLineLength(1000)).ParseMbox(strings.NewReader(stream)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		messages++
+	}
+	if got, want := messages, 1; got != want {
+		t.Errorf("got %d messages want %d", got, want)
+	}
+	if got, want := len(errs), 1; got != want {
+		t.Fatalf("got %d errors want %d", got, want)
+	}
+	if !errors.Is(errs[0], ErrLineTooLong) {
+		t.Errorf("got error %v want %v", errs[0], ErrLineTooLong)
+	}
+}
@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"io"
 	"net/mail"
-	"os"
-	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/rorycl/letters/email"
+	"golang.org/x/text/encoding"
 )
 
 // WithVerbose is presently a no-op option for testing if options are
@@ -27,24 +28,477 @@ func WithHeadersOnly() Opt {
 	}
 }
 
+// WithTimeout sets the maximum duration of a parse. It builds on
+// Parser.ParseContext, deriving a context with a deadline from the
+// provided (or background) context, and aborts parsing, including long
+// reads of attachments, returning ErrTimeout if the deadline is
+// exceeded. It provides a simple safeguard against pathological
+// messages for those who don't otherwise use contexts.
+func WithTimeout(d time.Duration) Opt {
+	return func(p *Parser) {
+		p.timeout = d
+	}
+}
+
 // WithSkipContentTypes allows the user to provide a slice of content
-// types whose email parts will be skipped in processing.
+// types whose email parts will be skipped in processing. Content types
+// are matched case-insensitively on their base media type, ignoring any
+// parameters, and an entry may be a wildcard such as "image/*" matching
+// all subtypes of a type.
 func WithSkipContentTypes(skipContentTypes []string) Opt {
 	return func(p *Parser) {
 		p.skipContentTypes = skipContentTypes
 	}
 }
 
+// WithSkipUnknownContentTypes records parts of a content type the
+// parser cannot handle in email.Email.UnhandledParts and continues
+// parsing, rather than failing with an UnknownContentTypeError.
+func WithSkipUnknownContentTypes() Opt {
+	return func(p *Parser) {
+		p.skipUnknownContentTypes = true
+	}
+}
+
+// WithPartInspector sets a func called with the content info of each
+// part of a multipart email, including text parts, files and nested
+// multiparts, before the part is read or decoded. If the func reports
+// skip the part is skipped without being consumed, while an error
+// aborts the parse. This generalizes WithSkipContentTypes, allowing
+// parts to be skipped on arbitrary criteria such as their filename or
+// language, or the parse to be abandoned early by returning an error.
+func WithPartInspector(fn func(*email.ContentInfo) (skip bool, err error)) Opt {
+	return func(p *Parser) {
+		p.partInspector = fn
+	}
+}
+
+// WithLenient records recoverable parsing problems in
+// email.Email.Errors, and as warnings in email.Email.Warnings, rather
+// than aborting the parse. This isolates the failure to decode a part,
+// such as one with a corrupt base64 body, to that part so that the
+// remaining parts of a partially corrupt message are still parsed.
+// Files that fail to decode have email.File.DecodeError set, while text
+// parts that fail to decode are skipped. A Subject or Comments header
+// consisting of a single overlong encoded-word broken by folding
+// whitespace is also decoded.
+//
+// Headers which cannot be parsed are similarly recorded as errors and
+// warnings, while the remaining headers are populated. The parseable
+// addresses of an invalid address list are retained, an invalid date
+// is left as the zero time and a Subject or Comments header with an
+// invalid encoded-word is retained undecoded.
+//
+// Parts of an unknown content type are skipped, and content in an
+// unknown charset decoded with the fallback charset, with each recorded
+// as an error and warning.
+func WithLenient() Opt {
+	return func(p *Parser) {
+		p.lenient = true
+	}
+}
+
+// WithStrict aborts the parse at the first parsing problem, returning
+// its error. This is the default, and may be used to override an
+// earlier WithLenient option, such as in a shared list of options.
+func WithStrict() Opt {
+	return func(p *Parser) {
+		p.lenient = false
+	}
+}
+
+// WithSniffTransferEncoding turns on heuristic sniffing of content
+// declared with a "binary" Content-Transfer-Encoding. If the start of
+// such content consists only of lines of the base64 alphabet, it is
+// decoded as base64. Each such repair is recorded as a warning in
+// email.Email.Warnings. See WithSniffIdentityEncodings to also sniff
+// content declared with other or no encodings.
+func WithSniffTransferEncoding() Opt {
+	return func(p *Parser) {
+		p.sniffTransferEncoding = true
+	}
+}
+
+// WithSniffIdentityEncodings extends WithSniffTransferEncoding to
+// sniff content declared with no Content-Transfer-Encoding or with any
+// of the "7bit", "8bit" or "binary" identity encodings, and for
+// quoted-printable as well as base64 encoding. Content which looks
+// quoted-printable encoded, consisting of short lines of printable ASCII
+// with "=XX" escapes or soft line breaks, is decoded accordingly. Each
+// such repair is recorded as a warning in email.Email.Warnings. As this
+// option covers content declared as "binary", it need not be combined
+// with WithSniffTransferEncoding.
+//
+// Sniffing can misfire on plain text that happens to look encoded, so
+// is best reserved for mail from known broken senders.
+func WithSniffIdentityEncodings() Opt {
+	return func(p *Parser) {
+		p.sniffIdentityEncodings = true
+	}
+}
+
+// WithEncodingResolver sets a func to resolve the character encoding of
+// each part's content, consulted before the built-in lookup of the
+// declared charset. If the func returns nil the built-in lookup is
+// used. This allows a policy such as requiring an explicit charset to
+// be enforced, or a custom charset sniffer to be used. Return
+// encoding.Nop to leave content undecoded.
+func WithEncodingResolver(fn func(ci *email.ContentInfo) encoding.Encoding) Opt {
+	return func(p *Parser) {
+		p.encodingResolver = fn
+	}
+}
+
+// WithCharsetFallback sets the charset, such as "iso-8859-15", used to
+// decode content and encoded-word headers declaring a charset which
+// cannot be found, such as a misspelt or obscure label. The default is
+// "windows-1252", the pragmatic choice for mislabelled western email.
+// Setting an empty or unknown label passes the undecoded bytes through
+// unchanged, while such encoded-words fail to decode.
+func WithCharsetFallback(label string) Opt {
+	return func(p *Parser) {
+		p.charsetFallback, _ = email.LookupCharset(label)
+	}
+}
+
+// WithFirstTextIsBody uses only the first text/plain, text/html and
+// text/enriched part of a multipart email for the respective body
+// field. Subsequent text parts of the same type, such as appended logs
+// or diffs lacking a Content-Disposition, are treated as attached
+// files. The default is to concatenate all text parts of each type.
+func WithFirstTextIsBody() Opt {
+	return func(p *Parser) {
+		p.firstTextIsBody = true
+	}
+}
+
+// WithOrderedParts records the text, enriched text and html body parts
+// and the files of an email in document order in
+// email.Email.OrderedParts, in addition to the usual fields. This
+// allows the reading flow of an email interleaving text and inline
+// images to be reconstructed.
+func WithOrderedParts() Opt {
+	return func(p *Parser) {
+		p.orderedParts = true
+	}
+}
+
+// WithDefaultDisposition sets the disposition, "inline" or
+// "attachment", applied to file parts which lack a Content-Disposition
+// header, in place of the default heuristics. This determines the
+// email.File.FileType of such files. Body text and multipart parts are
+// unaffected. Other values are ignored.
+func WithDefaultDisposition(d string) Opt {
+	return func(p *Parser) {
+		switch d = strings.ToLower(strings.TrimSpace(d)); d {
+		case "inline", "attachment":
+			p.defaultDisposition = d
+		}
+	}
+}
+
+// WithHTMLToText derives a plain text body from the HTML body of
+// messages which have no text/plain body, or only an empty one, storing
+// it in email.Email.Text for consumers such as full-text indexers. The
+// HTML is rendered with email.HTMLToText, which strips tags, scripts
+// and styles, decodes entities, collapses whitespace and formats line
+// breaks, paragraphs, lists and links. email.Email.HTML is unaltered
+// and a genuine plain text body is never replaced.
+func WithHTMLToText() Opt {
+	return func(p *Parser) {
+		p.htmlToText = true
+	}
+}
+
+// WithDropEmptyTextParts drops text/plain, text/enriched and text/html
+// parts which are empty or contain only whitespace once decoded, such
+// as the placeholder text alternatives of some newsletters, so that
+// they neither add separators to the respective email body field nor
+// appear in email.Email.OrderedParts. With WithFirstTextIsBody a
+// dropped part does not count as the first text part of its type.
+func WithDropEmptyTextParts() Opt {
+	return func(p *Parser) {
+		p.dropEmptyTextParts = true
+	}
+}
+
+// WithInferContentType infers the media type of files declared as
+// application/octet-stream from their file name extension, recording
+// it in email.File.InferredType without altering the declared
+// ContentInfo.Type.
+func WithInferContentType() Opt {
+	return func(p *Parser) {
+		p.inferContentType = true
+	}
+}
+
+// WithDetectMagicBytes sniffs the media type of each file from its
+// first 512 decoded bytes using http.DetectContentType, recording it in
+// email.File.SniffedType. email.File.TypeMismatch is set if the sniffed
+// type disagrees with the declared type or the type implied by the
+// file name extension, such as an executable named "invoice.pdf",
+// which may indicate a masqueraded file. Custom file funcs receive the
+// full content.
+func WithDetectMagicBytes() Opt {
+	return func(p *Parser) {
+		p.detectMagicBytes = true
+	}
+}
+
+// WithFileChecksums computes the SHA-256 checksum of the decoded content
+// of each file as it is read by the file func, recording it in
+// email.File.SHA256. This includes files read by custom file funcs
+// which don't retain the file data, such as those streaming to disk.
+// Content not read by a custom file func is read and discarded to
+// complete the checksum.
+func WithFileChecksums() Opt {
+	return func(p *Parser) {
+		p.fileChecksums = true
+	}
+}
+
+// WithMaxAttachmentSize sets the maximum decoded size in bytes of each
+// inline or attached file, guarding against exhausting memory with
+// hostile messages. The limit is enforced as the file is streamed to
+// the file func, whether the default or one set with
+// WithCustomFileFunc, by reads of the file's Reader failing with an
+// error wrapping ErrAttachmentTooLarge naming the file and limit. The
+// limit is also recorded in email.File.MaxSize for custom file funcs.
+// Parsing fails with the error unless WithLenient is set, in which case
+// the file is retained with the error in email.File.DecodeError. The
+// default is unbounded.
+func WithMaxAttachmentSize(n int64) Opt {
+	return func(p *Parser) {
+		p.maxAttachmentSize = n
+	}
+}
+
+// WithMaxPartSize sets the maximum decoded size in bytes of each
+// non-multipart part, including the text and html bodies as well as
+// files, guarding against hostile messages such as those with highly
+// compressible quoted-printable or base64 bodies. The limit is enforced
+// as the content is decoded, by reads failing with an error wrapping
+// ErrPartTooLarge naming the content type and limit, rather than after
+// it is buffered. Parsing fails with the error unless WithLenient is
+// set, in which case a body part of a multipart message is skipped and
+// a file retained with the error in email.File.DecodeError, with a
+// warning recorded. The default is unbounded.
+func WithMaxPartSize(n int64) Opt {
+	return func(p *Parser) {
+		p.maxPartSize = n
+	}
+}
+
+// WithMaxMessageSize sets the maximum size in bytes of the raw message
+// read by Parse, ParseStream or ParseStructure, including its headers,
+// guarding against a hostile or runaway reader exhausting memory.
+// Reading beyond the limit fails with an error wrapping
+// ErrMessageTooLarge, which fails the parse even if WithLenient is set;
+// oversized messages are never silently truncated. The limit applies to
+// the undecoded message, so it takes precedence over a larger limit set
+// with WithMaxAttachmentSize. Each message of an mbox parsed with
+// ParseMbox is limited separately. The default is unbounded.
+func WithMaxMessageSize(n int64) Opt {
+	return func(p *Parser) {
+		p.maxMessageSize = n
+	}
+}
+
+// WithCollapseHeaderWhitespace collapses runs of spaces and tabs to a
+// single space in the decoded Subject and Comments headers and in the
+// display names of addresses, tidying the display of headers from
+// MTAs which fold badly. Addresses themselves and other structured
+// fields are unaltered. The default leaves whitespace as-is.
+func WithCollapseHeaderWhitespace() Opt {
+	return func(p *Parser) {
+		p.collapseHeaderWhitespace = true
+	}
+}
+
+// WithDetectInlinePGP scans plain text bodies for inline OpenPGP
+// armored blocks, such as "-----BEGIN PGP MESSAGE-----", as sent by
+// clients which don't use PGP/MIME. The first block found is recorded
+// in email.Email.InlinePGP, including the cleartext of clearsigned
+// messages. Blocks are neither verified nor decrypted.
+func WithDetectInlinePGP() Opt {
+	return func(p *Parser) {
+		p.detectInlinePGP = true
+	}
+}
+
+// WithRawSubject retains the Subject header value as it appears on the
+// wire in email.Headers.RawSubject, before unfolding and RFC 2047
+// decoding, allowing the encoded form to be compared to the decoded
+// Subject. The header block is captured as it is read, as it is for
+// email.Headers.RawOrdered.
+func WithRawSubject() Opt {
+	return func(p *Parser) {
+		p.rawSubject = true
+	}
+}
+
+// WithSkipResentHeaders skips the parsing of the Resent-Date,
+// Resent-From, Resent-Sender, Resent-To, Resent-Cc, Resent-Bcc and
+// Resent-Message-ID headers, leaving the respective email.Headers
+// fields empty, which saves address parsing work for consumers which
+// don't use them. The headers are still captured in
+// email.Headers.RawOrdered.
+func WithSkipResentHeaders() Opt {
+	return func(p *Parser) {
+		p.skipResentHeaders = true
+	}
+}
+
+// WithRetainRaw retains the raw bytes of the message in
+// email.Email.RawMessage, which can be useful for reproducing parsing
+// problems. Note that this holds the whole message in memory: the
+// buffer is bounded by WithMaxMessageSize, but is unbounded without it.
+func WithRetainRaw() Opt {
+	return func(p *Parser) {
+		p.retainRaw = true
+	}
+}
+
+// WithLazyAddresses defers the parsing of the From, Reply-To, To, Cc,
+// Bcc and Resent address list headers until first access through
+// email.Headers.ParsedAddresses or methods such as ParsedTo, rather
+// than populating the respective Headers fields. This saves work for
+// consumers which seldom use addresses.
+func WithLazyAddresses() Opt {
+	return func(p *Parser) {
+		p.lazyAddresses = true
+	}
+}
+
+// WithRawHeaders captures all headers in the order they appear in the
+// message, with their values unfolded but not decoded, in
+// email.Headers.RawOrdered. Duplicate headers, such as Received, are
+// retained in sequence.
+//
+// Deprecated: email.Headers.RawOrdered is now always populated, and
+// this option has no effect.
+func WithRawHeaders() Opt {
+	return func(p *Parser) {}
+}
+
+// WithMaxPartDepth sets the maximum nesting depth of multipart parts,
+// guarding against crafted messages with deeply nested multiparts which
+// would otherwise exhaust the stack. A multipart message body has a
+// depth of 1, and each multipart nested within it one more. Parsing an
+// email exceeding the limit returns an error wrapping
+// ErrMaxPartDepthExceeded. The default is 50; a value of 0 or less
+// removes the limit.
+func WithMaxPartDepth(n int) Opt {
+	return func(p *Parser) {
+		p.maxPartDepth = max(n, 0)
+	}
+}
+
+// WithMaxMessageDepth sets the maximum nesting depth of enclosed
+// message/rfc822 messages, such as forwarded emails or the messages of
+// a digest, parsed into SubMessages. The messages enclosed in the
+// outermost email have a depth of 1, and messages enclosed within them
+// one more. More deeply nested messages are not parsed, recording a
+// warning wrapping ErrMaxMessageDepthExceeded, although enclosed
+// messages remain available as Files. The default of 0 removes the
+// limit, although enclosed messages still count towards the maximum
+// part depth.
+func WithMaxMessageDepth(n int) Opt {
+	return func(p *Parser) {
+		p.maxMessageDepth = max(n, 0)
+	}
+}
+
+// WithMaxHeaders sets the maximum number of header fields permitted in
+// an email, guarding against messages with an absurd number of headers.
+// Headers are counted as they are read, so parsing an email exceeding
+// the limit stops before the remainder of its headers are buffered,
+// returning an error wrapping ErrTooManyHeaders. The default is
+// unbounded.
+func WithMaxHeaders(n int) Opt {
+	return func(p *Parser) {
+		p.maxHeaders = n
+	}
+}
+
+// WithMaxLineLength sets the maximum length in bytes of a line of an
+// email, excluding its line ending, guarding against hostile messages
+// with pathologically long lines. Folded headers are measured as a
+// single line once unfolded, while body lines are measured
+// individually. Parsing an email exceeding the limit returns an error
+// wrapping ErrLineTooLong. The default is unbounded.
+func WithMaxLineLength(n int) Opt {
+	return func(p *Parser) {
+		p.maxLineLength = n
+	}
+}
+
 // inSkipContentTypes determines if a content-type should be skipped
 func (p *Parser) inSkipContentTypes(ct string) bool {
+	ct = baseMediaType(ct)
 	for _, s := range p.skipContentTypes {
-		if s == ct {
+		if mediaTypeMatches(baseMediaType(s), ct) {
 			return true
 		}
 	}
 	return false
 }
 
+// mediaTypeMatches reports if the base media type ct matches the
+// pattern, being either a base media type or a wildcard such as
+// "image/*".
+func mediaTypeMatches(pattern, ct string) bool {
+	if pattern == ct {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "/*")
+	return ok && strings.HasPrefix(ct, prefix+"/")
+}
+
+// WithContentTypeHandler registers a handler for the parts of a
+// multipart email with the given content type, which may be a wildcard
+// such as "application/*" matching all subtypes. The handler receives
+// the part content, transfer encoded as described by the
+// email.ContentInfo (decoders.DecodeContent may be used to decode it),
+// and its content info. A handler takes precedence over the built-in
+// processing of the type, so that otherwise unknown types such as
+// "text/markdown" can be handled, but is not consulted for multipart
+// types or skipped content types. A handler for an exact type is
+// preferred to a wildcard. Handler errors abort the parse unless
+// WithLenient is set, in which case they are recorded as errors of the
+// email.
+func WithContentTypeHandler(contentType string, handler func(io.Reader, *email.ContentInfo) error) Opt {
+	return func(p *Parser) {
+		if p.contentTypeHandlers == nil {
+			p.contentTypeHandlers = map[string]func(io.Reader, *email.ContentInfo) error{}
+		}
+		p.contentTypeHandlers[baseMediaType(contentType)] = handler
+	}
+}
+
+// contentTypeHandler returns the handler registered for the content
+// type, preferring an exact match to a wildcard, or nil if there is
+// none.
+func (p *Parser) contentTypeHandler(ct string) func(io.Reader, *email.ContentInfo) error {
+	if len(p.contentTypeHandlers) == 0 {
+		return nil
+	}
+	ct = baseMediaType(ct)
+	if h, ok := p.contentTypeHandlers[ct]; ok {
+		return h
+	}
+	if mainType, _, ok := strings.Cut(ct, "/"); ok {
+		return p.contentTypeHandlers[mainType+"/*"]
+	}
+	return nil
+}
+
+// baseMediaType returns the lowercased media type without parameters
+func baseMediaType(s string) string {
+	s, _, _ = strings.Cut(s, ";")
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
 // WithoutAttachments skips parsing email attachments, which often
 // provides a speedup in processing.
 func WithoutAttachments() Opt {
@@ -53,6 +507,16 @@ func WithoutAttachments() Opt {
 	}
 }
 
+// WithTextOnly parses only the headers and the text/plain body of an
+// email, skipping html and enriched text bodies as well as inline and
+// attached files without decoding them. This is considerably faster
+// than WithoutAttachments for indexing messages with html alternatives.
+func WithTextOnly() Opt {
+	return func(p *Parser) {
+		p.processType = textOnly
+	}
+}
+
 // WithCustomDateFunc allows for the provision of a custom date parsing
 // func.
 func WithCustomDateFunc(df func(string) (time.Time, error)) Opt {
@@ -61,16 +525,66 @@ func WithCustomDateFunc(df func(string) (time.Time, error)) Opt {
 	}
 }
 
+// WithDefaultTimezone provides a location used to interpret dates
+// lacking a timezone, such as "1 Jan 2000 12:00:00", which would
+// otherwise fail to parse. The default location is only used if the
+// date func fails, and its use is recorded as a warning in
+// email.Email.Warnings.
+func WithDefaultTimezone(loc *time.Location) Opt {
+	return func(p *Parser) {
+		p.defaultTimezone = loc
+	}
+}
+
 // WithCustomAddressFunc allows for the provision of a custom func for
-// parsing an email name/address combination.
+// parsing an email name/address combination. This replaces the address
+// func set by WithStrictAddresses, the last of the options given
+// taking effect.
 func WithCustomAddressFunc(af func(string) (*mail.Address, error)) Opt {
 	return func(p *Parser) {
 		p.addressFunc = af
 	}
 }
 
+// WithStrictAddresses replaces the address funcs with validating funcs
+// which reject any address that is not strictly RFC 5322 compliant, for
+// example those using obsolete syntax or lacking angle brackets around
+// an address following a display name. Parsing is aborted with an
+// error naming the offending header and wrapping an *AddressError
+// describing the offending token, its position and the reason.
+//
+// This replaces the address func set by WithCustomAddressFunc and the
+// address list func set by WithAddressComments or
+// WithCustomAddressesFunc, the last of these options given taking
+// effect.
+func WithStrictAddresses() Opt {
+	return func(p *Parser) {
+		p.addressFunc = strictParseAddress
+		p.addressesFunc = strictParseAddressList
+	}
+}
+
+// WithAddressComments parses address lists leniently, removing RFC 5322
+// parenthetical comments, such as "(Primary) john@example.com (work)",
+// which net/mail would otherwise reject or discard. The comments of the
+// From addresses are captured in email.Headers.FromComments keyed by
+// address. As with net/mail, a comment following an address lacking a
+// display name is used as the name.
+//
+// This replaces the address list func set by WithStrictAddresses or
+// WithCustomAddressesFunc, the last of these options given taking
+// effect. The From comments are captured regardless of the order.
+func WithAddressComments() Opt {
+	return func(p *Parser) {
+		p.addressComments = true
+		p.addressesFunc = commentedParseAddressList
+	}
+}
+
 // WithCustomAddressesFunc allows for the provision of a custom func for
-// parsing lists of email names and addresses from strings.
+// parsing lists of email names and addresses from strings. This
+// replaces the address list func set by WithStrictAddresses or
+// WithAddressComments, the last of these options given taking effect.
 func WithCustomAddressesFunc(af func(list string) ([]*mail.Address, error)) Opt {
 	return func(p *Parser) {
 		p.addressesFunc = af
@@ -86,9 +600,62 @@ func WithCustomAddressesFunc(af func(list string) ([]*mail.Address, error)) Opt
 // Note that all the fields of Parser and email.File are
 // available for custom uses, such as file filtering, file saving or
 // sending files over the network.
+//
+// Since a custom func is not assumed to retain file content in
+// email.File.Data, enclosed message/rfc822 messages are spooled as
+// they are read, in memory or, if large, to a temporary file, to be
+// parsed into SubMessages.
 func WithCustomFileFunc(ff func(*email.File) error) Opt {
 	return func(p *Parser) {
 		p.fileFunc = ff
+		p.fileFuncRetains = false
+	}
+}
+
+// WithLazyFiles retains the decoded content of each inline or attached
+// file in email.File.Lazy rather than reading it into email.File.Data,
+// so that consumers can later choose which files to read, any number of
+// times. Content larger than threshold bytes is spooled to a temporary
+// file in dir, or the default directory for temporary files if dir is
+// empty, limiting the memory used by large files. Email.Close should
+// be called to remove the temporary files once the email is no longer
+// needed; those of an email which fails to parse are removed by the
+// parser. This replaces the file func, including one set with
+// WithCustomFileFunc.
+func WithLazyFiles(threshold int64, dir string) Opt {
+	return func(p *Parser) {
+		threshold = max(threshold, 0)
+		p.fileFunc = func(f *email.File) error {
+			return spoolFile(f, threshold, dir)
+		}
+		p.fileFuncRetains = true
+	}
+}
+
+// WithHeaderCallback allows for the provision of a func which is called
+// with the canonical name and decoded value of each header, including
+// both explicit and extra headers, as the headers are parsed and before
+// the email body is read. The callback is observational and is called
+// for headers in no particular order, once for each value of a repeated
+// header.
+func WithHeaderCallback(hc func(name, decodedValue string)) Opt {
+	return func(p *Parser) {
+		p.headerCallback = hc
+	}
+}
+
+// WithBoundaryFunc allows for the provision of a custom func for
+// determining the boundary of a multipart part. The func is provided
+// with the part's content info and a peek of up to boundaryPeekSize
+// bytes of the part content, and should return the boundary to use.
+// If the func returns an empty string the declared boundary is used.
+//
+// This is an escape hatch for malformed messages where the declared
+// boundary does not match the actual "--boundary" delimiter lines, for
+// example due to quoting or whitespace errors.
+func WithBoundaryFunc(bf func(ci *email.ContentInfo, peek []byte) string) Opt {
+	return func(p *Parser) {
+		p.boundaryFunc = bf
 	}
 }
 
@@ -102,13 +669,22 @@ func WithCustomFileFunc(ff func(*email.File) error) Opt {
 // sequence using its file name suffix (if available).
 //
 // Caution should be used using the filename provided in
-// internet-provided emails, although some cleaning is done by the
-// parsers module -- see Parser.parseFile.
+// internet-provided emails. Although some cleaning is done by
+// Parser.parseFile, each name is sanitized again before saving: any
+// directory components, including those separated by back slashes, and
+// control characters are removed, and names which are then empty or
+// only dots are replaced by "attachment-<n>". Existing files are never
+// overwritten; a number is added to the name instead, such as
+// "report (1).pdf".
 func WithSaveFilesToDirectory(dir string) Opt {
 	return func(p *Parser) {
+		p.fileFuncRetains = false
+		var saved atomic.Int64
 		// attach the inline func to p.fileFunc
 		p.fileFunc = func(ef *email.File) error {
-			f, err := os.Create(filepath.Join(dir, ef.Name))
+			n := saved.Add(1)
+			name := sanitizeFileName(ef.Name, fmt.Sprintf("attachment-%d", n))
+			f, err := createUniqueFile(dir, name)
 			if err != nil {
 				return fmt.Errorf("file creation error %w", err)
 			}
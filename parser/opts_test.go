@@ -1,14 +1,25 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"net/mail"
 	"os"
 	"slices"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/decoders"
+	"github.com/rorycl/letters/email"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
 )
 
 func TestOptVerbose(t *testing.T) {
@@ -27,6 +38,107 @@ func TestOptWithoutAttachments(t *testing.T) {
 	}
 }
 
+func TestOptPartInspector(t *testing.T) {
+
+	c, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// skip html and one image, recording the parts inspected
+	seen := []string{}
+	inspector := func(ci *email.ContentInfo) (bool, error) {
+		seen = append(seen, ci.Type)
+		return ci.Type == "text/html" || ci.TypeParams["name"] == "cat1.jpg", nil
+	}
+	em, err := NewParser(WithPartInspector(inspector)).Parse(bytes.NewReader(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"multipart/alternative", "text/plain", "text/html", "image/png", "image/jpeg", "image/jpeg"}
+	if diff := cmp.Diff(want, seen); diff != "" {
+		t.Errorf("unexpected parts inspected (-want +got):\n%s", diff)
+	}
+	if em.Text == "" || em.HTML != "" {
+		t.Errorf("got text %q html %q", em.Text, em.HTML)
+	}
+	names := []string{}
+	for _, f := range em.Files {
+		names = append(names, f.Name)
+	}
+	if diff := cmp.Diff([]string{"cat2.png", "cat3.jpg"}, names); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+
+	// an error aborts the parse, even if lenient
+	errStop := errors.New("stop")
+	stopper := func(ci *email.ContentInfo) (bool, error) {
+		if strings.HasPrefix(ci.Type, "image/") {
+			return false, errStop
+		}
+		return false, nil
+	}
+	_, err = NewParser(WithPartInspector(stopper), WithLenient()).Parse(bytes.NewReader(c))
+	if !errors.Is(err, errStop) {
+		t.Errorf("got error %v want %v", err, errStop)
+	}
+}
+
+func TestOptTextOnly(t *testing.T) {
+
+	c, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	whole, err := NewParser().Parse(bytes.NewReader(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := NewParser(WithTextOnly()).Parse(bytes.NewReader(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Text, whole.Text; got != want || got == "" {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := em.Headers.Subject, whole.Headers.Subject; got != want {
+		t.Errorf("got subject %q want %q", got, want)
+	}
+	if em.HTML != "" {
+		t.Errorf("unexpected html %q", em.HTML)
+	}
+	if got, want := len(em.Files), 0; got != want {
+		t.Errorf("got %d files want %d", got, want)
+	}
+
+	// single part html and attachment messages have no body
+	for _, msg := range []string{
+		"Subject: html\r\nContent-Type: text/html\r\n\r\n<p>Hello</p>\r\n",
+		"Subject: file\r\nContent-Type: application/pdf\r\n\r\n%PDF-1.4\r\n",
+	} {
+		em, err := NewParser(WithTextOnly()).Parse(strings.NewReader(msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if em.Text != "" || em.HTML != "" || len(em.Files) != 0 {
+			t.Errorf("unexpected body or files parsing %q", msg)
+		}
+	}
+
+	// the skipping of files is particular to WithTextOnly, leaving the
+	// handling of a single part attachment by WithoutAttachments as it
+	// was
+	em, err = NewParser(WithoutAttachments()).Parse(strings.NewReader(
+		"Subject: file\r\nContent-Type: application/pdf\r\n\r\n%PDF-1.4\r\n",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Files), 1; got != want {
+		t.Errorf("got %d files without attachments want %d", got, want)
+	}
+}
+
 func TestOptSkipContentTypes(t *testing.T) {
 
 	tests := []struct {
@@ -57,6 +169,34 @@ func TestOptSkipContentTypes(t *testing.T) {
 			skipTypeOK:    true,
 			attachmentsNo: 0,
 		},
+		{
+			skips:         []string{"IMAGE/JPEG; name=cat1.jpg"},
+			skipLen:       1,
+			skipType:      "image/jpeg",
+			skipTypeOK:    true,
+			attachmentsNo: 1,
+		},
+		{
+			skips:         []string{"image/*"},
+			skipLen:       1,
+			skipType:      "Image/GIF",
+			skipTypeOK:    true,
+			attachmentsNo: 0,
+		},
+		{
+			skips:         []string{"image/*"},
+			skipLen:       1,
+			skipType:      "imagex/png",
+			skipTypeOK:    false,
+			attachmentsNo: 0,
+		},
+		{
+			skips:         []string{"application/*"},
+			skipLen:       1,
+			skipType:      "image/png",
+			skipTypeOK:    false,
+			attachmentsNo: 3,
+		},
 	}
 
 	for i, tt := range tests {
@@ -197,3 +337,1719 @@ func TestOptSaveFilesToDirectory(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestOptBoundaryFunc(t *testing.T) {
+
+	// the declared boundary does not match the delimiter lines
+	msg := `From: someone@example.com
+Subject: Broken boundary
+Content-Type: multipart/mixed; boundary="declared"
+
+--actual
+Content-Type: text/plain
+
+Hello from the actual boundary.
+--actual--
+`
+
+	// boundaryFunc finds the first line starting with "--"
+	boundaryFunc := func(ci *email.ContentInfo, peek []byte) string {
+		for _, line := range strings.Split(string(peek), "\n") {
+			if strings.HasPrefix(line, "--") {
+				return strings.TrimSpace(strings.TrimPrefix(line, "--"))
+			}
+		}
+		return ""
+	}
+
+	tests := []struct {
+		opts  []Opt
+		isErr bool
+		text  string
+	}{
+		{
+			opts:  []Opt{},
+			isErr: true,
+		},
+		{
+			opts:  []Opt{WithBoundaryFunc(boundaryFunc)},
+			isErr: false,
+			text:  "Hello from the actual boundary.",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			p := NewParser(tt.opts...)
+			em, err := p.Parse(strings.NewReader(msg))
+			if got, want := err != nil, tt.isErr; got != want {
+				t.Fatalf("got error %t want %t: %v", got, want, err)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := em.Text, tt.text; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOptDefaultTimezone(t *testing.T) {
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	msg := `From: someone@example.com
+Date: 1 Jan 2000 12:00:00
+Subject: A date without a timezone
+
+Body.
+`
+	tests := []struct {
+		opts     []Opt
+		isErr    bool
+		date     time.Time
+		warnings int
+	}{
+		{
+			opts:  []Opt{},
+			isErr: true,
+		},
+		{
+			opts:     []Opt{WithDefaultTimezone(loc)},
+			isErr:    false,
+			date:     time.Date(2000, 1, 1, 12, 0, 0, 0, loc),
+			warnings: 1,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			p := NewParser(tt.opts...)
+			em, err := p.Parse(strings.NewReader(msg))
+			if got, want := err != nil, tt.isErr; got != want {
+				t.Fatalf("got error %t want %t: %v", got, want, err)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := em.Headers.Date, tt.date; !got.Equal(want) {
+				t.Errorf("got %s want %s", got, want)
+			}
+			if got, want := len(em.Warnings), tt.warnings; got != want {
+				t.Errorf("got %d want %d warnings", got, want)
+			}
+		})
+	}
+}
+
+func TestOptSniffTransferEncoding(t *testing.T) {
+
+	tests := []struct {
+		opts     []Opt
+		data     string
+		warnings int
+	}{
+		{
+			opts:     []Opt{},
+			data:     "VGhpcyBhdHRhY2htZW50IHdhcyBzZW50IGFzIGJh",
+			warnings: 0,
+		},
+		{
+			opts:     []Opt{WithSniffTransferEncoding()},
+			data:     "This attachment was sent as base64 but declared as binary by a broken sender.",
+			warnings: 1,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/binary_base64.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			p := NewParser(tt.opts...)
+			em, err := p.Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(em.Files), 1; got != want {
+				t.Fatalf("got %d want %d files", got, want)
+			}
+			if got, want := string(em.Files[0].Data), tt.data; !strings.HasPrefix(got, want) {
+				t.Errorf("got %q want prefix %q", got, want)
+			}
+			if got, want := em.Files[0].ContentInfo.TransferEncoding, "binary"; got != want {
+				t.Errorf("transfer encoding got %s want %s", got, want)
+			}
+			if got, want := len(em.Warnings), tt.warnings; got != want {
+				t.Errorf("got %d want %d warnings", got, want)
+			}
+		})
+	}
+}
+
+func TestOptSniffIdentityEncodings(t *testing.T) {
+
+	tests := []struct {
+		opts     []Opt
+		text     string
+		data     string
+		warnings int
+	}{
+		{
+			opts:     []Opt{},
+			text:     "Caf=C3=A9 au lait",
+			data:     "VGhpcyBhdHRhY2htZW50IHdhcyBzZW50IGFzIGJh",
+			warnings: 0,
+		},
+		{
+			opts:     []Opt{WithSniffTransferEncoding()},
+			text:     "Caf=C3=A9 au lait",
+			data:     "VGhpcyBhdHRhY2htZW50IHdhcyBzZW50IGFzIGJh",
+			warnings: 0,
+		},
+		{
+			opts:     []Opt{WithSniffIdentityEncodings()},
+			text:     "Café au lait, crème brûlée and a very long line which has been softly broken.",
+			data:     "This attachment was sent as base64 but declared as 7bit by a broken sender.",
+			warnings: 2,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/undeclared_encodings.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			p := NewParser(tt.opts...)
+			em, err := p.Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, tt.text; !strings.HasPrefix(got, want) {
+				t.Errorf("text got %q want prefix %q", got, want)
+			}
+			if got, want := len(em.Files), 1; got != want {
+				t.Fatalf("got %d want %d files", got, want)
+			}
+			if got, want := string(em.Files[0].Data), tt.data; !strings.HasPrefix(got, want) {
+				t.Errorf("got %q want prefix %q", got, want)
+			}
+			if got, want := em.Files[0].ContentInfo.TransferEncoding, "7bit"; got != want {
+				t.Errorf("transfer encoding got %s want %s", got, want)
+			}
+			if got, want := len(em.Warnings), tt.warnings; got != want {
+				t.Errorf("got %d want %d warnings", got, want)
+			}
+		})
+	}
+}
+
+func TestOptRawSubject(t *testing.T) {
+	msg := `From: someone@example.com
+Subject: =?UTF-8?B?8J+Tpw==?= Test
+
+Body.
+`
+	tests := []struct {
+		opts       []Opt
+		rawSubject string
+	}{
+		{[]Opt{}, ""},
+		{[]Opt{WithRawSubject()}, "=?UTF-8?B?8J+Tpw==?= Test"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Headers.Subject, "📧 Test"; got != want {
+				t.Errorf("subject got %q want %q", got, want)
+			}
+			if got, want := em.Headers.RawSubject, tt.rawSubject; got != want {
+				t.Errorf("raw subject got %q want %q", got, want)
+			}
+		})
+	}
+
+	// the raw subject retains its folding
+	folded := "From: someone@example.com\r\n" +
+		"Subject: =?UTF-8?B?8J+Tpw==?=\r\n\t=?UTF-8?Q?Test?=\r\n\r\nBody.\r\n"
+	em, err := NewParser(WithRawSubject()).Parse(strings.NewReader(folded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Headers.Subject, "📧Test"; got != want {
+		t.Errorf("subject got %q want %q", got, want)
+	}
+	if got, want := em.Headers.RawSubject, "=?UTF-8?B?8J+Tpw==?=\r\n\t=?UTF-8?Q?Test?="; got != want {
+		t.Errorf("raw subject got %q want %q", got, want)
+	}
+}
+
+func TestOptHeaderCallback(t *testing.T) {
+	msg := `From: someone@example.com
+Subject: =?UTF-8?B?8J+Tpw==?= Test
+X-Route: queue-1
+X-Route: queue-2
+
+Body.
+`
+	headers := []string{}
+	callback := func(name, decodedValue string) {
+		headers = append(headers, name+": "+decodedValue)
+	}
+	_, err := NewParser(WithHeaderCallback(callback)).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(headers)
+	want := []string{
+		"From: someone@example.com",
+		"Subject: 📧 Test",
+		"X-Route: queue-1",
+		"X-Route: queue-2",
+	}
+	if diff := cmp.Diff(want, headers); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOptFirstTextIsBody(t *testing.T) {
+
+	tests := []struct {
+		opts      []Opt
+		textLines int
+		files     int
+	}{
+		{opts: []Opt{}, textLines: 10, files: 0},
+		{opts: []Opt{WithFirstTextIsBody()}, textLines: 1, files: 2},
+		{opts: []Opt{WithFirstTextIsBody(), WithoutAttachments()}, textLines: 1, files: 0},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/two_text_parts.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			em, err := NewParser(tt.opts...).Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(strings.Split(em.Text, "\n")), tt.textLines; got != want {
+				t.Errorf("got %d want %d lines of text:\n%s", got, want, em.Text)
+			}
+			if got, want := len(em.Files), tt.files; got != want {
+				t.Fatalf("got %d want %d files", got, want)
+			}
+			for _, file := range em.Files {
+				if got, want := file.FileType, "attachment"; got != want {
+					t.Errorf("file type got %s want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptHTMLToText(t *testing.T) {
+
+	html := "<html><head><style>p { color: red; }</style></head><body>" +
+		"<script>alert('x')</script><p>Fish &amp; chips<br>on Friday</p>" +
+		"<ul><li>cod</li><li>haddock</li></ul>" +
+		"<a href=\"https://example.com/menu\">menu</a></body></html>"
+	htmlOnly := "Subject: html only\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n\r\n" + html + "\r\n"
+	alternative := func(text string) string {
+		return "Subject: alternative\r\n" +
+			"Content-Type: multipart/alternative; boundary=\"b\"\r\n\r\n" +
+			"--b\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n" + text + "\r\n" +
+			"--b\r\nContent-Type: text/html; charset=utf-8\r\n\r\n" + html + "\r\n" +
+			"--b--\r\n"
+	}
+	derived := "Fish & chips\non Friday\n\n* cod\n* haddock\n\nmenu (https://example.com/menu)"
+
+	tests := []struct {
+		msg  string
+		opts []Opt
+		text string
+	}{
+		{htmlOnly, nil, ""},
+		{htmlOnly, []Opt{WithHTMLToText()}, derived},
+		{alternative("Fish and chips on Friday."), []Opt{WithHTMLToText()}, "Fish and chips on Friday."},
+		{alternative(" "), []Opt{WithHTMLToText()}, derived},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(tt.msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, tt.text; got != want {
+				t.Errorf("got text %q want %q", got, want)
+			}
+			if got, want := em.HTML, html; got != want {
+				t.Errorf("got html %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOptDropEmptyTextParts(t *testing.T) {
+
+	tests := []struct {
+		opts  []Opt
+		text  string
+		kinds []string
+	}{
+		{
+			opts:  []Opt{WithOrderedParts()},
+			text:  "April issue preview\n\n",
+			kinds: []string{"text", "text", "html"},
+		},
+		{
+			opts:  []Opt{WithOrderedParts(), WithDropEmptyTextParts()},
+			text:  "April issue preview",
+			kinds: []string{"text", "html"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/empty_text_alternative.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			em, err := NewParser(tt.opts...).Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, tt.text; got != want {
+				t.Errorf("text got %q want %q", got, want)
+			}
+			if got, want := em.HTML, "<h1>April issue</h1><p>News from the spring.</p>"; got != want {
+				t.Errorf("html got %q want %q", got, want)
+			}
+			kinds := []string{}
+			for _, p := range em.OrderedParts {
+				kinds = append(kinds, p.Kind)
+			}
+			if diff := cmp.Diff(tt.kinds, kinds); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestOptOrderedParts(t *testing.T) {
+
+	// a newsletter interleaving text and images
+	msg := `From: news@example.com
+Subject: Newsletter
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: text/plain
+
+First story.
+--outer
+Content-Type: image/gif; name="one.gif"
+Content-Disposition: inline; filename="one.gif"
+Content-Transfer-Encoding: base64
+
+R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain
+
+Second story.
+--inner
+Content-Type: text/html
+
+<p>Second story.</p>
+--inner--
+--outer
+Content-Type: image/gif; name="two.gif"
+Content-Disposition: inline; filename="two.gif"
+Content-Transfer-Encoding: base64
+
+R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7
+--outer--
+`
+	summarise := func(parts []email.OrderedPart) []string {
+		s := []string{}
+		for _, p := range parts {
+			if p.File != nil {
+				s = append(s, p.Kind+":"+p.File.Name)
+				continue
+			}
+			s = append(s, p.Kind+":"+p.Text)
+		}
+		return s
+	}
+
+	em, err := NewParser().Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.OrderedParts), 0; got != want {
+		t.Errorf("got %d want %d ordered parts without option", got, want)
+	}
+
+	em, err = NewParser(WithOrderedParts()).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"text:First story.",
+		"file:one.gif",
+		"text:Second story.",
+		"html:<p>Second story.</p>",
+		"file:two.gif",
+	}
+	if diff := cmp.Diff(want, summarise(em.OrderedParts)); diff != "" {
+		t.Error(diff)
+	}
+
+	em, err = NewParser(WithOrderedParts()).Parse(strings.NewReader("Subject: plain\n\nJust text.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"text:Just text."}, summarise(em.OrderedParts)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// slowReader delays each read, simulating a pathologically slow or
+// large message
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	if len(p) > 16 {
+		p = p[:16]
+	}
+	return s.r.Read(p)
+}
+
+func TestOptTimeout(t *testing.T) {
+
+	body := "Subject: slow\nContent-Type: application/octet-stream\n\n" + strings.Repeat("x", 4096)
+
+	tests := []struct {
+		timeout time.Duration
+		ctx     func() context.Context
+		wantErr error
+	}{
+		{ // unbounded
+			timeout: 0,
+			ctx:     context.Background,
+			wantErr: nil,
+		},
+		{ // generous timeout
+			timeout: time.Minute,
+			ctx:     context.Background,
+			wantErr: nil,
+		},
+		{ // timeout exceeded
+			timeout: 20 * time.Millisecond,
+			ctx:     context.Background,
+			wantErr: ErrTimeout,
+		},
+		{ // cancelled context without timeout
+			timeout: 0,
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			wantErr: context.Canceled,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			delay := time.Duration(0)
+			if tt.wantErr != nil {
+				delay = 5 * time.Millisecond
+			}
+			r := &slowReader{r: strings.NewReader(body), delay: delay}
+			p := NewParser(WithTimeout(tt.timeout))
+			em, err := p.ParseContext(tt.ctx(), r)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(em.Files[0].Data), 4096; got != want {
+				t.Errorf("got %d want %d bytes", got, want)
+			}
+		})
+	}
+}
+
+func TestOptLenient(t *testing.T) {
+
+	open := func() *os.File {
+		f, err := os.Open("testdata/broken_attachment.eml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	// the corrupt attachment aborts the parse by default
+	f := open()
+	defer f.Close()
+	if _, err := NewParser().Parse(f); err == nil {
+		t.Fatal("expected error parsing corrupt attachment")
+	}
+
+	f = open()
+	defer f.Close()
+	em, err := NewParser(WithLenient()).Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Text, "Two attachments follow, the first of which is corrupt."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := len(em.Files), 2; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	if em.Files[0].DecodeError == nil {
+		t.Errorf("expected decode error for %s", em.Files[0].Name)
+	}
+	if em.Files[1].DecodeError != nil {
+		t.Errorf("unexpected decode error for %s: %v", em.Files[1].Name, em.Files[1].DecodeError)
+	}
+	if got, want := string(em.Files[1].Data), "Good attachment\n"; got != want {
+		t.Errorf("got data %q want %q", got, want)
+	}
+	if got, want := len(em.Errors), 1; got != want {
+		t.Errorf("got %d errors want %d", got, want)
+	}
+	if got, want := em.DecodeStats.LenientRepairs, 1; got != want {
+		t.Errorf("got %d lenient repairs want %d", got, want)
+	}
+}
+
+func TestOptLenientAccumulates(t *testing.T) {
+
+	msg := "From: not an address\r\n" +
+		"Subject: problems\r\n" +
+		"Date: not a date\r\n" +
+		"Content-Type: multipart/alternative; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain; charset=x-unknown\r\n\r\nHello\r\n" +
+		"--b\r\nContent-Type: text/x-made-up\r\n\r\n???\r\n" +
+		"--b--\r\n"
+
+	tests := []struct {
+		opts   []Opt
+		err    bool
+		errors int
+	}{
+		{opts: nil, err: true},
+		{opts: []Opt{WithStrict()}, err: true},
+		{opts: []Opt{WithLenient(), WithStrict()}, err: true},
+		{opts: []Opt{WithLenient()}, err: false, errors: 4},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if got, want := err != nil, tt.err; got != want {
+				t.Fatalf("got error %v want error %t", err, want)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := len(em.Errors), tt.errors; got != want {
+				t.Errorf("got %d errors want %d: %v", got, want, em.Errors)
+			}
+			// each recovered error is also recorded as a warning
+			if got, want := len(em.Warnings), tt.errors; got != want {
+				t.Errorf("got %d warnings want %d: %v", got, want, em.Warnings)
+			}
+			contentTypes := []string{}
+			for _, pe := range em.Errors {
+				contentTypes = append(contentTypes, pe.ContentType)
+			}
+			want := []string{"", "", "text/plain", "text/x-made-up"}
+			if diff := cmp.Diff(want, contentTypes); diff != "" {
+				t.Errorf("error content types: %s", diff)
+			}
+			var unknown *UnknownContentTypeError
+			if !slices.ContainsFunc(em.Errors, func(pe email.ParseError) bool { return errors.As(pe, &unknown) }) {
+				t.Errorf("no unknown content type error in %v", em.Errors)
+			}
+			if !slices.ContainsFunc(em.Warnings, func(w error) bool { return errors.As(w, &unknown) }) {
+				t.Errorf("no unknown content type warning in %v", em.Warnings)
+			}
+			if got, want := em.Headers.Subject, "problems"; got != want {
+				t.Errorf("got subject %q want %q", got, want)
+			}
+			if got, want := em.Text, "Hello"; got != want {
+				t.Errorf("got text %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOptRawHeaders(t *testing.T) {
+
+	msg := "Received: from a.example.com\r\n" +
+		"\tby b.example.com\r\n" +
+		"X-Mailer: Mutt\r\n" +
+		"From: alice@example.com\r\n" +
+		"Received: from c.example.com\r\n" +
+		"subject: =?utf-8?q?caf=C3=A9?=\r\n" +
+		"X-Spam: no\r\n" +
+		"\r\n" +
+		"Body text.\r\n"
+
+	em, err := NewParser().Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []email.RawHeader{
+		{Key: "Received", Value: "from a.example.com by b.example.com"},
+		{Key: "X-Mailer", Value: "Mutt"},
+		{Key: "From", Value: "alice@example.com"},
+		{Key: "Received", Value: "from c.example.com"},
+		{Key: "Subject", Value: "=?utf-8?q?caf=C3=A9?="},
+		{Key: "X-Spam", Value: "no"},
+	}
+	if diff := cmp.Diff(want, em.Headers.RawOrdered); diff != "" {
+		t.Error(diff)
+	}
+	wantExtra := []email.RawHeader{
+		{Key: "X-Mailer", Value: "Mutt"},
+		{Key: "X-Spam", Value: "no"},
+	}
+	if diff := cmp.Diff(wantExtra, em.Headers.ExtraHeadersOrdered()); diff != "" {
+		t.Error(diff)
+	}
+	if got, want := em.Headers.Subject, "café"; got != want {
+		t.Errorf("got subject %q want %q", got, want)
+	}
+	if got, want := em.Text, "Body text."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+}
+
+func TestOptDefaultDisposition(t *testing.T) {
+
+	tests := []struct {
+		disposition string
+		isErr       bool
+		fileTypes   []string
+	}{
+		{ // the disposition-less text/csv part is an unknown content type
+			disposition: "",
+			isErr:       true,
+		},
+		{
+			disposition: "invalid",
+			isErr:       true,
+		},
+		{
+			disposition: "attachment",
+			fileTypes:   []string{"attachment", "attachment"},
+		},
+		{
+			disposition: "Inline",
+			fileTypes:   []string{"inline", "inline"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/no_disposition.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			em, err := NewParser(WithDefaultDisposition(tt.disposition)).Parse(f)
+			if tt.isErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, "Your scanned documents are attached."; got != want {
+				t.Errorf("got text %q want %q", got, want)
+			}
+			fileTypes := []string{}
+			for _, f := range em.Files {
+				fileTypes = append(fileTypes, f.FileType)
+			}
+			if diff := cmp.Diff(tt.fileTypes, fileTypes); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestOptRetainRaw(t *testing.T) {
+
+	raw, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		opts []Opt
+		want []byte
+	}{
+		{nil, nil},
+		{[]Opt{WithRetainRaw()}, raw},
+		{[]Opt{WithRetainRaw(), WithHeadersOnly()}, raw},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(em.RawMessage, tt.want) {
+				t.Errorf("got %d raw bytes want %d", len(em.RawMessage), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestOptLazyAddresses(t *testing.T) {
+
+	msg := `From: Alice <alice@example.com>
+To: bob@example.com, Carol <carol@example.com>
+Cc: =?utf-8?q?Andr=C3=A9?= <andre@example.com>
+Bcc: not an address
+Subject: Lazy
+
+Body.
+`
+	// eager parsing fails on the invalid Bcc address
+	if _, err := NewParser().Parse(strings.NewReader(msg)); err == nil {
+		t.Fatal("expected eager address parsing error")
+	}
+
+	em, err := NewParser(WithLazyAddresses()).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if em.Headers.To != nil || em.Headers.From != nil {
+		t.Error("address fields should not be populated when lazy")
+	}
+
+	addressesOf := func(list []*mail.Address) []string {
+		s := []string{}
+		for _, a := range list {
+			s = append(s, a.String())
+		}
+		return s
+	}
+
+	to, err := em.Headers.ParsedTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"<bob@example.com>", `"Carol" <carol@example.com>`}, addressesOf(to)); diff != "" {
+		t.Error(diff)
+	}
+	cc, err := em.Headers.ParsedAddresses("cc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cc[0].Name, "André"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	replyTo, err := em.Headers.ParsedReplyTo()
+	if err != nil || replyTo != nil {
+		t.Errorf("got %v, %v for missing header", replyTo, err)
+	}
+	if _, err := em.Headers.ParsedBcc(); err == nil {
+		t.Error("expected lazy address parsing error")
+	}
+
+	// eager fields are returned without the option
+	em, err = NewParser().Parse(strings.NewReader(strings.Replace(msg, "Bcc: not an address\n", "", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := em.Headers.ParsedFrom()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := from[0], em.Headers.From[0]; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestOptCollapseHeaderWhitespace(t *testing.T) {
+
+	tests := []struct {
+		opts     []Opt
+		subject  string
+		comments string
+		names    []string
+	}{
+		{
+			opts:     nil,
+			subject:  "Quarterly    report for    review",
+			comments: "sent  \tfrom   a   buggy  MTA",
+			names:    []string{"Alice \t   Smith", "Mail Robot", "André Dupont", "Bob"},
+		},
+		{
+			opts:     []Opt{WithCollapseHeaderWhitespace()},
+			subject:  "Quarterly report for review",
+			comments: "sent from a buggy MTA",
+			names:    []string{"Alice Smith", "Mail Robot", "André Dupont", "Bob"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/overfolded.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			em, err := NewParser(tt.opts...).Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := em.Headers
+			if got, want := h.Subject, tt.subject; got != want {
+				t.Errorf("subject got %q want %q", got, want)
+			}
+			if got, want := h.Comments, tt.comments; got != want {
+				t.Errorf("comments got %q want %q", got, want)
+			}
+			names := []string{h.From[0].Name, h.Sender.Name, h.To[0].Name, h.To[1].Name}
+			if diff := cmp.Diff(tt.names, names); diff != "" {
+				t.Error(diff)
+			}
+			// addresses are unaltered
+			if got, want := h.From[0].Address, "alice.smith@example.com"; got != want {
+				t.Errorf("address got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOptMaxLineLength(t *testing.T) {
+
+	long, err := os.ReadFile("testdata/long_header.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	folded := "From: someone@example.com\r\nReferences: <" + strings.Repeat("a", 40) + ">\r\n <" +
+		strings.Repeat("b", 40) + ">\r\n <" + strings.Repeat("c", 40) + ">\r\n\r\n" +
+		strings.Repeat("d", 80) + "\r\n" + strings.Repeat("e", 80) + "\r\n"
+	longBody := "From: someone@example.com\n\nShort line.\n" + strings.Repeat("f", 150) + "\n"
+
+	tests := []struct {
+		msg           string
+		maxLineLength int
+		isErr         bool
+	}{
+		{string(long), 0, false},
+		{string(long), 200000, false},
+		{string(long), 998, true},
+		{folded, 0, false},
+		{folded, 200, false},
+		{folded, 100, true},
+		{longBody, 200, false},
+		{longBody, 100, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			p := NewParser(WithMaxLineLength(tt.maxLineLength))
+			_, err := p.Parse(strings.NewReader(tt.msg))
+			if got, want := errors.Is(err, ErrLineTooLong), tt.isErr; got != want {
+				t.Errorf("got line too long error %t want %t (%v)", got, want, err)
+			}
+			if err != nil && !tt.isErr {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestOptFileChecksums(t *testing.T) {
+
+	parse := func(opts ...Opt) map[string]string {
+		t.Helper()
+		f, err := os.Open("testdata/cats.eml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		em, err := NewParser(opts...).Parse(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return em.FileChecksums()
+	}
+
+	// checksums computed from the file data
+	want := parse()
+	if got, want := len(want), 3; got != want {
+		t.Fatalf("got %d want %d checksums", got, want)
+	}
+
+	// checksums computed while streaming, with a file func which only
+	// reads the start of each file
+	partialRead := func(f *email.File) error {
+		_, err := f.Reader.Read(make([]byte, 10))
+		return err
+	}
+	got := parse(WithFileChecksums(), WithCustomFileFunc(partialRead))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOptLenientOverlongSubject(t *testing.T) {
+
+	decoded := "Ihre Bestellung Nr. 4711 wurde versandt – voraussichtliche Zustellung am Freitag, 24. April, zwischen 9 und 12 Uhr durch DHL (Sendung 003404341612)"
+
+	tests := []struct {
+		opts     []Opt
+		decoded  bool
+		warnings int
+	}{
+		{opts: nil, decoded: false, warnings: 0},
+		{opts: []Opt{WithLenient()}, decoded: true, warnings: 1},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/overlong_subject.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			em, err := NewParser(tt.opts...).Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Headers.Subject == decoded, tt.decoded; got != want {
+				t.Errorf("got decoded %t want %t: %q", got, want, em.Headers.Subject)
+			}
+			if got, want := len(em.Warnings), tt.warnings; got != want {
+				t.Errorf("got %d warnings want %d", got, want)
+			}
+		})
+	}
+}
+
+// resentMsg is a message which has been resent
+const resentMsg = `From: Alice <alice@example.com>
+To: Bob <bob@example.net>
+Subject: Resent
+Date: Mon, 27 Apr 2026 10:00:00 +0000
+Resent-From: Bob <bob@example.net>
+Resent-Sender: Bob's Assistant <assistant@example.net>
+Resent-To: Carol <carol@example.org>, Dave <dave@example.org>
+Resent-Cc: Erin <erin@example.org>
+Resent-Date: Mon, 27 Apr 2026 11:00:00 +0000
+Resent-Message-ID: <resent-1@example.net>
+
+Body.
+`
+
+func TestOptSkipResentHeaders(t *testing.T) {
+
+	em, err := NewParser().Parse(strings.NewReader(resentMsg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Headers.ResentTo), 2; got != want {
+		t.Errorf("got %d resent to want %d", got, want)
+	}
+	if got, want := em.Headers.ResentMessageID, "resent-1@example.net"; got != want {
+		t.Errorf("got resent message id %q want %q", got, want)
+	}
+
+	em, err = NewParser(WithSkipResentHeaders()).Parse(strings.NewReader(resentMsg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := em.Headers
+	if h.ResentFrom != nil || h.ResentSender != nil || h.ResentTo != nil || h.ResentCc != nil ||
+		!h.ResentDate.IsZero() || h.ResentMessageID != "" {
+		t.Errorf("expected empty resent fields, got %+v", h)
+	}
+	if got, want := len(h.To), 1; got != want {
+		t.Errorf("got %d to want %d", got, want)
+	}
+	resent := 0
+	for _, r := range h.RawOrdered {
+		if strings.HasPrefix(r.Key, "Resent-") {
+			resent++
+		}
+	}
+	if got, want := resent, 6; got != want {
+		t.Errorf("got %d raw resent headers want %d", got, want)
+	}
+}
+
+func TestOptEncodingResolver(t *testing.T) {
+
+	msg := "Subject: resolver\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain; charset=x-legacy\r\n\r\ncaf\xe9\r\n" +
+		"--b\r\nContent-Type: text/html; charset=utf-8\r\n\r\n<p>caf\xc3\xa9</p>\r\n" +
+		"--b--\r\n"
+
+	// a charset only known to the resolver; others use the default
+	resolver := func(ci *email.ContentInfo) encoding.Encoding {
+		if ci.Charset == "x-legacy" {
+			return charmap.ISO8859_1
+		}
+		return nil
+	}
+
+	em, err := NewParser().Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.DecodeStats.CharsetFallbacks, 1; got != want {
+		t.Errorf("default charset fallbacks got %d want %d", got, want)
+	}
+
+	em, err = NewParser(WithEncodingResolver(resolver)).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Text, "café"; got != want {
+		t.Errorf("text got %q want %q", got, want)
+	}
+	if got, want := em.HTML, "<p>café</p>"; got != want {
+		t.Errorf("html got %q want %q", got, want)
+	}
+	if got, want := em.DecodeStats.CharsetFallbacks, 0; got != want {
+		t.Errorf("charset fallbacks got %d want %d", got, want)
+	}
+}
+
+func TestOptCharsetFallback(t *testing.T) {
+
+	msg := "From: Alice <alice@example.com>\r\n" +
+		"Subject: =?x-no-such-charset?Q?caf=E9?=\r\n" +
+		"Content-Type: text/plain; charset=x-no-such-charset\r\n" +
+		"\r\ncaf\xe9\r\n"
+
+	tests := []struct {
+		opts    []Opt
+		subject string
+		text    string
+		err     bool
+	}{
+		{nil, "café", "café", false},
+		{[]Opt{WithCharsetFallback("iso-8859-7")}, "cafι", "cafι", false},
+		{[]Opt{WithCharsetFallback("")}, "", "", true},
+		{[]Opt{WithCharsetFallback(""), WithLenient()}, "=?x-no-such-charset?Q?caf=E9?=", "caf\xe9", false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Headers.Subject, tt.subject; got != want {
+				t.Errorf("subject got %q want %q", got, want)
+			}
+			if got, want := em.Text, tt.text; got != want {
+				t.Errorf("text got %q want %q", got, want)
+			}
+			if got, want := em.DecodeStats.CharsetFallbacks, 1; got != want {
+				t.Errorf("charset fallbacks got %d want %d", got, want)
+			}
+		})
+	}
+}
+
+// nestedMultipart returns a message with multipart/mixed parts nested
+// to the given depth around a single text part.
+func nestedMultipart(depth int) string {
+	var b strings.Builder
+	b.WriteString("From: someone@example.com\r\nSubject: nested\r\n")
+	for i := range depth {
+		fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=\"b%d\"\r\n\r\n--b%d\r\n", i, i)
+	}
+	b.WriteString("Content-Type: text/plain\r\n\r\nDeep.\r\n")
+	for i := depth - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "--b%d--\r\n", i)
+	}
+	return b.String()
+}
+
+func TestOptMaxPartDepth(t *testing.T) {
+
+	tests := []struct {
+		depth int
+		opts  []Opt
+		isErr bool
+	}{
+		{1, nil, false},
+		{50, nil, false},
+		{51, nil, true},
+		{5, []Opt{WithMaxPartDepth(5)}, false},
+		{6, []Opt{WithMaxPartDepth(5)}, true},
+		{200, []Opt{WithMaxPartDepth(0)}, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			msg := nestedMultipart(tt.depth)
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if got, want := errors.Is(err, ErrMaxPartDepthExceeded), tt.isErr; got != want {
+				t.Fatalf("got part depth error %t want %t (%v)", got, want, err)
+			}
+			if tt.isErr {
+				if got, want := err.Error(), fmt.Sprintf("depth %d exceeds", tt.depth); !strings.Contains(got, want) {
+					t.Errorf("error %q does not contain %q", got, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, "Deep."; got != want {
+				t.Errorf("got text %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOptMaxPartDepthStructureLeaves(t *testing.T) {
+
+	p := NewParser(WithMaxPartDepth(3))
+	if _, err := p.ParseStructure(strings.NewReader(nestedMultipart(3))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.ParseStructure(strings.NewReader(nestedMultipart(4))); !errors.Is(err, ErrMaxPartDepthExceeded) {
+		t.Errorf("structure got error %v want %v", err, ErrMaxPartDepthExceeded)
+	}
+	if _, err := p.ParseLeaves(strings.NewReader(nestedMultipart(3))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.ParseLeaves(strings.NewReader(nestedMultipart(4))); !errors.Is(err, ErrMaxPartDepthExceeded) {
+		t.Errorf("leaves got error %v want %v", err, ErrMaxPartDepthExceeded)
+	}
+}
+
+func TestOptMaxAttachmentSize(t *testing.T) {
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	msg := "Subject: attachment\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nSee attached.\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n" +
+		"Content-Disposition: attachment; filename=\"digits.bin\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n\r\n" +
+		base64.StdEncoding.EncodeToString(data) + "\r\n--b--\r\n"
+
+	// streamed counts the bytes the custom file func receives
+	streamed := 0
+	streamFunc := func(f *email.File) error {
+		n, err := io.Copy(io.Discard, f.Reader)
+		streamed = int(n)
+		return err
+	}
+
+	tests := []struct {
+		opts     []Opt
+		isErr    bool
+		fileErr  bool
+		streamed int
+	}{
+		{nil, false, false, 0},
+		{[]Opt{WithMaxAttachmentSize(1000)}, false, false, 0},
+		{[]Opt{WithMaxAttachmentSize(999)}, true, false, 0},
+		{[]Opt{WithMaxAttachmentSize(999), WithLenient()}, false, true, 0},
+		{[]Opt{WithMaxAttachmentSize(100), WithCustomFileFunc(streamFunc)}, true, false, 100},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			streamed = 0
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if got, want := errors.Is(err, ErrAttachmentTooLarge), tt.isErr; got != want {
+				t.Fatalf("got attachment too large error %t want %t (%v)", got, want, err)
+			}
+			if got, want := streamed, tt.streamed; got != want {
+				t.Errorf("got %d bytes streamed want %d", got, want)
+			}
+			if tt.isErr {
+				if !strings.Contains(err.Error(), `"digits.bin"`) {
+					t.Errorf("error %q does not name the file", err)
+				}
+				return
+			}
+			if got, want := len(em.Files), 1; got != want {
+				t.Fatalf("got %d files want %d", got, want)
+			}
+			file := em.Files[0]
+			if got, want := errors.Is(file.DecodeError, ErrAttachmentTooLarge), tt.fileErr; got != want {
+				t.Errorf("got file error %t want %t (%v)", got, want, file.DecodeError)
+			}
+			if !tt.fileErr && !bytes.Equal(file.Data, data) {
+				t.Errorf("file data does not match")
+			}
+		})
+	}
+}
+
+func TestOptMaxPartSize(t *testing.T) {
+
+	// the quoted-printable soft line breaks decode to a single line
+	text := strings.Repeat(strings.Repeat("z", 70)+"=\r\n", 15)
+	msg := "Subject: parts\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n" +
+		text + "\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n" +
+		"Content-Disposition: attachment; filename=\"short.bin\"\r\n\r\n" +
+		"short\r\n--b--\r\n"
+
+	tests := []struct {
+		opts   []Opt
+		isErr  bool
+		text   int
+		errors int
+	}{
+		{nil, false, 1050, 0},
+		{[]Opt{WithMaxPartSize(1050)}, false, 1050, 0},
+		{[]Opt{WithMaxPartSize(1049)}, true, 0, 0},
+		{[]Opt{WithMaxPartSize(1049), WithLenient()}, false, 0, 1},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if got, want := errors.Is(err, ErrPartTooLarge), tt.isErr; got != want {
+				t.Fatalf("got part too large error %t want %t (%v)", got, want, err)
+			}
+			if tt.isErr {
+				var sizeErr *SizeLimitExceededError
+				if !errors.As(err, &sizeErr) {
+					t.Fatalf("error %v is not a SizeLimitExceededError", err)
+				}
+				if got, want := sizeErr.Limit, int64(1049); got != want {
+					t.Errorf("got limit %d want %d", got, want)
+				}
+				if got, want := sizeErr.What, `part of type "text/plain"`; got != want {
+					t.Errorf("got what %q want %q", got, want)
+				}
+				if errors.Is(err, ErrMessageTooLarge) {
+					t.Errorf("part error %v matches ErrMessageTooLarge", err)
+				}
+				return
+			}
+			if got, want := len(em.Text), tt.text; got != want {
+				t.Errorf("got text length %d want %d", got, want)
+			}
+			if got, want := len(em.Errors), tt.errors; got != want {
+				t.Errorf("got %d errors want %d: %v", got, want, em.Errors)
+			}
+			// the small file is unaffected
+			if got, want := len(em.Files), 1; got != want {
+				t.Fatalf("got %d files want %d", got, want)
+			}
+			if got, want := string(em.Files[0].Data), "short"; got != want {
+				t.Errorf("got file data %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOptLazyFiles(t *testing.T) {
+
+	large := bytes.Repeat([]byte("0123456789"), 10)
+	attachment := func(name string, data []byte) string {
+		return "--b\r\nContent-Type: application/octet-stream\r\n" +
+			"Content-Disposition: attachment; filename=\"" + name + "\"\r\n" +
+			"Content-Transfer-Encoding: base64\r\n\r\n" +
+			base64.StdEncoding.EncodeToString(data) + "\r\n"
+	}
+	msg := "Subject: lazy\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nSee attached.\r\n" +
+		attachment("small.bin", []byte("small")) +
+		attachment("large.bin", large) +
+		"--b--\r\n"
+
+	dir := t.TempDir()
+	spooled := func() int {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries)
+	}
+
+	em, err := NewParser(WithLazyFiles(10, dir)).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Files), 2; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	if got, want := spooled(), 1; got != want {
+		t.Errorf("got %d spooled files want %d", got, want)
+	}
+	for _, tt := range []struct {
+		file    *email.File
+		data    []byte
+		spooled bool
+	}{
+		{em.Files[0], []byte("small"), false},
+		{em.Files[1], large, true},
+	} {
+		f := tt.file
+		if f.Data != nil || f.Lazy == nil {
+			t.Fatalf("%s: expected lazy data only", f.Name)
+		}
+		if got, want := f.Lazy.Spooled(), tt.spooled; got != want {
+			t.Errorf("%s: got spooled %t want %t", f.Name, got, want)
+		}
+		if got, want := f.Lazy.Size(), int64(len(tt.data)); got != want {
+			t.Errorf("%s: got size %d want %d", f.Name, got, want)
+		}
+		// the content may be read more than once
+		for range 2 {
+			data, err := f.Lazy.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Errorf("%s: got data %q want %q", f.Name, data, tt.data)
+			}
+		}
+	}
+
+	// closing the email removes the spooled files
+	if err := em.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := spooled(), 0; got != want {
+		t.Errorf("got %d spooled files after close want %d", got, want)
+	}
+	if _, err := em.Files[1].Lazy.Open(); !errors.Is(err, email.ErrLazyDataClosed) {
+		t.Errorf("got error %v want %v", err, email.ErrLazyDataClosed)
+	}
+
+	// the spooled files of an email which fails to parse are removed
+	broken := strings.Replace(msg, "--b--", "--b\r\nContent-Type: application/octet-stream\r\n"+
+		"Content-Transfer-Encoding: base64\r\n\r\n!!!!\r\n--b--", 1)
+	if _, err := NewParser(WithLazyFiles(10, dir)).Parse(strings.NewReader(broken)); err == nil {
+		t.Fatal("expected error parsing corrupt attachment")
+	}
+	if got, want := spooled(), 0; got != want {
+		t.Errorf("got %d spooled files after failure want %d", got, want)
+	}
+
+	// as are those of an email which fails after it has been parsed,
+	// through a short read or failure reading the remainder of the raw
+	// message
+	if _, err := NewParser(WithLazyFiles(10, dir)).ParseN(strings.NewReader(msg), int64(len(msg)+10)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got error %v want %v", err, io.ErrUnexpectedEOF)
+	}
+	if got, want := spooled(), 0; got != want {
+		t.Errorf("got %d spooled files after short read want %d", got, want)
+	}
+	failing := io.MultiReader(strings.NewReader(msg+"epilogue\r\n"), iotest.ErrReader(errors.New("read failure")))
+	if _, err := NewParser(WithLazyFiles(10, dir), WithRetainRaw()).Parse(failing); err == nil {
+		t.Fatal("expected error retaining raw message")
+	}
+	if got, want := spooled(), 0; got != want {
+		t.Errorf("got %d spooled files after raw message failure want %d", got, want)
+	}
+
+	// as is that of an enclosed message which exceeds the part depth
+	f, err := os.Open("testdata/forward_nested.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := NewParser(WithLazyFiles(0, dir), WithMaxPartDepth(3)).Parse(f); !errors.Is(err, ErrMaxPartDepthExceeded) {
+		t.Errorf("got error %v want %v", err, ErrMaxPartDepthExceeded)
+	}
+	if got, want := spooled(), 0; got != want {
+		t.Errorf("got %d spooled files after part depth failure want %d", got, want)
+	}
+}
+
+func TestOptMaxMessageSize(t *testing.T) {
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	msg := "Subject: attachment\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nSee attached.\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n" +
+		"Content-Disposition: attachment; filename=\"digits.bin\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n\r\n" +
+		base64.StdEncoding.EncodeToString(data) + "\r\n--b--\r\n"
+	size := int64(len(msg))
+
+	tests := []struct {
+		opts  []Opt
+		isErr bool
+	}{
+		{nil, false},
+		{[]Opt{WithMaxMessageSize(size)}, false},
+		{[]Opt{WithMaxMessageSize(size), WithRetainRaw()}, false},
+		{[]Opt{WithMaxMessageSize(size - 1)}, true},
+		{[]Opt{WithMaxMessageSize(size - 1), WithLenient()}, true},
+		{[]Opt{WithMaxMessageSize(10)}, true}, // within the headers
+		{[]Opt{WithMaxMessageSize(200), WithMaxAttachmentSize(999)}, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if got, want := errors.Is(err, ErrMessageTooLarge), tt.isErr; got != want {
+				t.Fatalf("got message too large error %t want %t (%v)", got, want, err)
+			}
+			if tt.isErr {
+				if errors.Is(err, ErrAttachmentTooLarge) {
+					t.Errorf("unexpected attachment too large error %v", err)
+				}
+				return
+			}
+			if got, want := len(em.Files), 1; got != want {
+				t.Fatalf("got %d files want %d", got, want)
+			}
+			if !bytes.Equal(em.Files[0].Data, data) {
+				t.Errorf("file data does not match")
+			}
+		})
+	}
+}
+
+func TestOptLenientHeaders(t *testing.T) {
+
+	msg := "From: Alice <alice@example.com>\r\n" +
+		"To: Bob <bob@example.net>, broken@@example.net, Carol <carol@example.org>\r\n" +
+		"Cc: <unterminated@example.org\r\n" +
+		"Date: the day before yesterday\r\n" +
+		"Subject: =?x-unknown?Q?caf=E9?=\r\n" +
+		"Message-ID: <lenient-1@example.com>\r\n" +
+		"\r\nBody.\r\n"
+
+	// without a charset fallback the subject fails to decode
+	if _, err := NewParser(WithCharsetFallback("")).Parse(strings.NewReader(msg)); err == nil {
+		t.Fatal("expected error parsing without WithLenient")
+	}
+
+	em, err := NewParser(WithLenient(), WithCharsetFallback("")).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := em.Headers
+	to := []string{}
+	for _, a := range h.To {
+		to = append(to, a.Address)
+	}
+	if diff := cmp.Diff([]string{"bob@example.net", "carol@example.org"}, to); diff != "" {
+		t.Errorf("unexpected salvaged addresses (-want +got):\n%s", diff)
+	}
+	if got, want := len(h.Cc), 0; got != want {
+		t.Errorf("got %d cc want %d", got, want)
+	}
+	if !h.Date.IsZero() {
+		t.Errorf("expected zero date, got %s", h.Date)
+	}
+	if got, want := h.Subject, "=?x-unknown?Q?caf=E9?="; got != want {
+		t.Errorf("got subject %q want %q", got, want)
+	}
+	if got, want := h.From[0].Address, "alice@example.com"; got != want {
+		t.Errorf("got from %q want %q", got, want)
+	}
+	if got, want := h.MessageID, "lenient-1@example.com"; got != want {
+		t.Errorf("got message id %q want %q", got, want)
+	}
+	if got, want := em.Text, "Body."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	// to, cc, date and subject
+	if got, want := len(em.Errors), 4; got != want {
+		t.Errorf("got %d errors want %d: %v", got, want, em.Errors)
+	}
+	if got, want := len(em.Warnings), 4; got != want {
+		t.Errorf("got %d warnings want %d: %v", got, want, em.Warnings)
+	}
+}
+
+func TestOptSkipUnknownContentTypes(t *testing.T) {
+
+	msg := "Subject: unknown\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"mixed\"\r\n\r\n" +
+		"--mixed\r\nContent-Type: text/plain\r\n\r\nPlain.\r\n" +
+		"--mixed\r\nContent-Type: text/markdown; name=\"notes.md\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n\r\n" +
+		base64.StdEncoding.EncodeToString([]byte("# Café")) + "\r\n" +
+		"--mixed\r\nContent-Type: application/pdf; name=\"report.pdf\"\r\n\r\n" +
+		"%PDF-1.4\r\n" +
+		"--mixed--\r\n"
+
+	tests := []struct {
+		opts  []Opt
+		data  string
+		isErr bool
+	}{
+		{
+			opts:  []Opt{},
+			isErr: true,
+		},
+		{
+			opts: []Opt{WithSkipUnknownContentTypes()},
+			data: base64.StdEncoding.EncodeToString([]byte("# Café")),
+		},
+		{
+			opts: []Opt{WithSkipUnknownContentTypes(), WithoutAttachments()},
+			data: "",
+		},
+		{
+			opts:  []Opt{WithSkipUnknownContentTypes(), WithMaxAttachmentSize(10)},
+			isErr: true, // the raw markdown part is 12 bytes
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if tt.isErr {
+				var unknown *UnknownContentTypeError
+				if !errors.As(err, &unknown) && !errors.Is(err, ErrAttachmentTooLarge) {
+					t.Fatalf("got error %v want unknown content type or too large error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, "Plain."; got != want {
+				t.Errorf("got text %q want %q", got, want)
+			}
+			if got, want := len(em.UnhandledParts), 1; got != want {
+				t.Fatalf("got %d want %d unhandled parts", got, want)
+			}
+			part := em.UnhandledParts[0]
+			if got, want := part.Name, "notes.md"; got != want {
+				t.Errorf("got name %q want %q", got, want)
+			}
+			if got, want := part.ContentInfo.Type, "text/markdown"; got != want {
+				t.Errorf("got type %q want %q", got, want)
+			}
+			if got, want := string(part.Data), tt.data; got != want {
+				t.Errorf("got data %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOptContentTypeHandler(t *testing.T) {
+
+	msg := "Subject: handlers\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"mixed\"\r\n\r\n" +
+		"--mixed\r\nContent-Type: multipart/alternative; boundary=\"alt\"\r\n\r\n" +
+		"--alt\r\nContent-Type: text/plain\r\n\r\nPlain.\r\n" +
+		"--alt\r\nContent-Type: text/markdown; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: base64\r\n\r\n" +
+		base64.StdEncoding.EncodeToString([]byte("# Café")) + "\r\n" +
+		"--alt--\r\n" +
+		"--mixed\r\nContent-Type: application/pgp-signature; name=\"signature.asc\"\r\n\r\n" +
+		"-----BEGIN PGP SIGNATURE-----\r\n" +
+		"--mixed\r\nContent-Type: application/pdf; name=\"report.pdf\"\r\n\r\n" +
+		"%PDF-1.4\r\n" +
+		"--mixed--\r\n"
+
+	// without a handler the markdown part is unknown
+	_, err := NewParser().Parse(strings.NewReader(msg))
+	var unknown *UnknownContentTypeError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("got error %v want unknown content type error", err)
+	}
+
+	handled := map[string]string{}
+	record := func(name string) func(io.Reader, *email.ContentInfo) error {
+		return func(r io.Reader, ci *email.ContentInfo) error {
+			b, err := io.ReadAll(decoders.DecodeContent(r, ci))
+			handled[name+" "+ci.Type] = string(b)
+			return err
+		}
+	}
+	em, err := NewParser(
+		WithContentTypeHandler("Text/Markdown", record("markdown")),
+		WithContentTypeHandler("application/*", record("application")),
+		WithContentTypeHandler("application/pgp-signature", record("signature")),
+	).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"markdown text/markdown":              "# Café",
+		"signature application/pgp-signature": "-----BEGIN PGP SIGNATURE-----",
+		"application application/pdf":         "%PDF-1.4",
+	}
+	if diff := cmp.Diff(want, handled); diff != "" {
+		t.Errorf("unexpected handled parts (-want +got):\n%s", diff)
+	}
+	if got, want := em.Text, "Plain."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := len(em.Files), 0; got != want {
+		t.Errorf("got %d files want %d", got, want)
+	}
+
+	// handler errors abort the parse unless lenient
+	fail := WithContentTypeHandler("text/markdown", func(io.Reader, *email.ContentInfo) error {
+		return errors.New("no markdown please")
+	})
+	if _, err := NewParser(fail).Parse(strings.NewReader(msg)); err == nil {
+		t.Error("expected handler error")
+	}
+	em, err = NewParser(fail, WithLenient()).Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Errors), 1; got != want {
+		t.Errorf("got %d errors want %d", got, want)
+	}
+}
@@ -4,6 +4,9 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/mail"
@@ -11,6 +14,8 @@ import (
 	"time"
 
 	"github.com/rorycl/letters/email"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
 )
 
 // UnknownContentTypeError reports an unknown Content Type
@@ -24,14 +29,15 @@ func (e *UnknownContentTypeError) Error() string {
 
 // typeOfProcessing determines the type of processing to be done by the
 // Parser. If processing many emails it will be much more efficient to
-// use the `noAttachments` or `headersOnly` processing types if the
-// whole email isn't needed.
+// use the `noAttachments`, `textOnly` or `headersOnly` processing types
+// if the whole email isn't needed.
 type typeOfProcessing string
 
 const (
 	wholeEmail    typeOfProcessing = "wholeEmail"
 	headersOnly   typeOfProcessing = "headersOnly"
 	noAttachments typeOfProcessing = "noAttachments"
+	textOnly      typeOfProcessing = "textOnly"
 )
 
 // Opt is a parser option type provided as a closure to add options to a
@@ -46,14 +52,114 @@ type Opt func(p *Parser)
 // aren't to be used.
 //
 // The default address and date parsers are provided by net/mail
-// (mail.ParseAddress and mail.ParseAddressList, mail.ParseDate) while
+// (mail.ParseAddress and mail.ParseAddressList, mail.ParseDate), with
+// address groups lacking a terminating semicolon supported, while
 // the default attachment func is to simply ready each attachment into
 // the slice of email.File.Data.
+//
+// A Parser is not modified by parsing, as the state of each parse is
+// held separately, so a single Parser may be used to parse many emails,
+// including from multiple goroutines concurrently. Custom funcs
+// provided by Opt closures, such as file funcs and content type
+// handlers, must themselves be safe for concurrent use if the Parser is
+// shared in this way.
 type Parser struct {
 	// what parts of the email to process (default all)
 	processType typeOfProcessing
 	// skipContentTypes is a list of content types to skip
 	skipContentTypes []string
+	// skipUnknownContentTypes determines if parts of an unknown content
+	// type are recorded and skipped rather than failing the parse
+	skipUnknownContentTypes bool
+	// partInspector, if set, is called with the content info of each
+	// part to determine if it is skipped
+	partInspector func(*email.ContentInfo) (skip bool, err error)
+	// contentTypeHandlers are custom handlers for parts, keyed by base
+	// media type or wildcard such as "application/*"
+	contentTypeHandlers map[string]func(io.Reader, *email.ContentInfo) error
+	// sniffTransferEncoding determines if content transfer encodings
+	// are sniffed to correct those misdeclared by senders
+	sniffTransferEncoding bool
+	// sniffIdentityEncodings determines if content declared with an
+	// identity (or no) transfer encoding is sniffed for base64 and
+	// quoted-printable encoding
+	sniffIdentityEncodings bool
+	// encodingResolver, if set, resolves the character encoding of
+	// content before the built-in charset lookup
+	encodingResolver func(ci *email.ContentInfo) encoding.Encoding
+	// charsetFallback is the encoding used for content and headers
+	// declaring a charset which cannot be found (nil to leave them
+	// undecoded)
+	charsetFallback encoding.Encoding
+	// firstTextIsBody determines if only the first text part of each
+	// type is used for the email body, with subsequent text parts of
+	// the same type treated as files
+	firstTextIsBody bool
+	// orderedParts determines if body parts and files are recorded in
+	// document order
+	orderedParts bool
+	// defaultDisposition is the disposition applied to files lacking
+	// a Content-Disposition
+	defaultDisposition string
+	// inferContentType determines if the media type of generic
+	// application/octet-stream files is inferred from their name
+	inferContentType bool
+	// fileChecksums determines if the SHA-256 checksums of files are
+	// computed as they are read
+	fileChecksums bool
+	// detectMagicBytes determines if the media type of files is
+	// sniffed from their content
+	detectMagicBytes bool
+	// lenient determines if recoverable parsing problems are recorded
+	// as errors of the email rather than aborting the parse
+	lenient bool
+	// retainRaw determines if the raw message is retained
+	retainRaw bool
+	// lazyAddresses determines if address lists are parsed on first
+	// access rather than during parsing
+	lazyAddresses bool
+	// collapseHeaderWhitespace determines if runs of whitespace in
+	// decoded header text and display names are collapsed
+	collapseHeaderWhitespace bool
+	// dropEmptyTextParts determines if text parts which are empty or
+	// only whitespace once decoded are dropped
+	dropEmptyTextParts bool
+	// htmlToText determines if a plain text body is derived from the
+	// html body of messages lacking one
+	htmlToText bool
+	// detectInlinePGP determines if plain text bodies are scanned for
+	// inline OpenPGP armored blocks
+	detectInlinePGP bool
+	// rawSubject determines if the Subject is retained as it appears on
+	// the wire
+	rawSubject bool
+	// skipResentHeaders determines if the Resent-* headers are skipped
+	skipResentHeaders bool
+	// addressComments determines if comments in the From header are
+	// captured
+	addressComments bool
+	// maxPartDepth is the maximum nesting depth of multipart parts (0
+	// is unbounded)
+	maxPartDepth int
+	// maxMessageDepth is the maximum nesting depth of enclosed messages
+	// parsed into SubMessages (0 is unbounded)
+	maxMessageDepth int
+	// maxHeaders is the maximum number of header lines permitted (0
+	// is unbounded)
+	maxHeaders int
+	// maxLineLength is the maximum length of a line (0 is unbounded)
+	maxLineLength int
+	// maxAttachmentSize is the maximum decoded size of a file in bytes
+	// (0 is unbounded)
+	maxAttachmentSize int64
+	// maxPartSize is the maximum decoded size of a part in bytes (0 is
+	// unbounded)
+	maxPartSize int64
+	// maxMessageSize is the maximum size of a message in bytes (0 is
+	// unbounded)
+	maxMessageSize int64
+	// timeout is the maximum duration of a parse (0 is unbounded)
+	timeout time.Duration
 
 	// funcs that can be overridden by the user; defaults are set
 	// attached by NewParser.
@@ -64,8 +170,21 @@ type Parser struct {
 	addressesFunc func(list string) ([]*mail.Address, error)
 	// dateFunc : the function for processing the email header Date
 	dateFunc func(string) (time.Time, error)
+	// defaultTimezone : the location used to interpret dates without a
+	// timezone which cannot be parsed by dateFunc
+	defaultTimezone *time.Location
 	// fileFunc : a function for processing inline and attached files
 	fileFunc func(*email.File) error
+	// fileFuncRetains : if the fileFunc retains the content of files in
+	// email.File.Data or email.File.Lazy, from which enclosed messages
+	// are parsed
+	fileFuncRetains bool
+	// headerCallback : an optional func called with the name and
+	// decoded value of each header
+	headerCallback func(name, decodedValue string)
+	// boundaryFunc : an optional func for determining the boundary of
+	// a multipart part from its content info and a peek at its content
+	boundaryFunc func(ci *email.ContentInfo, peek []byte) string
 
 	// debugging, for future use
 	verbose bool
@@ -77,12 +196,17 @@ func NewParser(options ...Opt) *Parser {
 	p := &Parser{
 		// initialise main fields
 		processType: wholeEmail,
+		// guard against maliciously deep multipart nesting
+		maxPartDepth: defaultMaxPartDepth,
+		// decode mislabelled content as windows-1252, the pragmatic
+		// choice for western email
+		charsetFallback: charmap.Windows1252,
 
 		// initialise overrideable funcs
-		// use net/mail.ParseAddress and ParseAddressList  as default
-		// address parsers
+		// use net/mail.ParseAddress and ParseAddressList, supporting
+		// unterminated groups, as default address parsers
 		addressFunc:   mail.ParseAddress,
-		addressesFunc: mail.ParseAddressList,
+		addressesFunc: groupParseAddressList,
 		// use net/mail.ParseDate as the default date parser
 		dateFunc: mail.ParseDate,
 		// by default write file io.Readers to email.File.Data.
@@ -93,6 +217,7 @@ func NewParser(options ...Opt) *Parser {
 			f.Data, err = io.ReadAll(f.Reader)
 			return err
 		},
+		fileFuncRetains: true,
 
 		// debugging
 		verbose: false,
@@ -106,14 +231,146 @@ func NewParser(options ...Opt) *Parser {
 
 // Parse is the main entry point of letters.
 func (p *Parser) Parse(r io.Reader) (*email.Email, error) {
-	var err error
+	return p.ParseContext(context.Background(), r)
+}
+
+// ParseContext parses an email like Parse, aborting with the context's
+// error if the context is cancelled or its deadline is exceeded before
+// parsing completes. Cancellation is checked after the headers are
+// read, between multipart parts and before each file is read, as well
+// as by each read from r; a read which is blocked, or processing by a
+// custom file func, is not interrupted. If a timeout has been set with
+// WithTimeout, a deadline is derived from the context and ErrTimeout
+// returned if it is exceeded.
+func (p *Parser) ParseContext(ctx context.Context, r io.Reader) (*email.Email, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	e, err := p.parse(ctx, &contextReader{ctx: ctx, r: r})
+	if err == nil {
+		if err = ctx.Err(); err != nil {
+			_ = e.Close()
+		}
+	}
+	if err != nil {
+		if p.timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s: %w", ErrTimeout, p.timeout, err)
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// ParseN parses a message of exactly n bytes from r, such as an IMAP
+// literal of known length, without first copying it into a separate
+// buffer. Reading is limited to n bytes and any of the n bytes not
+// consumed by parsing are discarded, leaving r positioned after the
+// message. If fewer than n bytes are available the message is
+// truncated, and an error wrapping io.ErrUnexpectedEOF is returned.
+func (p *Parser) ParseN(r io.Reader, n int64) (*email.Email, error) {
+	lr := &io.LimitedReader{R: r, N: n}
+	e, err := p.Parse(lr)
+	if _, cerr := io.Copy(io.Discard, lr); cerr != nil && err == nil {
+		err = fmt.Errorf("cannot read message: %w", cerr)
+	}
+	if err == nil && lr.N > 0 {
+		err = fmt.Errorf("%w: message of %d bytes ended after %d bytes", io.ErrUnexpectedEOF, n, n-lr.N)
+	}
+	if err != nil {
+		// release the files of an email parsed before the failure
+		if e != nil {
+			_ = e.Close()
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// limitReader wraps r to enforce the parser's maximum message size and
+// line length, if set.
+func (p *Parser) limitReader(r io.Reader) io.Reader {
+	if p.maxMessageSize > 0 {
+		r = &sizeLimitReader{r: r, desc: "message", tooLarge: ErrMessageTooLarge, max: p.maxMessageSize}
+	}
+	if p.maxLineLength > 0 {
+		r = newLineLimitReader(r, p.maxLineLength)
+	}
+	return r
+}
+
+// limitHeaders wraps r to enforce the parser's maximum number of
+// headers, if set, as the header section of the message is read.
+func (p *Parser) limitHeaders(r io.Reader) io.Reader {
+	if p.maxHeaders > 0 {
+		r = newHeaderLimitReader(r, p.maxHeaders)
+	}
+	return r
+}
+
+// parse parses the email from r, limiting its size and the length of
+// its lines and retaining the raw message if required.
+func (p *Parser) parse(ctx context.Context, r io.Reader) (*email.Email, error) {
+	r = p.limitReader(r)
+	if !p.retainRaw {
+		return p.parseMessage(ctx, r, 0, 0)
+	}
+	raw := &bytes.Buffer{}
+	tee := io.TeeReader(r, raw)
+	e, err := p.parseMessage(ctx, tee, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	// read any remainder of the message not consumed by parsing, such
+	// as the body when parsing headers only
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		_ = e.Close()
+		return nil, fmt.Errorf("cannot retain raw message: %w", err)
+	}
+	e.RawMessage = raw.Bytes()
+	return e, nil
+}
+
+// parseMessage parses the email from r, checking ctx for cancellation
+// at part boundaries. The depth is the part nesting depth of an
+// enclosed message, or 0 for the outermost message, and is counted
+// towards the maximum part depth. The messageDepth is the number of
+// messages enclosing the message.
+func (p *Parser) parseMessage(ctx context.Context, r io.Reader, depth, messageDepth int) (_ *email.Email, err error) {
 	se := newStagedEmail(p)
+	defer func() {
+		// release any files spooled before a failure
+		if err != nil {
+			_ = se.email.Close()
+		}
+	}()
+	se.ctx = ctx
+	se.depth = depth
+	se.messageDepth = messageDepth
+	if err := se.checkPartDepth(depth); err != nil {
+		return nil, err
+	}
+
+	// count the headers as they are read, including by readRawHeaders
+	r = p.limitHeaders(r)
+
+	// read the raw header block, capturing the headers in their
+	// original order, the raw subject if required and any headers
+	// forged by bare line feeds
+	r, err = se.captureRawHeaders(r)
+	if err != nil {
+		return nil, err
+	}
 
 	// read the message into a *mail.Message
 	se.msg, err = mail.ReadMessage(r)
 	if err != nil {
 		return nil, fmt.Errorf("cannot read message: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// extract content information
 	se.contentInfo, err = email.ExtractContentInfo(se.msg.Header, nil)
@@ -145,17 +402,37 @@ func (p *Parser) Parse(r io.Reader) (*email.Email, error) {
 			se.msg.Body,
 			se.contentInfo,
 			se.contentInfo.TypeParams["boundary"],
+			depth+1,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+	case ct == "message/external-body":
+		// parse reference to externally stored content
+		if p.processType != wholeEmail {
+			break
+		}
+		err = se.parseExternalBody(se.msg.Body, se.contentInfo)
+		if err != nil {
+			return nil, err
+		}
+
 	default:
 		// parse attachment
+		if p.processType == textOnly {
+			break
+		}
+		se.applyDefaultDisposition(se.contentInfo)
 		err = se.parseFile(se.msg.Body, se.contentInfo)
 		if err != nil {
 			return nil, err
 		}
 	}
+
+	// derive a plain text body for messages with only an html body
+	if p.htmlToText && se.email.Text == "" && se.email.HTML != "" {
+		se.email.Text = email.HTMLToText(se.email.HTML)
+	}
 	return se.email, err
 }
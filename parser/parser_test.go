@@ -1,9 +1,23 @@
 package parser
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rorycl/letters/email"
 )
 
 func TestBasicParser(t *testing.T) {
@@ -53,3 +67,720 @@ func TestParseEnglishPlaintext(t *testing.T) {
 		t.Errorf("got %s want %s", got, want)
 	}
 }
+
+func TestParseNoContentType(t *testing.T) {
+
+	msg, err := os.Open("testdata/no_content_type.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewParser()
+	email, err := p.Parse(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := email.Headers.ContentInfo.Type, "text/plain"; got != want {
+		t.Errorf("got type %s want %s", got, want)
+	}
+	if got, want := len(email.Files), 0; got != want {
+		t.Errorf("got %d files want %d", got, want)
+	}
+	if got, want := email.Text, "A minimal message with neither a MIME-Version nor a Content-Type\nheader, which should be treated as plain text."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+}
+
+func TestParseDecodeStats(t *testing.T) {
+
+	msg := `From: someone@example.com
+Subject: Decode stats
+Content-Type: multipart/mixed; boundary="stats"
+
+--stats
+Content-Type: text/plain; charset="x-no-such-charset"
+
+Plain text in an unknown charset.
+--stats
+Content-Type: text/calendar; method=REQUEST
+
+BEGIN:VCALENDAR
+END:VCALENDAR
+--stats--
+`
+	p := NewParser()
+	email, err := p.Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := email.DecodeStats.CharsetFallbacks, 1; got != want {
+		t.Errorf("charset fallbacks got %d want %d", got, want)
+	}
+	// calendar parts are parsed as files rather than skipped
+	if got, want := email.DecodeStats.UnknownSkipped, 0; got != want {
+		t.Errorf("unknown skipped got %d want %d", got, want)
+	}
+	if got, want := email.DecodeStats.LenientRepairs, 0; got != want {
+		t.Errorf("lenient repairs got %d want %d", got, want)
+	}
+}
+
+func TestParseDecodeStatsUnknownSkipped(t *testing.T) {
+
+	msg := "Subject: Unknown parts\r\n" +
+		"Content-Type: multipart/alternative; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nHello\r\n" +
+		"--b\r\nContent-Type: text/x-made-up\r\n\r\n???\r\n" +
+		"--b\r\nContent-Type: text/x-also-made-up\r\n\r\n???\r\n" +
+		"--b--\r\n"
+
+	tests := []struct {
+		opts []Opt
+	}{
+		{[]Opt{WithSkipUnknownContentTypes()}},
+		{[]Opt{WithLenient()}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			em, err := NewParser(tt.opts...).Parse(strings.NewReader(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.DecodeStats.UnknownSkipped, 2; got != want {
+				t.Errorf("unknown skipped got %d want %d", got, want)
+			}
+			// skipping unknown parts is not a repair
+			if got, want := em.DecodeStats.LenientRepairs, 0; got != want {
+				t.Errorf("lenient repairs got %d want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestParseMultipartTransferEncoding(t *testing.T) {
+
+	f, err := os.Open("testdata/multipart_transfer_encoding.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Text, "Please find the scanned document attached."; got != want {
+		t.Errorf("text got %q want %q", got, want)
+	}
+	if got, want := len(em.Files), 1; got != want {
+		t.Fatalf("got %d want %d files", got, want)
+	}
+	if got, want := em.Files[0].Name, "scan.pdf"; got != want {
+		t.Errorf("file name got %s want %s", got, want)
+	}
+	if got, want := len(em.Warnings), 2; got != want {
+		t.Fatalf("got %d want %d warnings: %v", got, want, em.Warnings)
+	}
+	if got, want := em.Warnings[0].Error(), `ignored illegal Content-Transfer-Encoding "base64" on multipart/mixed`; got != want {
+		t.Errorf("warning got %q want %q", got, want)
+	}
+}
+
+func TestParseAutoSubmitted(t *testing.T) {
+	tests := []struct {
+		file          string
+		autoSubmitted string
+		isAuto        bool
+	}{
+		{"testdata/vacation.eml", "auto-replied", true},
+		{"testdata/bounce.eml", "auto-replied", true},
+		{"testdata/cats.eml", "", false},
+		{"../tests/test_english_plaintext_ascii_over_7bit.txt", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			email, err := NewParser().Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := email.Headers.AutoSubmitted, tt.autoSubmitted; got != want {
+				t.Errorf("auto submitted got %q want %q", got, want)
+			}
+			if got, want := email.IsAutoSubmitted(), tt.isAuto; got != want {
+				t.Errorf("is auto submitted got %t want %t", got, want)
+			}
+		})
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+
+	f, err := os.Open("testdata/digest.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(em.Text, "Today's Topics:") {
+		t.Errorf("unexpected text %q", em.Text)
+	}
+	if got, want := len(em.Files), 0; got != want {
+		t.Errorf("got %d files want %d", got, want)
+	}
+	if got, want := len(em.SubMessages), 2; got != want {
+		t.Fatalf("got %d sub messages want %d", got, want)
+	}
+
+	first, second := em.SubMessages[0], em.SubMessages[1]
+	if got, want := first.Headers.From[0].Name, "Alice"; got != want {
+		t.Errorf("got from %q want %q", got, want)
+	}
+	if got, want := first.Text, "How do I constrain a type parameter to numeric types?"; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := second.Headers.InReplyTo[0], "generics-1@example.com"; got != want {
+		t.Errorf("got in-reply-to %q want %q", got, want)
+	}
+	if got, want := second.HTML, "<p>Use a constraint interface with a type set.</p>"; got != want {
+		t.Errorf("got html %q want %q", got, want)
+	}
+}
+
+func TestParseMultilingual(t *testing.T) {
+
+	f, err := os.Open("testdata/multilingual.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []email.LanguageBody{
+		{Languages: []string{"en"}, Text: "Hello", HTML: "<p>Hello</p>"},
+		{Languages: []string{"fr"}, Text: "Bonjour", HTML: "<p>Bonjour</p>"},
+		{Languages: []string{"de-ch"}, Text: "Gruezi"},
+	}
+	if diff := cmp.Diff(want, em.LanguageBodies); diff != "" {
+		t.Error(diff)
+	}
+	if text, html := em.BodyForLanguage([]string{"it", "fr"}); text != "Bonjour" || html != "<p>Bonjour</p>" {
+		t.Errorf("got %q, %q", text, html)
+	}
+}
+
+func TestParseHebrewBidi(t *testing.T) {
+
+	f, err := os.Open("testdata/hebrew_bidi.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Headers.Subject, "שלום"; got != want {
+		t.Errorf("subject got %q want %q", got, want)
+	}
+	if got, want := em.Text, "שלום עולם"; got != want {
+		t.Errorf("text got %q want %q", got, want)
+	}
+	if got, want := em.HTML, `<p dir="rtl">שלום עולם</p>`; got != want {
+		t.Errorf("html got %q want %q", got, want)
+	}
+	if got, want := em.DecodeStats.CharsetFallbacks, 0; got != want {
+		t.Errorf("charset fallbacks got %d want %d", got, want)
+	}
+}
+
+func TestParseMultiCharset(t *testing.T) {
+
+	f, err := os.Open("testdata/multi_charset.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the text part inherits tis-620 from its enclosing part rather
+	// than utf-8 from the message
+	if got, want := em.Text, "สวัสดีครับ"; got != want {
+		t.Errorf("text got %q want %q", got, want)
+	}
+	if got, want := em.HTML, "<p>สวัสดีครับ</p>"; got != want {
+		t.Errorf("html got %q want %q", got, want)
+	}
+	if got, want := len(em.Files), 1; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	if got, want := em.Files[0].ContentInfo.Charset, "iso-8859-1"; got != want {
+		t.Errorf("file charset got %q want %q", got, want)
+	}
+	if got, want := em.DecodeStats.CharsetFallbacks, 0; got != want {
+		t.Errorf("charset fallbacks got %d want %d", got, want)
+	}
+}
+
+func TestParseInheritedCharsetImage(t *testing.T) {
+
+	// an image in a multipart declaring a charset is not decoded as
+	// text in that charset, which would corrupt its bytes
+	msg := "MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"; charset=utf-8\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		"A pixel.\r\n" +
+		"--b\r\n" +
+		"Content-Type: image/gif; name=\"pixel.gif\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"R0lGODlhAQABAIAAAP///wAAACH5BAEAAAAALAAAAAABAAEAAAICRAEAOw==\r\n" +
+		"--b--\r\n"
+
+	em, err := NewParser().Parse(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Files), 1; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	want, _ := base64.StdEncoding.DecodeString("R0lGODlhAQABAIAAAP///wAAACH5BAEAAAAALAAAAAABAAEAAAICRAEAOw==")
+	if got := em.Files[0].Data; !bytes.Equal(got, want) {
+		t.Errorf("got data %x want %x", got, want)
+	}
+	if got, want := em.Files[0].ContentInfo.Charset, ""; got != want {
+		t.Errorf("got charset %q want %q", got, want)
+	}
+}
+
+func TestParseReplyForward(t *testing.T) {
+
+	tests := []struct {
+		file    string
+		reply   bool
+		forward bool
+	}{
+		{"testdata/new_message.eml", false, false},
+		{"testdata/reply.eml", true, false},
+		{"testdata/forward.eml", false, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			em, err := NewParser().Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.IsReply(), tt.reply; got != want {
+				t.Errorf("reply got %t want %t", got, want)
+			}
+			if got, want := em.IsForward(), tt.forward; got != want {
+				t.Errorf("forward got %t want %t", got, want)
+			}
+		})
+	}
+}
+
+func TestParseN(t *testing.T) {
+
+	msg := "Subject: literal\r\n\r\nLiteral body.\r\n"
+	trailer := ")\r\nA002 OK FETCH completed\r\n"
+
+	tests := []struct {
+		n     int64
+		isErr bool
+		text  string
+		rest  string
+	}{
+		{ // exact literal
+			n:    int64(len(msg)),
+			text: "Literal body.",
+			rest: trailer,
+		},
+		{ // shorter literal truncates the body
+			n:    int64(len(msg) - 6),
+			text: "Literal b",
+			rest: "ody.\r\n" + trailer,
+		},
+		{ // fewer bytes available than the literal length
+			n:     int64(len(msg) + len(trailer) + 10),
+			isErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(msg + trailer))
+			em, err := NewParser(WithHeadersOnly()).ParseN(r, tt.n)
+			if tt.isErr {
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Fatalf("got error %v want %v", err, io.ErrUnexpectedEOF)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Headers.Subject, "literal"; got != want {
+				t.Errorf("got subject %q want %q", got, want)
+			}
+			rest, _ := io.ReadAll(r)
+			if got, want := string(rest), tt.rest; got != want {
+				t.Errorf("got rest %q want %q", got, want)
+			}
+
+			// parse the body in full
+			r = bufio.NewReader(strings.NewReader(msg + trailer))
+			em, err = NewParser().ParseN(r, tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Text, tt.text; got != want {
+				t.Errorf("got text %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseEnclosedMessages(t *testing.T) {
+
+	parse := func(opts ...Opt) (*email.Email, error) {
+		f, err := os.Open("testdata/forward_nested.eml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		return NewParser(opts...).Parse(f)
+	}
+
+	em, err := parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// both enclosed messages are retained as files
+	if got, want := len(em.Files), 2; got != want {
+		t.Errorf("got %d files want %d", got, want)
+	}
+	// the malformed enclosed message is recorded as a warning
+	if got, want := len(em.Warnings), 1; got != want {
+		t.Errorf("got %d warnings want %d: %v", got, want, em.Warnings)
+	}
+	if got, want := len(em.SubMessages), 1; got != want {
+		t.Fatalf("got %d sub messages want %d", got, want)
+	}
+	fwd := em.SubMessages[0]
+	if got, want := fwd.Headers.MessageID, "forward-1@example.net"; got != want {
+		t.Errorf("got message id %q want %q", got, want)
+	}
+	if got, want := fwd.Headers.ExtraHeaders["X-Mailer"], []string{"Example Mail 1.0"}; !slices.Equal(got, want) {
+		t.Errorf("got x-mailer %q want %q", got, want)
+	}
+	if got, want := fwd.Text, "See Alice's message below."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := len(fwd.SubMessages), 1; got != want {
+		t.Fatalf("got %d nested sub messages want %d", got, want)
+	}
+	if got, want := fwd.SubMessages[0].Text, "Lunch on Friday?"; got != want {
+		t.Errorf("got nested text %q want %q", got, want)
+	}
+
+	// the nested messages count towards the part depth: the forward
+	// is at depth 2, its parts at depth 3 and the innermost message at
+	// depth 4
+	if _, err := parse(WithMaxPartDepth(4)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(WithMaxPartDepth(3)); !errors.Is(err, ErrMaxPartDepthExceeded) {
+		t.Errorf("got error %v want %v", err, ErrMaxPartDepthExceeded)
+	}
+
+	// enclosed messages are parsed with the same options
+	em, err = parse(WithSkipContentTypes([]string{"text/plain"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.SubMessages[0].Text, ""; got != want {
+		t.Errorf("got skipped text %q want %q", got, want)
+	}
+
+	// the message depth limits the parsing of enclosed messages without
+	// failing the parse
+	em, err = parse(WithMaxMessageDepth(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fwd = em.SubMessages[0]
+	if got, want := len(fwd.SubMessages), 0; got != want {
+		t.Errorf("got %d nested sub messages want %d", got, want)
+	}
+	if got, want := len(fwd.Files), 1; got != want {
+		t.Errorf("got %d nested files want %d", got, want)
+	}
+	if got, want := len(fwd.Warnings), 1; got != want {
+		t.Fatalf("got %d nested warnings want %d: %v", got, want, fwd.Warnings)
+	}
+	if !errors.Is(fwd.Warnings[0], ErrMaxMessageDepthExceeded) {
+		t.Errorf("got warning %v want %v", fwd.Warnings[0], ErrMaxMessageDepthExceeded)
+	}
+}
+
+func TestParseEnclosedMessagesStorage(t *testing.T) {
+
+	c, err := os.ReadFile("testdata/forward_nested.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	discard := WithCustomFileFunc(func(f *email.File) error {
+		_, err := io.Copy(io.Discard, f.Reader)
+		return err
+	})
+
+	// enclosed messages are parsed from the content retained by the
+	// file func or, if it retains none, from a spool
+	for i, opts := range [][]Opt{
+		nil,
+		{WithLazyFiles(10, t.TempDir())},
+		{discard},
+	} {
+		em, err := NewParser(opts...).Parse(bytes.NewReader(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(em.SubMessages), 1; got != want {
+			t.Fatalf("test %d: got %d sub messages want %d", i, got, want)
+		}
+		if got, want := em.SubMessages[0].Text, "See Alice's message below."; got != want {
+			t.Errorf("test %d: got text %q want %q", i, got, want)
+		}
+		if got, want := len(em.SubMessages[0].SubMessages), 1; got != want {
+			t.Errorf("test %d: got %d nested sub messages want %d", i, got, want)
+		}
+		_ = em.Close()
+	}
+
+	// a large enclosed message is spooled to a temporary file, which is
+	// removed once parsed
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	large := "Subject: forward\r\nContent-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: message/rfc822\r\n\r\n" +
+		"Subject: large\r\n\r\n" + strings.Repeat("0123456789\r\n", 1<<17) +
+		"--b--\r\n"
+	em, err := NewParser(discard).Parse(strings.NewReader(large))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.SubMessages), 1; got != want {
+		t.Fatalf("got %d sub messages want %d", got, want)
+	}
+	if got, want := em.SubMessages[0].TextLen(), 11*(1<<17)-1; got != want {
+		t.Errorf("got text length %d want %d", got, want)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(entries), 0; got != want {
+		t.Errorf("got %d spooled files want %d", got, want)
+	}
+}
+
+func TestParseDigestMessageDepth(t *testing.T) {
+
+	parse := func(opts ...Opt) (*email.Email, error) {
+		f, err := os.Open("testdata/forwarded_digest.eml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		return NewParser(opts...).Parse(f)
+	}
+
+	em, err := parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.SubMessages), 1; got != want {
+		t.Fatalf("got %d sub messages want %d", got, want)
+	}
+	if got, want := len(em.SubMessages[0].SubMessages), 2; got != want {
+		t.Errorf("got %d digest messages want %d", got, want)
+	}
+
+	// the messages of a digest nested too deeply are retained as files
+	em, err = parse(WithMaxMessageDepth(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.SubMessages), 1; got != want {
+		t.Fatalf("got %d sub messages want %d", got, want)
+	}
+	digest := em.SubMessages[0]
+	if got, want := len(digest.SubMessages), 0; got != want {
+		t.Errorf("got %d digest messages want %d", got, want)
+	}
+	if got, want := len(digest.Files), 2; got != want {
+		t.Fatalf("got %d digest files want %d", got, want)
+	}
+	for _, f := range digest.Files {
+		if got, want := f.FileType, "attachment"; got != want {
+			t.Errorf("got file type %q want %q", got, want)
+		}
+		if got, want := f.ContentInfo.Type, "message/rfc822"; got != want {
+			t.Errorf("got content type %q want %q", got, want)
+		}
+	}
+	if !bytes.Contains(digest.Files[0].Data, []byte("Subject: Generics question")) {
+		t.Errorf("unexpected digest file data %q", digest.Files[0].Data)
+	}
+	if got, want := len(digest.Warnings), 2; got != want {
+		t.Fatalf("got %d digest warnings want %d: %v", got, want, digest.Warnings)
+	}
+	for _, w := range digest.Warnings {
+		if !errors.Is(w, ErrMaxMessageDepthExceeded) {
+			t.Errorf("got warning %v want %v", w, ErrMaxMessageDepthExceeded)
+		}
+	}
+}
+
+func TestParseCalendar(t *testing.T) {
+
+	f, err := os.Open("testdata/calendar.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := em.Text, "You have been invited to Lunch on Friday."; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := len(em.Files), 1; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	cal := em.Files[0]
+	if got, want := cal.FileType, "calendar"; got != want {
+		t.Errorf("got file type %q want %q", got, want)
+	}
+	if got, want := cal.ContentInfo.TypeParams["method"], "REQUEST"; got != want {
+		t.Errorf("got method %q want %q", got, want)
+	}
+	if got, want := string(cal.Data), "SUMMARY:Lunch on Friday at the Café\n"; !strings.Contains(got, want) {
+		t.Errorf("calendar %q does not contain %q", got, want)
+	}
+	if got, want := len(em.Calendars), 1; got != want {
+		t.Fatalf("got %d calendars want %d", got, want)
+	}
+	if got, want := em.Calendars[0].Method, "REQUEST"; got != want {
+		t.Errorf("got calendar method %q want %q", got, want)
+	}
+	if got, want := len(em.Calendars[0].Events), 1; got != want {
+		t.Fatalf("got %d events want %d", got, want)
+	}
+	event := em.Calendars[0].Events[0]
+	if got, want := event.Summary, "Lunch on Friday at the Café"; got != want {
+		t.Errorf("got summary %q want %q", got, want)
+	}
+	if got, want := event.Start, time.Date(2025, 4, 11, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got start %v want %v", got, want)
+	}
+}
+
+func TestParseContextPartBoundaries(t *testing.T) {
+
+	msg := "Subject: files\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n\r\none\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n\r\ntwo\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n\r\nthree\r\n" +
+		"--b--\r\n"
+
+	// the small message is buffered by the multipart reader, so the
+	// cancellation is only noticed at a part boundary
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	files := 0
+	fileFunc := func(f *email.File) error {
+		files++
+		cancel()
+		_, err := io.ReadAll(f.Reader)
+		return err
+	}
+	p := NewParser(WithCustomFileFunc(fileFunc), WithLenient())
+	_, err := p.ParseContext(ctx, strings.NewReader(msg))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v want %v", err, context.Canceled)
+	}
+	if got, want := files, 1; got != want {
+		t.Errorf("got %d files read want %d", got, want)
+	}
+}
+
+// TestParseConcurrent parses the same email from many goroutines with
+// a shared parser. Run with -race to detect shared mutable state.
+func TestParseConcurrent(t *testing.T) {
+
+	raw, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewParser(WithFileChecksums(), WithOrderedParts(), WithDetectMagicBytes())
+	want, err := p.Parse(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines = 50
+	results := make([]*email.Email, goroutines)
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = p.Parse(bytes.NewReader(raw))
+		}()
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		if errs[i] != nil {
+			t.Fatalf("goroutine %d: %v", i, errs[i])
+		}
+		if diff := cmp.Diff(want, got,
+			cmpopts.IgnoreFields(email.File{}, "Reader"),
+			cmpopts.IgnoreFields(email.ContentInfo{}, "Encoding", "encDone"),
+			cmp.Comparer(bytes.Equal),
+		); diff != "" {
+			t.Fatalf("goroutine %d: emails are not equal\n%s", i, diff)
+		}
+	}
+}
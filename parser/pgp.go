@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// "pgp" provides the detection of inline OpenPGP ASCII armored blocks
+// (RFC 9580 section 6) in plain text bodies.
+
+const (
+	armorBegin      string = "-----BEGIN PGP "
+	armorEnd        string = "-----END PGP "
+	armorDashes     string = "-----"
+	armorSignedType string = "SIGNED MESSAGE"
+)
+
+// findInlinePGP returns the first armored block in the text, or nil if
+// there is none or it is unterminated. For clearsigned messages the
+// block extends to the end of the signature and the cleartext is
+// extracted.
+func findInlinePGP(text string) *email.InlinePGP {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	start, armorType := -1, ""
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if strings.HasPrefix(line, armorBegin) && strings.HasSuffix(line, armorDashes) {
+			start = i
+			armorType = strings.TrimSuffix(strings.TrimPrefix(line, armorBegin), armorDashes)
+			break
+		}
+	}
+	if start < 0 || armorType == "" {
+		return nil
+	}
+
+	// a clearsigned message is terminated by the end of its signature
+	endType := armorType
+	if armorType == armorSignedType {
+		endType = "SIGNATURE"
+	}
+	endLine := armorEnd + endType + armorDashes
+	end := -1
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t") == endLine {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil
+	}
+
+	pgp := &email.InlinePGP{
+		Type:  strings.ToLower(armorType),
+		Block: strings.Join(lines[start:end+1], "\n"),
+	}
+	if armorType == armorSignedType {
+		pgp.ClearText = clearSignedText(lines[start+1 : end])
+	}
+	return pgp
+}
+
+// clearSignedText extracts the dash-unescaped cleartext from the lines
+// of a clearsigned message following the BEGIN line, which commence
+// with armor headers such as "Hash:" followed by a blank line and
+// finish with the signature.
+func clearSignedText(lines []string) string {
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+		i++ // skip armor headers
+	}
+	text := []string{}
+	for _, line := range lines[min(i+1, len(lines)):] {
+		if strings.HasPrefix(strings.TrimRight(line, " \t"), armorBegin+"SIGNATURE"+armorDashes) {
+			break
+		}
+		text = append(text, strings.TrimPrefix(line, "- "))
+	}
+	return strings.Join(text, "\n")
+}
+
+// detectInlinePGP records the first inline armored block found in
+// plain text if the parser is set to detect inline PGP.
+func (se *stagedEmail) detectInlinePGP(text string) {
+	if !se.parser.detectInlinePGP || se.email.InlinePGP != nil {
+		return
+	}
+	se.email.InlinePGP = findInlinePGP(text)
+}
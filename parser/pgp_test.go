@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestFindInlinePGP(t *testing.T) {
+
+	encrypted := "-----BEGIN PGP MESSAGE-----\n\nhQEMA0example\n=wxyz\n-----END PGP MESSAGE-----"
+
+	tests := []struct {
+		text string
+		want *email.InlinePGP
+	}{
+		{"no armor here", nil},
+		{ // unterminated
+			"-----BEGIN PGP MESSAGE-----\n\nhQEMA0example\n", nil,
+		},
+		{
+			"Please find my reply below.\r\n\r\n" + encrypted + "\r\n\r\nSent from my PC",
+			&email.InlinePGP{Type: "message", Block: encrypted},
+		},
+		{
+			"-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmQENBexample\n-----END PGP PUBLIC KEY BLOCK-----",
+			&email.InlinePGP{
+				Type:  "public key block",
+				Block: "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmQENBexample\n-----END PGP PUBLIC KEY BLOCK-----",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, findInlinePGP(tt.text)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseInlinePGP(t *testing.T) {
+
+	for _, detect := range []bool{false, true} {
+		f, err := os.Open("testdata/pgp_clearsigned.eml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := []Opt{}
+		if detect {
+			opts = append(opts, WithDetectInlinePGP())
+		}
+		em, err := NewParser(opts...).Parse(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !detect {
+			if em.InlinePGP != nil {
+				t.Error("inline pgp should not be detected without option")
+			}
+			continue
+		}
+		pgp := em.InlinePGP
+		if pgp == nil {
+			t.Fatal("inline pgp not detected")
+		}
+		if got, want := pgp.Type, "signed message"; got != want {
+			t.Errorf("got type %q want %q", got, want)
+		}
+		if got, want := pgp.ClearText, "The release is available.\n-----BEGIN not really armor-----\nRegards\n"; got != want {
+			t.Errorf("got cleartext %q want %q", got, want)
+		}
+		if got, want := pgp.Block, em.Text; got != want {
+			t.Errorf("got block %q want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// priorityHeaders are the headers used by Outlook and other clients to
+// indicate the priority of a message, in canonical form and in their
+// order of precedence. Importance reflects the priority set in Outlook
+// and is the most reliable, X-MSMail-Priority is derived from it by
+// Outlook and the numeric X-Priority is the oldest and most widely
+// used, but is often left at its default by gateways.
+var priorityHeaders = []string{"Importance", "X-Msmail-Priority", "X-Priority"}
+
+// normalizePriority normalizes the value of a priority header to
+// email.PriorityHigh, PriorityNormal or PriorityLow, returning an empty
+// string if the value is not recognised. X-Priority values range from
+// "1 (Highest)" to "5 (Lowest)", while the other headers use keywords.
+func normalizePriority(header, value string) email.Priority {
+	if header == "X-Priority" {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return ""
+		}
+		switch value[0] {
+		case '1', '2':
+			return email.PriorityHigh
+		case '3':
+			return email.PriorityNormal
+		case '4', '5':
+			return email.PriorityLow
+		}
+		return ""
+	}
+	switch kw := getKeyword(value); kw {
+	case "high", "urgent":
+		return email.PriorityHigh
+	case "normal":
+		return email.PriorityNormal
+	case "low", "non-urgent":
+		return email.PriorityLow
+	}
+	return ""
+}
+
+// parsePriority records the raw values of the priority headers and
+// reconciles them into a single normalized priority, taken from the
+// first header in order of precedence with a recognised value, or
+// email.PriorityNormal if there is none. A warning is recorded if the
+// recognised values conflict.
+func (se *stagedEmail) parsePriority(h *email.Headers) {
+	winner := ""
+	defer func() {
+		if h.Priority == "" {
+			h.Priority = email.PriorityNormal
+		}
+	}()
+	for _, header := range priorityHeaders {
+		value := se.msg.Header.Get(header)
+		if value == "" {
+			continue
+		}
+		if h.PriorityRaw == nil {
+			h.PriorityRaw = map[string]string{}
+		}
+		h.PriorityRaw[header] = value
+		p := normalizePriority(header, value)
+		switch {
+		case p == "":
+			continue
+		case h.Priority == "":
+			h.Priority, winner = p, header
+		case p != h.Priority:
+			se.warn(fmt.Errorf("priority headers conflict: %s %q (%s) used over %s %q (%s)",
+				winner, h.PriorityRaw[winner], h.Priority, header, value, p))
+		}
+	}
+}
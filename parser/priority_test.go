@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestNormalizePriority(t *testing.T) {
+	tests := []struct {
+		header string
+		value  string
+		want   email.Priority
+	}{
+		{"Importance", "High", "high"},
+		{"Importance", " normal ", "normal"},
+		{"Importance", "low (set by user)", "low"},
+		{"X-Msmail-Priority", "High", "high"},
+		{"X-Msmail-Priority", "Lowest", ""},
+		{"X-Priority", "1 (Highest)", "high"},
+		{"X-Priority", "2", "high"},
+		{"X-Priority", "3 (Normal)", "normal"},
+		{"X-Priority", "5", "low"},
+		{"X-Priority", "urgent", ""},
+		{"X-Priority", "", ""},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := normalizePriority(tt.header, tt.value), tt.want; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		headers  string
+		priority email.Priority
+		raw      map[string]string
+		warnings []string
+	}{
+		{
+			headers:  "",
+			priority: "normal",
+			raw:      nil,
+		},
+		{
+			headers:  "Importance: whenever\n",
+			priority: "normal",
+			raw:      map[string]string{"Importance": "whenever"},
+		},
+		{
+			headers:  "X-Priority: 1 (Highest)\n",
+			priority: "high",
+			raw:      map[string]string{"X-Priority": "1 (Highest)"},
+		},
+		{
+			headers:  "X-Priority: 1\nX-MSMail-Priority: High\nImportance: High\n",
+			priority: "high",
+			raw:      map[string]string{"Importance": "High", "X-Msmail-Priority": "High", "X-Priority": "1"},
+		},
+		{
+			// a gateway leaving X-Priority at its default
+			headers:  "X-Priority: 3 (Normal)\nX-MSMail-Priority: High\nImportance: high\n",
+			priority: "high",
+			raw:      map[string]string{"Importance": "high", "X-Msmail-Priority": "High", "X-Priority": "3 (Normal)"},
+			warnings: []string{`priority headers conflict: Importance "high" (high) used over X-Priority "3 (Normal)" (normal)`},
+		},
+		{
+			headers:  "Importance: whenever\nX-Priority: 5\n",
+			priority: "low",
+			raw:      map[string]string{"Importance": "whenever", "X-Priority": "5"},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			msg := "From: someone@example.com\nSubject: priority\n" + tt.headers + "\nBody.\n"
+			em, err := NewParser().Parse(strings.NewReader(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := em.Headers.Priority, tt.priority; got != want {
+				t.Errorf("priority got %q want %q", got, want)
+			}
+			if diff := cmp.Diff(tt.raw, em.Headers.PriorityRaw); diff != "" {
+				t.Error(diff)
+			}
+			var warnings []string
+			for _, w := range em.Warnings {
+				warnings = append(warnings, w.Error())
+			}
+			if diff := cmp.Diff(tt.warnings, warnings); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// captureRawHeaders reads the header block of the message from r with
+// readRawHeaders, keeping the raw headers in their original order, the
+// raw subject if the parser is set to capture it, and any headers forged
+// by a bare line feed for checkHeaderInjection. The reader to be used in
+// place of r is returned.
+func (se *stagedEmail) captureRawHeaders(r io.Reader) (io.Reader, error) {
+	r, headers, wire, forged, err := readRawHeaders(r)
+	if err != nil {
+		return nil, err
+	}
+	se.forgedHeaders = forged
+	se.rawHeaders = headers
+	if se.parser.rawSubject {
+		for i, h := range headers {
+			if h.Key == "Subject" {
+				se.rawSubject = wire[i]
+				break
+			}
+		}
+	}
+	return r, nil
+}
+
+// readRawHeaders reads the header block of a message, returning the
+// headers in their original order with their values unfolded and, in
+// the same order, their values as they appear on the wire, including
+// any folding but without the whitespace following the colon or the
+// final line ending. A reader replaying the whole message for
+// net/mail.ReadMessage is also returned. Lines that are not headers or
+// continuations are ignored, leaving net/mail to report malformed
+// headers.
+//
+// The keys of headers starting on a line which follows a bare line
+// feed, in a block whose first line ends with CRLF, are also returned.
+// Such a line ending is not produced by a conforming writer of a CRLF
+// message, and is the mark of a header forged by a line feed injected
+// into the value of the header before it.
+func readRawHeaders(r io.Reader) (io.Reader, []email.RawHeader, []string, []string, error) {
+	br := bufio.NewReader(r)
+	var block bytes.Buffer
+	headers := []email.RawHeader{}
+	wire := []string{}
+	var forged []string
+	ending := ""      // the line ending of the previous line
+	blockEnding := "" // the line ending of the first line
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, nil, nil, fmt.Errorf("cannot read headers: %w", err)
+		}
+		block.WriteString(line)
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case trimmed == "":
+			// end of the header block
+		case trimmed[0] == ' ' || trimmed[0] == '\t':
+			// continuation lines are unfolded with a single space
+			if n := len(headers); n > 0 {
+				headers[n-1].Value = strings.TrimSpace(headers[n-1].Value + " " + strings.TrimSpace(trimmed))
+				wire[n-1] += ending + trimmed
+			}
+		default:
+			if key, value, ok := strings.Cut(trimmed, ":"); ok {
+				key = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(key))
+				if blockEnding == "\r\n" && ending == "\n" {
+					forged = append(forged, key)
+				}
+				headers = append(headers, email.RawHeader{
+					Key:   key,
+					Value: strings.TrimSpace(value),
+				})
+				wire = append(wire, strings.TrimLeft(value, " \t"))
+			}
+		}
+		if trimmed == "" || err != nil {
+			break
+		}
+		ending = line[len(trimmed):]
+		if blockEnding == "" {
+			blockEnding = ending
+		}
+	}
+	return io.MultiReader(&block, br), headers, wire, forged, nil
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// parseReceived parses a Received header value into its clauses and
+// timestamp. Comments are removed and clause keywords are matched
+// case-insensitively in any order. Parsing never fails: missing or
+// malformed clauses, such as a keyword without a value, are left
+// empty, as is the Date if the timestamp cannot be parsed, although its
+// text is kept in Timestamp.
+func parseReceived(s string) email.ReceivedHeader {
+	rh := email.ReceivedHeader{Raw: s}
+
+	// remove comments, noting the last semicolon outside of them
+	var b strings.Builder
+	semicolon := -1
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '(':
+			_, i = readComment(s, i)
+			b.WriteByte(' ')
+			continue
+		case ';':
+			semicolon = b.Len()
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	stamp, date := b.String(), ""
+	if semicolon >= 0 {
+		stamp, date = stamp[:semicolon], stamp[semicolon+1:]
+	}
+
+	rh.Timestamp = strings.Join(strings.Fields(date), " ")
+	if rh.Timestamp != "" {
+		if t, err := mail.ParseDate(rh.Timestamp); err == nil {
+			rh.Date = t
+		}
+	}
+
+	fields := strings.Fields(stamp)
+	for i := 0; i+1 < len(fields); i++ {
+		var clause *string
+		switch strings.ToLower(fields[i]) {
+		case "from":
+			clause = &rh.From
+		case "by":
+			clause = &rh.By
+		case "via":
+			clause = &rh.Via
+		case "with":
+			clause = &rh.With
+		case "id":
+			clause = &rh.ID
+		case "for":
+			clause = &rh.For
+		default:
+			continue
+		}
+		if *clause == "" {
+			*clause = fields[i+1]
+		}
+		i++
+	}
+	rh.For = strings.Trim(rh.For, "<>")
+	return rh
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestParseReceived(t *testing.T) {
+	date := time.Date(2025, 4, 8, 10, 0, 5, 0, time.UTC)
+	tests := []struct {
+		raw  string
+		want email.ReceivedHeader
+	}{
+		{
+			raw: "from mx.example.com (mx.example.com [192.0.2.1]) by mail.example.net (Postfix) with ESMTPS id 4A1B2C3D for <alice@example.net>; Tue, 8 Apr 2025 10:00:05 +0000",
+			want: email.ReceivedHeader{
+				From: "mx.example.com", By: "mail.example.net", With: "ESMTPS",
+				ID: "4A1B2C3D", For: "alice@example.net", Date: date,
+				Timestamp: "Tue, 8 Apr 2025 10:00:05 +0000",
+			},
+		},
+		{
+			// folded whitespace and upper case keywords
+			raw: "FROM mx.example.com\t BY  mail.example.net\r\n\tWITH SMTP;\r\n\tTue, 8 Apr 2025 10:00:05 +0000 (UTC)",
+			want: email.ReceivedHeader{
+				From: "mx.example.com", By: "mail.example.net", With: "SMTP", Date: date,
+				Timestamp: "Tue, 8 Apr 2025 10:00:05 +0000",
+			},
+		},
+		{
+			// semicolon in a comment and a nested comment
+			raw: "by mail.example.net (envelope-from <bob@example.com>; (nested)) id abc; Tue, 8 Apr 2025 10:00:05 +0000",
+			want: email.ReceivedHeader{
+				By: "mail.example.net", ID: "abc", Date: date,
+				Timestamp: "Tue, 8 Apr 2025 10:00:05 +0000",
+			},
+		},
+		{
+			// qmail style without clauses
+			raw: "(qmail 12345 invoked by uid 1000); 8 Apr 2025 10:00:05 -0000",
+			want: email.ReceivedHeader{
+				Date: date, Timestamp: "8 Apr 2025 10:00:05 -0000",
+			},
+		},
+		{
+			// malformed date and a trailing keyword without a value
+			raw: "from mx.example.com by; yesterday",
+			want: email.ReceivedHeader{
+				From: "mx.example.com", Timestamp: "yesterday",
+			},
+		},
+		{
+			raw:  "garbage",
+			want: email.ReceivedHeader{},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			tt.want.Raw = tt.raw
+			got := parseReceived(tt.raw)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+				t.Errorf("unexpected received (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseReceivedFixture(t *testing.T) {
+
+	f, err := os.Open("testdata/spamassassin.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser(WithHeadersOnly()).Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(em.Headers.ParsedReceived), len(em.Headers.Received); got != want || got != 1 {
+		t.Fatalf("got %d parsed received want %d", got, want)
+	}
+	r := em.Headers.ParsedReceived[0]
+	if got, want := r.By, "mail.example.com"; got != want {
+		t.Errorf("by got %q want %q", got, want)
+	}
+	if got, want := r.For, "alice@example.com"; got != want {
+		t.Errorf("for got %q want %q", got, want)
+	}
+	// transit latency from the Date header to the first hop
+	if got, want := r.Date.Sub(em.Headers.Date), 5*time.Second; got != want {
+		t.Errorf("latency got %s want %s", got, want)
+	}
+}
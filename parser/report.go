@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// parseFeedbackReport parses the fields of a message/feedback-report
+// part of an ARF report into se.email.FeedbackReport. The fields take
+// the form of headers.
+func (se *stagedEmail) parseFeedbackReport(r io.Reader, ci *email.ContentInfo) error {
+	// the fields are terminated by a blank line to satisfy the header
+	// reader
+	tr := textproto.NewReader(bufio.NewReader(io.MultiReader(
+		se.decodeContent(r, ci),
+		strings.NewReader("\r\n\r\n"),
+	)))
+	fields, err := tr.ReadMIMEHeader()
+	if err != nil {
+		return fmt.Errorf("cannot read feedback report fields: %w", err)
+	}
+	se.email.FeedbackReport = &email.FeedbackReport{
+		FeedbackType:     strings.ToLower(strings.TrimSpace(fields.Get("Feedback-Type"))),
+		UserAgent:        strings.TrimSpace(fields.Get("User-Agent")),
+		OriginalMailFrom: strings.Trim(fields.Get("Original-Mail-From"), idTrimCutset),
+		SourceIP:         strings.TrimSpace(fields.Get("Source-Ip")),
+		Fields:           fields,
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFeedbackReport(t *testing.T) {
+
+	f, err := os.Open("testdata/feedback_report.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr := em.FeedbackReport
+	if fr == nil {
+		t.Fatal("expected feedback report")
+	}
+	got := []string{fr.FeedbackType, fr.UserAgent, fr.OriginalMailFrom, fr.SourceIP}
+	want := []string{"abuse", "SomeGenerator/1.0", "somespammer@example.net", "192.0.2.1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(
+		[]string{"http://example.net/earn_money.html", "mailto:user@example.com"},
+		fr.Fields["Reported-Uri"],
+	); diff != "" {
+		t.Error(diff)
+	}
+
+	// the original message is attached
+	if got, want := len(em.Files), 1; got != want {
+		t.Fatalf("got %d files want %d", got, want)
+	}
+	if got, want := em.Files[0].ContentInfo.Type, "message/rfc822"; got != want {
+		t.Errorf("got type %s want %s", got, want)
+	}
+	if !strings.Contains(string(em.Files[0].Data), "Subject: Earn money") {
+		t.Error("original message not attached")
+	}
+}
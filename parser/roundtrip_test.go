@@ -0,0 +1,234 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rorycl/letters/email"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+
+	tests := []string{
+		"testdata/cats.eml",
+		"testdata/related_locations.eml",
+		"testdata/rfc2231_filenames.eml",
+		"testdata/calendar.eml",
+		"testdata/forward.eml",
+		"testdata/voicemail.eml",
+		"testdata/content_headers.eml",
+		"../tests/test_chinese_multipart_related_gb18030_over_base64.txt",
+	}
+
+	for i, fp := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open(fp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			p := NewParser()
+			want, err := p.Parse(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := email.Encode(&buf, want); err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Parse(&buf)
+			if err != nil {
+				t.Fatalf("cannot parse encoded email: %v\n%s", err, buf.String())
+			}
+			if diff := cmp.Diff(want, got,
+				// the transfer encodings, charsets, parameters and part
+				// indexes of the parts, the headers added by encoding or
+				// mbox delivery, the order of the headers and warnings
+				// about the original structure are not reproduced
+				cmpopts.IgnoreFields(email.Email{}, "Warnings"),
+				cmpopts.IgnoreFields(email.Headers{}, "RawOrdered"),
+				cmpopts.IgnoreFields(email.File{}, "Reader", "Index"),
+				cmpopts.IgnoreFields(email.ContentInfo{}, "Encoding", "encDone",
+					"TypeParams", "TransferEncoding", "IgnoredEncoding", "Charset"),
+				cmpopts.IgnoreMapEntries(func(k string, _ []string) bool {
+					return k == "Mime-Version" || k == "Content-Length"
+				}),
+				cmp.Comparer(bytes.Equal),
+			); diff != "" {
+				t.Errorf("emails are not equal (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeConstructed(t *testing.T) {
+
+	e := &email.Email{
+		Headers: email.Headers{
+			Date:      time.Date(2025, 4, 8, 10, 0, 0, 0, time.UTC),
+			From:      []*mail.Address{{Name: "Zoë Ünal", Address: "zoe@example.com"}},
+			To:        []*mail.Address{{Address: "bob@example.net"}, {Name: "Carol", Address: "carol@example.net"}},
+			MessageID: "constructed-1@example.com",
+			Subject:   "Café menu – " + strings.Repeat("long subject ", 8),
+			ExtraHeaders: map[string][]string{
+				"X-Redacted": {"true\r\nBcc: injected@example.com"},
+			},
+		},
+		Text: "Menu attached.\n\nZoë",
+		Files: []*email.File{
+			{FileType: "attachment", Name: "menü.pdf", ContentInfo: &email.ContentInfo{Type: "application/pdf"}, Data: []byte("%PDF-1.4")},
+			{FileType: "attachment", Name: "notes.txt", Data: []byte(strings.Repeat("x", 200))},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := email.Encode(&buf, e); err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 78 {
+			t.Errorf("line %d too long: %q", i, line)
+		}
+	}
+
+	got, err := NewParser().Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := got.Headers
+	if got, want := h.Subject, e.Headers.Subject; got != want {
+		t.Errorf("got subject %q want %q", got, want)
+	}
+	if got, want := h.From[0].Name, "Zoë Ünal"; got != want {
+		t.Errorf("got from name %q want %q", got, want)
+	}
+	if got, want := len(h.To), 2; got != want {
+		t.Errorf("got %d to addresses want %d", got, want)
+	}
+	if got, want := h.MessageID, e.Headers.MessageID; got != want {
+		t.Errorf("got message id %q want %q", got, want)
+	}
+	if h.Bcc != nil || got.HeaderInjectionSuspected {
+		t.Errorf("header injection not sanitized: %v", h.Bcc)
+	}
+	if got, want := got.Text, e.Text; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	files := []string{}
+	for _, f := range got.Files {
+		files = append(files, fmt.Sprintf("%s %s %s %d", f.FileType, f.Name, f.ContentInfo.Type, len(f.Data)))
+	}
+	want := []string{
+		"attachment menü.pdf application/pdf 8",
+		"attachment notes.txt application/octet-stream 200",
+	}
+	if diff := cmp.Diff(want, files); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteToStripAttachments(t *testing.T) {
+
+	f, err := os.Open("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(em.Files) == 0 {
+		t.Fatal("expected files")
+	}
+
+	// parse, modify and write the email without its files
+	em.Files = nil
+	var buf bytes.Buffer
+	n, err := em.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := n, int64(buf.Len()); got != want {
+		t.Errorf("got %d bytes written want %d", got, want)
+	}
+
+	got, err := NewParser().Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(got.Files), 0; got != want {
+		t.Errorf("got %d files want %d", got, want)
+	}
+	if got, want := got.Text, em.Text; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := got.Headers.Subject, em.Headers.Subject; got != want {
+		t.Errorf("got subject %q want %q", got, want)
+	}
+}
+
+func TestEncodeForwardedMessage(t *testing.T) {
+
+	tests := []struct {
+		enclosed string
+		encoding string
+	}{
+		{"Subject: Lunch on Friday\r\n\r\nLunch on Friday?\r\n", "7bit"},
+		{"Subject: =?utf-8?q?Caf=C3=A9?=\r\n\r\nLunch at the café?\r\n", "8bit"},
+		{"Subject: Long\r\n\r\n" + strings.Repeat("x", 1000) + "\r\n", "binary"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			e := &email.Email{
+				Headers: email.Headers{
+					Date:      time.Date(2025, 4, 8, 12, 0, 0, 0, time.UTC),
+					From:      []*mail.Address{{Address: "bob@example.net"}},
+					MessageID: "forward-2@example.net",
+				},
+				Text: "See the message below.",
+				Files: []*email.File{
+					{
+						FileType:    "attachment",
+						Name:        "lunch.eml",
+						ContentInfo: &email.ContentInfo{Type: "message/rfc822"},
+						Data:        []byte(tt.enclosed),
+					},
+				},
+			}
+			var buf bytes.Buffer
+			if err := email.Encode(&buf, e); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := strings.Contains(buf.String(), "Content-Transfer-Encoding: "+tt.encoding+"\r\n"), true; got != want {
+				t.Errorf("%s transfer encoding not found:\n%s", tt.encoding, buf.String())
+			}
+
+			got, err := NewParser().Parse(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(got.Files), 1; got != want {
+				t.Fatalf("got %d files want %d", got, want)
+			}
+			f := got.Files[0]
+			if got, want := f.ContentInfo.TransferEncoding, tt.encoding; got != want {
+				t.Errorf("got transfer encoding %q want %q", got, want)
+			}
+			if got, want := string(f.Data), tt.enclosed; got != want {
+				t.Errorf("got data %q want %q", got, want)
+			}
+			if got, want := len(got.SubMessages), 1; got != want {
+				t.Errorf("got %d enclosed messages want %d", got, want)
+			}
+		})
+	}
+}
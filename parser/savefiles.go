@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// "savefiles" provides helpers for WithSaveFilesToDirectory which guard
+// against hostile or clashing file names in emails.
+
+// maxUniqueFileAttempts is the number of numbered variants of a file
+// name tried before giving up
+const maxUniqueFileAttempts int = 1000
+
+// sanitizeFileName makes a file name from an email safe to use in a
+// directory. Only the part following the last forward or back slash is
+// retained and control characters are removed. Names which are empty
+// or consist only of dots once cleaned, such as "..", are replaced by
+// the fallback.
+func sanitizeFileName(name, fallback string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if strings.Trim(name, ".") == "" {
+		return fallback
+	}
+	return name
+}
+
+// createUniqueFile exclusively creates a file with the name in dir. If a
+// file of that name exists, a number is added before the extension,
+// such as "report (1).pdf", "report (2).pdf" and so on, until a name
+// is found which does not.
+func createUniqueFile(dir, name string) (*os.File, error) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; i <= maxUniqueFileAttempts; i++ {
+		f, err := os.OpenFile(filepath.Join(dir, candidate), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, fs.ErrExist) {
+			return f, err
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", stem, i, ext)
+	}
+	return nil, fmt.Errorf("no unique name for %q after %d attempts", name, maxUniqueFileAttempts)
+}
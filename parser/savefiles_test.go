@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"../../etc/cron.d/x", "x"},
+		{"/etc/passwd", "passwd"},
+		{`C:\Users\evil\run.bat`, "run.bat"},
+		{"..", "fallback"},
+		{"", "fallback"},
+		{"dir/", "fallback"},
+		{"bad\x00na\x1bme\n.txt", "badname.txt"},
+		{" spaced.txt ", "spaced.txt"},
+		{".hidden", ".hidden"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := sanitizeFileName(tt.name, "fallback"), tt.want; got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCreateUniqueFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"report.pdf", "report.pdf", "report.pdf", "notes", "notes"} {
+		f, err := createUniqueFile(dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	slices.Sort(got)
+	want := []string{"notes", "notes (1)", "report (1).pdf", "report (2).pdf", "report.pdf"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOptSaveFilesToDirectoryHostileNames(t *testing.T) {
+
+	part := func(name, content string) string {
+		return "--b\r\nContent-Type: application/octet-stream\r\n" +
+			"Content-Disposition: attachment; filename=\"" + name + "\"\r\n\r\n" +
+			content + "\r\n"
+	}
+	msg := "Subject: hostile\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		part("../../escape.txt", "one") +
+		part("dup.txt", "two") +
+		part("dup.txt", "three") +
+		part("..", "four") +
+		"--b--\r\n"
+
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "files")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewParser(WithSaveFilesToDirectory(dir)).Parse(strings.NewReader(msg)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[e.Name()] = string(b)
+	}
+	want := map[string]string{
+		"escape.txt":   "one",
+		"dup.txt":      "two",
+		"dup (1).txt":  "three",
+		"attachment-4": "four",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+	if entries, _ := os.ReadDir(parent); len(entries) != 1 {
+		t.Errorf("got %d entries in parent directory want 1", len(entries))
+	}
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rorycl/letters/email"
+)
+
+// sizeLimitReader is an io.Reader which fails with a
+// SizeLimitExceededError matching tooLarge once more than max bytes
+// have been read, so that oversized messages and files are rejected as
+// they are streamed rather than after being buffered. The error
+// describes the content by desc, and is returned by all subsequent
+// reads.
+type sizeLimitReader struct {
+	r        io.Reader
+	desc     string
+	tooLarge error
+	max      int64
+	read     int64
+	err      error
+}
+
+func (s *sizeLimitReader) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	// read at most one byte beyond the limit to detect oversized content
+	if remaining := s.max - s.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := s.r.Read(p)
+	s.read += int64(n)
+	if s.read > s.max {
+		s.err = &SizeLimitExceededError{What: s.desc, Limit: s.max, tooLarge: s.tooLarge}
+		return n - int(s.read-s.max), s.err
+	}
+	return n, err
+}
+
+// limitPart limits the content r of a part to the parser's maximum part
+// size, if any.
+func (se *stagedEmail) limitPart(r io.Reader, ci *email.ContentInfo) io.Reader {
+	limit := se.parser.maxPartSize
+	if limit <= 0 {
+		return r
+	}
+	return &sizeLimitReader{
+		r:        r,
+		desc:     fmt.Sprintf("part of type %q", ci.Type),
+		tooLarge: ErrPartTooLarge,
+		max:      limit,
+	}
+}
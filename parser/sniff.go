@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// sniffPeekSize is the number of bytes of content inspected when
+// sniffing the transfer encoding of content.
+const sniffPeekSize int = 512
+
+// base64Alphabet is the standard base64 alphabet
+const base64Alphabet string = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// sniffTransferEncoding peeks at the start of the content in r to
+// determine if it looks base64 or, if quotedPrintable is true,
+// quoted-printable encoded, returning "base64", "quoted-printable" or
+// an empty string if neither. Since the peek consumes from the reader,
+// the returned reader should be used in place of the one provided.
+func sniffTransferEncoding(r io.Reader, quotedPrintable bool) (io.Reader, string) {
+	br := bufio.NewReaderSize(r, sniffPeekSize)
+	peek, _ := br.Peek(sniffPeekSize)
+	truncated := len(peek) == sniffPeekSize
+	switch {
+	case looksBase64(peek, truncated):
+		return br, "base64"
+	case quotedPrintable && looksQuotedPrintable(peek, truncated):
+		return br, "quoted-printable"
+	}
+	return br, ""
+}
+
+// looksBase64 reports if the content consists only of lines of the
+// base64 alphabet with lengths that are a multiple of four, all of equal
+// length except for the last line which may be shorter and padded. If
+// truncated, the last line of content is disregarded as it may be
+// incomplete.
+func looksBase64(content []byte, truncated bool) bool {
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	if truncated && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	chars, lineLen := 0, 0
+	for i, line := range lines {
+		line = bytes.TrimRight(line, "\r")
+		last := i == len(lines)-1
+		if len(line) == 0 {
+			return false
+		}
+		trimmed := bytes.TrimRight(line, "=")
+		if len(line)-len(trimmed) > 2 || (!last && len(trimmed) != len(line)) {
+			return false
+		}
+		for _, c := range trimmed {
+			if strings.IndexByte(base64Alphabet, c) < 0 {
+				return false
+			}
+		}
+		switch {
+		case i == 0:
+			lineLen = len(line)
+		case !last && len(line) != lineLen:
+			return false
+		case last && len(line) > lineLen:
+			return false
+		}
+		if len(line)%4 != 0 {
+			return false
+		}
+		chars += len(trimmed)
+	}
+	return chars >= 16
+}
+
+// qpMaxLineLen is the maximum length of a quoted-printable line,
+// excluding the line ending
+const qpMaxLineLen int = 76
+
+// looksQuotedPrintable reports if the content consists only of lines
+// of printable ASCII no longer than 76 characters in which every "="
+// introduces either a "=XX" hexadecimal escape or a soft line break,
+// with at least two such escapes or soft line breaks present. If
+// truncated, the last line of content is disregarded as it may be
+// incomplete.
+func looksQuotedPrintable(content []byte, truncated bool) bool {
+	lines := bytes.Split(content, []byte("\n"))
+	if truncated && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	escapes := 0
+	for _, line := range lines {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) > qpMaxLineLen {
+			return false
+		}
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			switch {
+			case c == '=' && i == len(line)-1:
+				escapes++ // soft line break
+			case c == '=':
+				if i+2 >= len(line) || !isUpperHex(line[i+1]) || !isUpperHex(line[i+2]) {
+					return false
+				}
+				escapes++
+				i += 2
+			case c != '\t' && (c < ' ' || c > '~'):
+				return false
+			}
+		}
+	}
+	return escapes >= 2
+}
+
+// isUpperHex reports if the byte is an uppercase hexadecimal digit, as
+// required in quoted-printable escapes
+func isUpperHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLooksBase64(t *testing.T) {
+	tests := []struct {
+		content   string
+		truncated bool
+		isBase64  bool
+	}{
+		{
+			content:  "VGhpcyBhdHRhY2htZW50IHdhcyBzZW50IGFzIGJh\nc2U2NCBidXQgZGVjbGFyZWQgYXMgYmluYXJ5IGJ5\nIGEgYnJva2VuIHNlbmRlci4=\n",
+			isBase64: true,
+		},
+		{
+			content:  "VGhpcyBhdHRhY2htZW50IHdhcyBzZW50IGFzIGJh\r\nc2U2NCBidXQgZGVjbGFyZWQgYXMgYmluYXJ5IGJ5\r\n",
+			isBase64: true,
+		},
+		{
+			content:   "VGhpcyBhdHRhY2htZW50IHdhcyBzZW50IGFzIGJh\nc2U2NCBidXQgZGVj",
+			truncated: true,
+			isBase64:  true,
+		},
+		{
+			content:  "This is plain text.\nIt is not base64.\n",
+			isBase64: false,
+		},
+		{
+			content:  "VGhpcyBhdHRhY2htZW50IHdhcyBzZW50IGFzIGJh\nc2U2NCBidXQ\nZGVjbGFyZWQgYXMgYmluYXJ5IGJ5\n",
+			isBase64: false, // irregular line lengths
+		},
+		{
+			content:  "QUJD",
+			isBase64: false, // too short
+		},
+		{
+			content:  "",
+			isBase64: false,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := looksBase64([]byte(tt.content), tt.truncated), tt.isBase64; got != want {
+				t.Errorf("got %t want %t", got, want)
+			}
+		})
+	}
+}
+
+func TestLooksQuotedPrintable(t *testing.T) {
+	tests := []struct {
+		content   string
+		truncated bool
+		isQP      bool
+	}{
+		{
+			content: "Caf=C3=A9 au lait\r\nand a long line which has been soft=\r\nly broken.\r\n",
+			isQP:    true,
+		},
+		{
+			content:   "Caf=C3=A9 au lait, cr=C3=A8me\nbr=C3=BB",
+			truncated: true,
+			isQP:      true,
+		},
+		{
+			content: "This is plain text.\nIt is not quoted-printable.\n",
+			isQP:    false, // no escapes
+		},
+		{
+			content: "Caf=C3=A9 where a=b\n",
+			isQP:    false, // invalid escape
+		},
+		{
+			content: "caf=c3=a9\n",
+			isQP:    false, // lowercase escapes
+		},
+		{
+			content: "Caf=C3=A9 au lait " + strings.Repeat("x", 80) + "\n",
+			isQP:    false, // overlong line
+		},
+		{
+			content: "Café =C3=A9\n",
+			isQP:    false, // 8bit content
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			if got, want := looksQuotedPrintable([]byte(tt.content), tt.truncated), tt.isQP; got != want {
+				t.Errorf("got %t want %t", got, want)
+			}
+		})
+	}
+}
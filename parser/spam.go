@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// spamFieldRegexp matches the "key=value" fields of a SpamAssassin
+// X-Spam-Status header, such as "score=5.2". The value of the tests
+// field may contain the whitespace of folding after its commas, which
+// is removed before matching.
+var spamFieldRegexp = regexp.MustCompile(`([a-z_]+)=(\S*)`)
+
+// spamTestsFoldRegexp matches the whitespace following a comma in the
+// list of tests, left by unfolding a long X-Spam-Status header.
+var spamTestsFoldRegexp = regexp.MustCompile(`,\s+`)
+
+// parseSpamStatus parses the SpamAssassin X-Spam-Status header, such as
+// "Yes, score=5.2 required=5.0 tests=BAYES_99,HTML_MESSAGE
+// autolearn=no", into h.SpamStatus. Older versions of SpamAssassin use
+// "hits" in place of "score". The X-Spam-Score header provides the
+// score if the status is absent or lacks one. A warning is recorded
+// for scores which cannot be parsed.
+func (se *stagedEmail) parseSpamStatus(h *email.Headers) {
+	status := strings.TrimSpace(se.msg.Header.Get("X-Spam-Status"))
+	score := strings.TrimSpace(se.msg.Header.Get("X-Spam-Score"))
+	if status == "" && score == "" {
+		return
+	}
+	ss := &email.SpamStatus{}
+	parseFloat := func(header, field, v string) float64 {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			se.warn(fmt.Errorf("invalid %s %s %q", header, field, v))
+		}
+		return f
+	}
+
+	scoreFound := false
+	if status != "" {
+		verdict, rest, _ := strings.Cut(status, ",")
+		ss.Flagged = strings.EqualFold(strings.TrimSpace(verdict), "yes")
+		rest = spamTestsFoldRegexp.ReplaceAllString(rest, ",")
+		for _, m := range spamFieldRegexp.FindAllStringSubmatch(rest, -1) {
+			switch key, value := m[1], m[2]; key {
+			case "score", "hits":
+				ss.Score, scoreFound = parseFloat("X-Spam-Status", key, value), true
+			case "required":
+				ss.Required = parseFloat("X-Spam-Status", key, value)
+			case "tests":
+				for _, t := range strings.Split(value, ",") {
+					if t = strings.TrimSpace(t); t != "" && t != "none" {
+						ss.Tests = append(ss.Tests, t)
+					}
+				}
+			}
+		}
+	}
+	if !scoreFound && score != "" {
+		ss.Score = parseFloat("X-Spam-Score", "score", score)
+	}
+	h.SpamStatus = ss
+}
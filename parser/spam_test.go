@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestParseSpamStatus(t *testing.T) {
+	tests := []struct {
+		headers  string
+		status   *email.SpamStatus
+		warnings int
+	}{
+		{
+			headers: "",
+			status:  nil,
+		},
+		{
+			headers: "X-Spam-Status: No, score=-1.9 required=5.0 tests=BAYES_00,\r\n\tDKIM_SIGNED autolearn=ham version=3.4.6\r\n",
+			status:  &email.SpamStatus{Score: -1.9, Required: 5, Tests: []string{"BAYES_00", "DKIM_SIGNED"}},
+		},
+		{
+			headers: "X-Spam-Status: Yes, hits=7.5 required=5.0 tests=none\r\n",
+			status:  &email.SpamStatus{Flagged: true, Score: 7.5, Required: 5},
+		},
+		{
+			headers: "X-Spam-Score: 3.1\r\n",
+			status:  &email.SpamStatus{Score: 3.1},
+		},
+		{
+			headers: "X-Spam-Status: No, required=5.0 tests=\r\nX-Spam-Score: 0.4\r\n",
+			status:  &email.SpamStatus{Score: 0.4, Required: 5},
+		},
+		{
+			headers:  "X-Spam-Status: Yes, score=high required=5.0\r\n",
+			status:   &email.SpamStatus{Flagged: true, Required: 5},
+			warnings: 1,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			msg := "Subject: spam\r\n" + tt.headers + "\r\nBody.\r\n"
+			em, err := NewParser(WithHeadersOnly()).Parse(strings.NewReader(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.status, em.Headers.SpamStatus); diff != "" {
+				t.Errorf("unexpected spam status (-want +got):\n%s", diff)
+			}
+			if got, want := len(em.Warnings), tt.warnings; got != want {
+				t.Errorf("got %d warnings want %d: %v", got, want, em.Warnings)
+			}
+		})
+	}
+}
+
+func TestParseSpamAssassinFixture(t *testing.T) {
+
+	f, err := os.Open("testdata/spamassassin.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	em, err := NewParser().Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &email.SpamStatus{
+		Flagged:  true,
+		Score:    5.2,
+		Required: 5,
+		Tests: []string{
+			"BAYES_99", "BAYES_999", "HTML_IMAGE_ONLY_16",
+			"HTML_MESSAGE", "RDNS_NONE", "URIBL_BLACK",
+		},
+	}
+	if diff := cmp.Diff(want, em.Headers.SpamStatus); diff != "" {
+		t.Errorf("unexpected spam status (-want +got):\n%s", diff)
+	}
+	if _, ok := em.Headers.ExtraHeaders["X-Spam-Status"]; !ok {
+		t.Error("expected X-Spam-Status to remain in extra headers")
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rorycl/letters/email"
+)
+
+// "spool" provides the file func used by WithLazyFiles, which retains
+// the decoded content of each file in memory or, beyond a threshold, in
+// a temporary file, to be read on demand.
+
+// spoolWriter is an io.Writer holding up to threshold bytes in memory
+// and spooling larger content to a temporary file in dir, or the
+// default directory for temporary files if dir is empty.
+type spoolWriter struct {
+	threshold int64
+	dir       string
+	buf       bytes.Buffer
+	tmp       *os.File
+	size      int64
+}
+
+func (s *spoolWriter) Write(p []byte) (int, error) {
+	if s.tmp == nil && s.size+int64(len(p)) > s.threshold {
+		tmp, err := os.CreateTemp(s.dir, "letters-*")
+		if err != nil {
+			return 0, fmt.Errorf("cannot create spool file: %w", err)
+		}
+		s.tmp = tmp
+		if _, err := s.buf.WriteTo(s.tmp); err != nil {
+			return 0, err
+		}
+	}
+	var n int
+	var err error
+	if s.tmp != nil {
+		n, err = s.tmp.Write(p)
+	} else {
+		n, err = s.buf.Write(p)
+	}
+	s.size += int64(n)
+	return n, err
+}
+
+// lazyData returns the content written as LazyData, which then owns
+// any temporary file, or the first error encountered writing it, in
+// which case any temporary file is removed.
+func (s *spoolWriter) lazyData(err error) (*email.LazyData, error) {
+	if s.tmp == nil {
+		if err != nil {
+			return nil, err
+		}
+		return email.NewLazyData(s.buf.Bytes(), "", s.size), nil
+	}
+	tmp := s.tmp
+	s.tmp = nil
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return nil, fmt.Errorf("cannot spool file: %w", err)
+	}
+	return email.NewLazyData(nil, tmp.Name(), s.size), nil
+}
+
+// discard removes any temporary file not passed on by lazyData.
+func (s *spoolWriter) discard() {
+	if s.tmp == nil {
+		return
+	}
+	_ = s.tmp.Close()
+	_ = os.Remove(s.tmp.Name())
+	s.tmp = nil
+}
+
+// spoolFile reads the content of the file into f.Lazy, holding content
+// of up to threshold bytes in memory and spooling larger content to a
+// temporary file in dir, or the default directory for temporary files
+// if dir is empty.
+func spoolFile(f *email.File, threshold int64, dir string) error {
+	sw := &spoolWriter{threshold: threshold, dir: dir}
+	_, err := io.Copy(sw, f.Reader)
+	f.Lazy, err = sw.lazyData(err)
+	return err
+}
@@ -1,12 +1,17 @@
 package parser
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/mail"
+	"slices"
 	"strings"
 
+	"github.com/rorycl/letters/decoders"
 	"github.com/rorycl/letters/email"
 )
 
@@ -29,27 +34,332 @@ type stagedEmail struct {
 
 	// email to be built and returned, for incremental processing
 	email *email.Email
+
+	// rawHeaders are the headers in their original order
+	rawHeaders []email.RawHeader
+
+	// rawSubject is the Subject header as it appears on the wire, if
+	// captured
+	rawSubject string
+
+	// forgedHeaders are the keys of headers which follow a bare line
+	// feed in a CRLF header block, as found by readRawHeaders
+	forgedHeaders []string
+
+	// bodyTypesSeen records the text content types of the body parts
+	// encountered
+	bodyTypesSeen map[string]bool
+
+	// partIndex is the index of the part being parsed in depth-first
+	// document order, and partCount the number of parts encountered
+	partIndex, partCount int
+
+	// depth is the nesting depth of the part being parsed, counting
+	// the parts of enclosing messages
+	depth int
+
+	// messageDepth is the number of messages enclosing the email, or 0
+	// for the outermost message
+	messageDepth int
+
+	// ctx is the context of the parse, checked at part boundaries
+	ctx context.Context
 }
 
 // newStagedEmail returns an initialised *stagedEmail
 func newStagedEmail(p *Parser) *stagedEmail {
 	return &stagedEmail{
-		parser: p,
-		email:  &email.Email{},
-		msg:    &mail.Message{},
+		parser:        p,
+		ctx:           context.Background(),
+		email:         &email.Email{},
+		msg:           &mail.Message{},
+		bodyTypesSeen: map[string]bool{},
+	}
+}
+
+// addOrderedPart records a body part or file in document order if the
+// parser is set to record ordered parts.
+func (se *stagedEmail) addOrderedPart(kind, text string, file *email.File) {
+	if !se.parser.orderedParts {
+		return
+	}
+	se.email.OrderedParts = append(se.email.OrderedParts, email.OrderedPart{
+		Kind: kind,
+		Text: text,
+		File: file,
+	})
+}
+
+// addLanguageBody records the text or html body of a part declaring a
+// Content-Language, combining bodies of the same languages.
+func (se *stagedEmail) addLanguageBody(languages []string, kind, body string) {
+	if len(languages) == 0 {
+		return
+	}
+	key := strings.ToLower(strings.Join(languages, ","))
+	i := slices.IndexFunc(se.email.LanguageBodies, func(lb email.LanguageBody) bool {
+		return strings.ToLower(strings.Join(lb.Languages, ",")) == key
+	})
+	if i < 0 {
+		se.email.LanguageBodies = append(se.email.LanguageBodies, email.LanguageBody{Languages: languages})
+		i = len(se.email.LanguageBodies) - 1
+	}
+	lb := &se.email.LanguageBodies[i]
+	switch kind {
+	case email.OrderedPartText:
+		lb.Text = joinBody(lb.Text, body)
+	case email.OrderedPartHTML:
+		lb.HTML += body
+	}
+}
+
+// joinBody joins plain text bodies with a blank line separator
+func joinBody(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "\n\n" + b
+}
+
+// dropEmptyText reports if a text part is to be dropped because it is
+// empty once decoded, in which case it no longer counts as the first
+// text part of its type.
+func (se *stagedEmail) dropEmptyText(ci *email.ContentInfo, body string) bool {
+	if !se.parser.dropEmptyTextParts || body != "" {
+		return false
+	}
+	delete(se.bodyTypesSeen, ci.Type)
+	return true
+}
+
+// warn records a non-fatal parsing problem on the email.
+func (se *stagedEmail) warn(err error) {
+	se.email.Warnings = append(se.email.Warnings, err)
+}
+
+// recovered records an error recovered from through lenient parsing
+// in the email's Errors and Warnings. ci is the content info of the
+// part in which the error was encountered, or nil for the headers.
+func (se *stagedEmail) recovered(err error, ci *email.ContentInfo) {
+	pe := email.ParseError{Err: err}
+	if ci != nil {
+		pe.ContentType = ci.Type
+	}
+	se.email.Errors = append(se.email.Errors, pe)
+	se.warn(err)
+}
+
+// isolatePartError records the error decoding the part with content
+// info ci if the parser is lenient, reporting if parsing of the
+// remaining parts should continue. Exceeding the maximum message size
+// or cancellation of the parse is never isolated, as the remainder of
+// the message cannot be read.
+func (se *stagedEmail) isolatePartError(err error, ci *email.ContentInfo) bool {
+	if !se.parser.lenient || errors.Is(err, ErrMessageTooLarge) || se.ctx.Err() != nil {
+		return false
 	}
+	se.recovered(err, ci)
+	se.email.DecodeStats.LenientRepairs++
+	return true
 }
 
-// parsePart parses the parts of a multipart message and may be called
-// recursively.
-func (se *stagedEmail) parsePart(msg io.Reader, parentCI *email.ContentInfo, boundary string) error {
+// recoverError records a header parsing error and returns nil if the
+// parser is lenient, otherwise returning the error.
+func (se *stagedEmail) recoverError(err error) error {
+	if !se.parser.lenient {
+		return err
+	}
+	se.recovered(err, nil)
+	se.email.DecodeStats.LenientRepairs++
+	return nil
+}
+
+// recoverHeader handles the error, if any, of parsing the named header
+// with the given value as recoverError does. Errors reporting an empty
+// address or date are disregarded.
+func (se *stagedEmail) recoverHeader(err error, name, value string) error {
+	if err == nil || errors.Is(err, errorEmptyAddress) || errors.Is(err, errorEmptyDate) {
+		return nil
+	}
+	return se.recoverError(fmt.Errorf("%s header: (%s) %w", name, value, err))
+}
 
+// decodeContent wraps decoders.DecodeContent, recording a charset
+// fallback in the email's DecodeStats if the content info declares a
+// charset for which no encoding can be found, in which case the parser's
+// fallback charset, if any, is used. The parser's encoding resolver, if
+// any, is consulted before the built-in charset lookup.
+//
+// If the parser is set to sniff transfer encodings, content declared
+// as "binary" which looks base64 encoded is decoded as base64. If set
+// to sniff identity encodings, content declared with any identity
+// encoding is also sniffed for quoted-printable encoding. The decoded
+// content is limited to the parser's maximum part size, if any.
+func (se *stagedEmail) decodeContent(r io.Reader, ci *email.ContentInfo) io.Reader {
+	if se.parser.encodingResolver != nil && ci.Encoding == nil {
+		ci.Encoding = se.parser.encodingResolver(ci)
+	}
+	if ci.Encoding == nil {
+		ci.ExtractEncoding()
+		if ci.Charset != "" && ci.Encoding == nil {
+			se.email.DecodeStats.CharsetFallbacks++
+			ci.Encoding = se.parser.charsetFallback
+			if se.parser.lenient {
+				se.recovered(fmt.Errorf("unknown charset %q of content of type %q", ci.Charset, ci.Type), ci)
+			}
+		}
+	}
+	sniff := se.parser.sniffTransferEncoding && ci.TransferEncoding == "binary"
+	if se.parser.sniffIdentityEncodings && !strings.HasPrefix(ci.Type, "multipart/") {
+		switch ci.TransferEncoding {
+		case "", "7bit", "8bit", "binary":
+			sniff = true
+		}
+	}
+	if sniff {
+		var sniffed string
+		if r, sniffed = sniffTransferEncoding(r, se.parser.sniffIdentityEncodings); sniffed != "" {
+			// decode using a copy to retain the declared encoding
+			sniffedCI := *ci
+			sniffedCI.TransferEncoding = sniffed
+			declared := ci.TransferEncoding
+			if declared == "" {
+				declared = "7bit" // the default encoding
+			}
+			se.warn(fmt.Errorf("content of type %q declared as %s decoded as %s", ci.Type, declared, sniffed))
+			se.email.DecodeStats.LenientRepairs++
+			return se.limitPart(decoders.DecodeContent(r, &sniffedCI), ci)
+		}
+	}
+	return se.limitPart(decoders.DecodeContent(r, ci), ci)
+}
+
+// applyDefaultDisposition sets the parser's default disposition, if
+// any, on a file part lacking a Content-Disposition. Body text,
+// multipart and specially handled message parts are not files and are
+// left unaltered.
+func (se *stagedEmail) applyDefaultDisposition(ci *email.ContentInfo) {
+	if se.parser.defaultDisposition == "" || ci.Disposition != "" {
+		return
+	}
+	switch ci.Type {
+	case "text/plain", "text/enriched", "text/html",
+		"message/external-body", "message/feedback-report":
+		return
+	}
+	if strings.HasPrefix(ci.Type, "multipart/") {
+		return
+	}
+	ci.Disposition = se.parser.defaultDisposition
+}
+
+// messageDepthExceeded reports if an enclosed message would be nested
+// more deeply than permitted by WithMaxMessageDepth.
+func (se *stagedEmail) messageDepthExceeded() bool {
+	limit := se.parser.maxMessageDepth
+	return limit > 0 && se.messageDepth >= limit
+}
+
+// parseSubMessage parses an enclosed message into a nested email using
+// the same parser settings, adding it to the email's SubMessages. The
+// enclosed message is nested one level below the current part. An
+// enclosed message nested more deeply than permitted by
+// WithMaxMessageDepth is not parsed, which is recorded as a warning.
+func (se *stagedEmail) parseSubMessage(r io.Reader) error {
+	if se.messageDepthExceeded() {
+		se.warn(fmt.Errorf("%w: enclosed message at depth %d not parsed", ErrMaxMessageDepthExceeded, se.messageDepth+1))
+		return nil
+	}
+	sub, err := se.parser.parseMessage(se.ctx, r, se.depth+1, se.messageDepth+1)
+	if err != nil {
+		return err
+	}
+	se.email.SubMessages = append(se.email.SubMessages, sub)
+	return nil
+}
+
+// boundaryPeekSize is the maximum number of bytes of a multipart part
+// provided to a custom boundary func.
+const boundaryPeekSize int = 4096
+
+// sniffBoundary consults the parser's boundaryFunc, if set, to
+// determine the boundary to use for a multipart part. Since the peek
+// consumes from the reader, the returned reader should be used in place
+// of the one provided.
+func (se *stagedEmail) sniffBoundary(msg io.Reader, ci *email.ContentInfo, boundary string) (io.Reader, string) {
+	if se.parser.boundaryFunc == nil {
+		return msg, boundary
+	}
+	br := bufio.NewReaderSize(msg, boundaryPeekSize)
+	peek, err := br.Peek(boundaryPeekSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return br, boundary
+	}
+	if b := se.parser.boundaryFunc(ci, peek); b != "" {
+		boundary = b
+	}
+	return br, boundary
+}
+
+// ErrMaxMessageDepthExceeded is wrapped by the warning recorded when an
+// enclosed message is nested more deeply than permitted by
+// WithMaxMessageDepth.
+var ErrMaxMessageDepthExceeded error = errors.New("maximum message depth exceeded")
+
+// ErrMaxPartDepthExceeded is returned when multipart parts are nested
+// more deeply than permitted by WithMaxPartDepth.
+var ErrMaxPartDepthExceeded error = errors.New("maximum part depth exceeded")
+
+// defaultMaxPartDepth is the default maximum nesting depth of multipart
+// parts
+const defaultMaxPartDepth int = 50
+
+// checkPartDepth returns an error wrapping ErrMaxPartDepthExceeded if
+// the depth of a multipart part exceeds the parser's maximum.
+func (se *stagedEmail) checkPartDepth(depth int) error {
+	if limit := se.parser.maxPartDepth; limit > 0 && depth > limit {
+		return fmt.Errorf("%w: depth %d exceeds maximum of %d", ErrMaxPartDepthExceeded, depth, limit)
+	}
+	return nil
+}
+
+// parsePart parses the parts of a multipart message at the given
+// nesting depth, starting at 1, and may be called recursively. The
+// bodies of the parts of a multipart/mixed are concatenated, while only
+// the last body of each type in a multipart/alternative is kept.
+func (se *stagedEmail) parsePart(msg io.Reader, parentCI *email.ContentInfo, boundary string, depth int) error {
+	if err := se.checkPartDepth(depth); err != nil {
+		return err
+	}
+	prevDepth := se.depth
+	se.depth = depth
+	defer func() { se.depth = prevDepth }()
+
+	if parentCI.IgnoredEncoding != "" {
+		se.warn(fmt.Errorf("ignored illegal Content-Transfer-Encoding %q on %s", parentCI.IgnoredEncoding, parentCI.Type))
+	}
+
+	msg, boundary = se.sniffBoundary(msg, parentCI, boundary)
 	multipartReader := multipart.NewReader(msg, boundary)
 	if multipartReader == nil {
 		return nil
 	}
 
+	// only the last body of each type in an alternative is kept
+	alternative := parentCI.Type == "multipart/alternative"
+	start := se.markBodies()
+	before := start
+
 	for {
+		if alternative {
+			se.keepLastAlternative(start, before)
+			before = se.markBodies()
+		}
+
+		// check for cancellation between parts
+		if err := se.ctx.Err(); err != nil {
+			return err
+		}
 		part, err := multipartReader.NextPart()
 		if err == io.EOF {
 			break
@@ -57,20 +367,63 @@ func (se *stagedEmail) parsePart(msg io.Reader, parentCI *email.ContentInfo, bou
 		if err != nil {
 			return fmt.Errorf("cannot read part: %w", err)
 		}
+		se.partIndex = se.partCount
+		se.partCount++
 
 		// extract content information
-		contentInfo, err := email.ExtractContentInfo(part.Header, se.contentInfo)
+		contentInfo, err := email.ExtractContentInfo(part.Header, parentCI)
 		if err != nil {
 			return fmt.Errorf("content extraction error: %w", err)
 		}
 
+		// parts inherit the Content-Language of their enclosing part
+		if len(contentInfo.Languages) == 0 {
+			contentInfo.Languages = parentCI.Languages
+		}
+
+		// the parts of a digest default to message/rfc822 (RFC 2046
+		// section 5.1.5)
+		if parentCI.Type == "multipart/digest" && part.Header.Get("Content-Type") == "" {
+			contentInfo.Type = "message/rfc822"
+			contentInfo.TypeParams = map[string]string{}
+		}
+
+		// consult the part inspector, if any, before reading the part
+		if se.parser.partInspector != nil {
+			skip, err := se.parser.partInspector(contentInfo)
+			if err != nil {
+				return fmt.Errorf("part inspector: %w", err)
+			}
+			if skip {
+				continue
+			}
+		}
+
 		// skip part if the content type is in parser.skipContentTypes
 		if se.parser.inSkipContentTypes(contentInfo.Type) {
 			continue
 		}
 
+		se.applyDefaultDisposition(contentInfo)
+
+		// process part with a custom content type handler
+		if handler := se.parser.contentTypeHandler(contentInfo.Type); handler != nil && !strings.HasPrefix(contentInfo.Type, "multipart/") {
+			err = handler(part, contentInfo)
+			if err != nil {
+				err = fmt.Errorf("content type handler for %s: %w", contentInfo.Type, err)
+				if se.isolatePartError(err, contentInfo) {
+					continue
+				}
+				return err
+			}
+			continue
+		}
+
 		// commence extraction of data with attached file
 		if contentInfo.Disposition == "attachment" {
+			if se.parser.processType == textOnly {
+				continue
+			}
 			err = se.parseFile(
 				part,
 				contentInfo,
@@ -81,48 +434,159 @@ func (se *stagedEmail) parsePart(msg io.Reader, parentCI *email.ContentInfo, bou
 			continue
 		}
 
+		// treat subsequent text parts of a type already seen as files if
+		// only the first text part of each type is to be used as body
+		if se.parser.firstTextIsBody && se.bodyTypesSeen[contentInfo.Type] {
+			if se.parser.processType != wholeEmail {
+				continue
+			}
+			if contentInfo.Disposition == "" {
+				contentInfo.Disposition = "attachment"
+			}
+			err = se.parseFile(part, contentInfo)
+			if err != nil {
+				return fmt.Errorf("cannot parse text part as file: %w", err)
+			}
+			continue
+		}
+		switch contentInfo.Type {
+		case "text/plain", "text/enriched", "text/html":
+			se.bodyTypesSeen[contentInfo.Type] = true
+		}
+
 		// process text plain content
 		if contentInfo.Type == "text/plain" {
 			partTextBody, err := se.parseText(part, contentInfo)
 			if err != nil {
-				return fmt.Errorf("cannot parse plain text: %w", err)
+				err = fmt.Errorf("cannot parse plain text: %w", err)
+				if se.isolatePartError(err, contentInfo) {
+					continue
+				}
+				return err
+			}
+			if se.dropEmptyText(contentInfo, partTextBody) {
+				continue
 			}
 			if len(se.email.Text) > 0 { // add separator
 				se.email.Text += "\n\n"
 			}
 			se.email.Text += partTextBody
+			se.addOrderedPart(email.OrderedPartText, partTextBody, nil)
+			se.addLanguageBody(contentInfo.Languages, email.OrderedPartText, partTextBody)
+			se.detectInlinePGP(partTextBody)
 			continue
 		}
 
 		// process text enriched content
 		if contentInfo.Type == "text/enriched" {
+			if se.parser.processType == textOnly {
+				continue
+			}
 			partEnrichedText, err := se.parseText(part, contentInfo)
 			if err != nil {
-				return fmt.Errorf("cannot parse enriched text: %w", err)
+				err = fmt.Errorf("cannot parse enriched text: %w", err)
+				if se.isolatePartError(err, contentInfo) {
+					continue
+				}
+				return err
+			}
+			if se.dropEmptyText(contentInfo, partEnrichedText) {
+				continue
 			}
 			se.email.EnrichedText += partEnrichedText
+			se.addOrderedPart(email.OrderedPartEnriched, partEnrichedText, nil)
 			continue
 		}
 
 		// process html content
 		if contentInfo.Type == "text/html" {
+			if se.parser.processType == textOnly {
+				continue
+			}
 			partHtmlBody, err := se.parseText(part, contentInfo)
 			if err != nil {
-				return fmt.Errorf("cannot parse html text: %w", err)
+				err = fmt.Errorf("cannot parse html text: %w", err)
+				if se.isolatePartError(err, contentInfo) {
+					continue
+				}
+				return err
+			}
+			if se.dropEmptyText(contentInfo, partHtmlBody) {
+				continue
 			}
 			se.email.HTML += partHtmlBody
+			se.addOrderedPart(email.OrderedPartHTML, partHtmlBody, nil)
+			se.addLanguageBody(contentInfo.Languages, email.OrderedPartHTML, partHtmlBody)
 			continue
 		}
 
 		// recursive call to parsePart
 		if strings.HasPrefix(contentInfo.Type, "multipart") {
-			err := se.parsePart(part, contentInfo, contentInfo.TypeParams["boundary"])
+			err := se.parsePart(part, contentInfo, contentInfo.TypeParams["boundary"], depth+1)
+			if errors.Is(err, ErrMaxPartDepthExceeded) {
+				return err
+			}
 			if err != nil {
 				return fmt.Errorf("cannot parse nested part: %w", err)
 			}
 			continue
 		}
 
+		// process the messages of a digest as nested emails, retaining
+		// those nested too deeply as attached files
+		if parentCI.Type == "multipart/digest" && contentInfo.Type == "message/rfc822" {
+			if se.messageDepthExceeded() {
+				if se.parser.processType != wholeEmail {
+					continue
+				}
+				if contentInfo.Disposition == "" {
+					contentInfo.Disposition = "attachment"
+				}
+				err = se.parseFile(part, contentInfo)
+				if err != nil {
+					return fmt.Errorf("cannot parse digest message as file: %w", err)
+				}
+				continue
+			}
+			err = se.parseSubMessage(part)
+			if errors.Is(err, ErrMaxPartDepthExceeded) {
+				return err
+			}
+			if err != nil {
+				err = fmt.Errorf("cannot parse digest message: %w", err)
+				if se.isolatePartError(err, contentInfo) {
+					continue
+				}
+				return err
+			}
+			continue
+		}
+
+		// process the machine-readable part of a feedback report
+		if contentInfo.Type == "message/feedback-report" {
+			err = se.parseFeedbackReport(part, contentInfo)
+			if err != nil {
+				err = fmt.Errorf("cannot parse feedback report: %w", err)
+				if se.isolatePartError(err, contentInfo) {
+					continue
+				}
+				return err
+			}
+			continue
+		}
+
+		// process references to externally stored content
+		if contentInfo.Type == "message/external-body" {
+			if se.parser.processType != wholeEmail {
+				continue
+			}
+			err = se.parseExternalBody(part, contentInfo)
+			if err != nil {
+				return fmt.Errorf("cannot parse external body: %w", err)
+			}
+			continue
+		}
+
 		// process inline file
 		if contentInfo.IsInlineFile(contentInfo) {
 			if se.parser.processType != wholeEmail {
@@ -147,21 +611,40 @@ func (se *stagedEmail) parsePart(msg io.Reader, parentCI *email.ContentInfo, bou
 			continue
 		}
 
-		// types to ignore
-		// Todo/fixme
-		// This section needs to be expanded or, alternatively and more
-		// sensibly, expanded and moved to contentInfo
+		// process calendar invitations, commonly sent as an alternative
+		// to the text and html bodies
+		if contentInfo.Type == "text/calendar" {
+			if se.parser.processType != wholeEmail {
+				continue
+			}
+			err := se.parseFile(part, contentInfo)
+			if err != nil {
+				return fmt.Errorf("cannot parse calendar: %w", err)
+			}
+			continue
+		}
 
-		// unhandled types fixme
-		switch contentInfo.Type {
-		case "text/calendar":
-			fmt.Println("skipping text/calendar content-type")
+		// record unknown parts if skipping them
+		if se.parser.skipUnknownContentTypes {
+			err := se.parseUnhandledPart(part, contentInfo)
+			if err != nil {
+				return fmt.Errorf("cannot read unhandled part: %w", err)
+			}
 			continue
 		}
 
-		// fallthrough error
-		return &UnknownContentTypeError{contentType: contentInfo.Type}
+		// fallthrough error, skipping the part if lenient
+		err = &UnknownContentTypeError{contentType: contentInfo.Type}
+		if se.parser.lenient {
+			se.recovered(err, contentInfo)
+			se.email.DecodeStats.UnknownSkipped++
+			continue
+		}
+		return err
 	}
 
+	if alternative {
+		se.keepLastAlternative(start, before)
+	}
 	return nil
 }
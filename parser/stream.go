@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// "stream" provides a streaming parse of an email, reporting the
+// headers and each leaf part to a callback as they are read rather
+// than building an email.Email, for processing very large messages
+// without holding their content in memory.
+
+// PartEvent kinds
+const (
+	PartEventHeaders  = "headers"
+	PartEventText     = "text"
+	PartEventEnriched = "enriched"
+	PartEventHTML     = "html"
+	PartEventFile     = "file"
+)
+
+// ErrStopStream may be returned by a ParseStream or ParseLeavesFunc
+// callback to stop parsing the remainder of the email without error.
+var ErrStopStream = errors.New("stop stream")
+
+// PartEvent is an event reported by ParseStream. Kind is one of the
+// PartEvent* kinds. Headers is set for the single headers event, which
+// precedes the events for the parts of the email. Path, ContentInfo,
+// Name and Reader are set for the text, enriched, html and file events
+// of the leaf parts of the email, as for email.Leaf.
+type PartEvent struct {
+	Kind        string
+	Headers     *email.Headers
+	Path        []int
+	ContentInfo *email.ContentInfo
+	// Name is the file name of a file part, if any
+	Name string
+	// Reader provides the content of the part, decoded from its
+	// transfer-encoding and, where a charset is given, to UTF-8. It is
+	// only valid for the duration of the callback, and need not be
+	// read, in which case the content is skipped.
+	Reader io.Reader
+}
+
+// ParseStream parses an email, calling fn with the parsed headers and
+// then with each leaf (non-multipart) part in document order as it is
+// read. Part content is provided as a reader rather than being
+// buffered, so that very large emails can be processed with little
+// memory, and parts can be skipped by not reading them. Text parts with
+// an attachment disposition are reported as files.
+//
+// Parsing stops with the error returned by fn, if any, unless it is
+// ErrStopStream in which case ParseStream returns nil. The parser's
+// maximum message size, line length and part depth, skipped content
+// types and processing type are respected, such that WithHeadersOnly
+// reports only the headers event.
+func (p *Parser) ParseStream(r io.Reader, fn func(PartEvent) error) error {
+	err := p.parseStream(p.limitReader(r), fn)
+	if errors.Is(err, ErrStopStream) {
+		return nil
+	}
+	return err
+}
+
+// parseStream parses the email from r for ParseStream.
+func (p *Parser) parseStream(r io.Reader, fn func(PartEvent) error) error {
+	var err error
+	se := newStagedEmail(p)
+	r, err = se.captureRawHeaders(p.limitHeaders(r))
+	if err != nil {
+		return err
+	}
+	se.msg, err = mail.ReadMessage(r)
+	if err != nil {
+		return fmt.Errorf("cannot read message: %w", err)
+	}
+	se.contentInfo, err = email.ExtractContentInfo(se.msg.Header, nil)
+	if err != nil {
+		return fmt.Errorf("cannot extract content: %w", err)
+	}
+	err = se.parseHeaders()
+	if err != nil {
+		return fmt.Errorf("cannot parse headers: %w", err)
+	}
+	err = fn(PartEvent{
+		Kind:        PartEventHeaders,
+		Headers:     &se.email.Headers,
+		ContentInfo: se.contentInfo,
+	})
+	if err != nil || p.processType == headersOnly {
+		return err
+	}
+	return se.streamParts(se.msg.Body, se.contentInfo, []int{}, fn)
+}
+
+// streamParts reports the part with content r at path to fn,
+// recursively parsing the sub-parts of multipart parts.
+func (se *stagedEmail) streamParts(r io.Reader, ci *email.ContentInfo, path []int, fn func(PartEvent) error) error {
+	if !strings.HasPrefix(ci.Type, "multipart/") {
+		kind := streamKind(ci)
+		switch {
+		case kind == PartEventFile && se.parser.processType != wholeEmail:
+			return nil
+		case kind != PartEventText && se.parser.processType == textOnly:
+			return nil
+		}
+		return fn(PartEvent{
+			Kind:        kind,
+			Path:        path,
+			ContentInfo: ci,
+			Name:        structureFilename(ci),
+			Reader:      se.decodeContent(r, ci),
+		})
+	}
+
+	if err := se.checkPartDepth(len(path) + 1); err != nil {
+		return err
+	}
+	r, boundary := se.sniffBoundary(r, ci, ci.TypeParams["boundary"])
+	multipartReader := multipart.NewReader(r, boundary)
+	for i := 0; ; i++ {
+		// use raw parts to avoid decoding quoted-printable twice
+		part, err := multipartReader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read part: %w", err)
+		}
+		partCI, err := email.ExtractContentInfo(part.Header, ci)
+		if err != nil {
+			return fmt.Errorf("content extraction error: %w", err)
+		}
+		if se.parser.inSkipContentTypes(partCI.Type) {
+			continue
+		}
+		err = se.streamParts(part, partCI, append(slices.Clone(path), i), fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamKind returns the PartEvent kind of a leaf part.
+func streamKind(ci *email.ContentInfo) string {
+	if ci.Disposition == "attachment" {
+		return PartEventFile
+	}
+	switch ci.Type {
+	case "text/plain":
+		return PartEventText
+	case "text/enriched":
+		return PartEventEnriched
+	case "text/html":
+		return PartEventHTML
+	}
+	return PartEventFile
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseStream(t *testing.T) {
+
+	raw, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := NewParser().Parse(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []string{}
+	text, files := "", 0
+	err = NewParser().ParseStream(strings.NewReader(string(raw)), func(ev PartEvent) error {
+		events = append(events, fmt.Sprintf("%s %v %s", ev.Kind, ev.Path, ev.Name))
+		switch ev.Kind {
+		case PartEventHeaders:
+			if got, want := ev.Headers.Subject, want.Headers.Subject; got != want {
+				t.Errorf("got subject %q want %q", got, want)
+			}
+		case PartEventText:
+			b, err := io.ReadAll(ev.Reader)
+			if err != nil {
+				return err
+			}
+			text = strings.TrimSpace(strings.ReplaceAll(string(b), "\r\n", "\n"))
+		case PartEventFile:
+			// skip the content of all but the first file
+			if files++; files > 1 {
+				return nil
+			}
+			b, err := io.ReadAll(ev.Reader)
+			if err != nil {
+				return err
+			}
+			if got, want := len(b), len(want.Files[0].Data); got != want {
+				t.Errorf("got %d bytes want %d", got, want)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := text, want.Text; got != want {
+		t.Errorf("got text %q want %q", got, want)
+	}
+	if got, want := files, len(want.Files); got != want {
+		t.Errorf("got %d files want %d", got, want)
+	}
+	if got, want := events[0], "headers [] "; got != want {
+		t.Errorf("got first event %q want %q", got, want)
+	}
+	if got, want := len(events), 1+2+len(want.Files); got != want {
+		t.Errorf("got %d events want %d: %v", got, want, events)
+	}
+}
+
+func TestParseStreamStop(t *testing.T) {
+
+	errAbort := errors.New("abort")
+	tests := []struct {
+		opts   []Opt
+		stopAt string
+		stop   error
+		kinds  []string
+		err    error
+	}{
+		{
+			opts:  []Opt{WithHeadersOnly()},
+			kinds: []string{PartEventHeaders},
+		},
+		{
+			opts:  []Opt{WithTextOnly()},
+			kinds: []string{PartEventHeaders, PartEventText},
+		},
+		{
+			stopAt: PartEventText,
+			stop:   ErrStopStream,
+			kinds:  []string{PartEventHeaders, PartEventText},
+		},
+		{
+			stopAt: PartEventHeaders,
+			stop:   errAbort,
+			kinds:  []string{PartEventHeaders},
+			err:    errAbort,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			f, err := os.Open("testdata/cats.eml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			kinds := []string{}
+			err = NewParser(tt.opts...).ParseStream(f, func(ev PartEvent) error {
+				kinds = append(kinds, ev.Kind)
+				if ev.Kind == tt.stopAt {
+					return tt.stop
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v want %v", err, tt.err)
+			}
+			if diff := cmp.Diff(tt.kinds, kinds); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
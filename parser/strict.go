@@ -0,0 +1,325 @@
+package parser
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+// "strict" provides validating address funcs used by WithStrictAddresses
+// which reject any address that is not strictly RFC 5322 compliant,
+// such as those using obsolete syntax or lacking angle brackets.
+
+// AddressError reports an address which failed strict validation,
+// noting the offending token and its byte position in the (decoded)
+// address header.
+type AddressError struct {
+	Token    string
+	Position int
+	Reason   string
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("invalid address token %q at position %d: %s", e.Token, e.Position, e.Reason)
+}
+
+// addressToken is an address from an address list and its position
+type addressToken struct {
+	s   string
+	pos int
+}
+
+// isAtext reports if the rune is valid in an RFC 5322 atom, allowing
+// the UTF-8 characters permitted by RFC 6532.
+func isAtext(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case r > 127:
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+// isWSP reports if the byte is whitespace
+func isWSP(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// skipQuoted returns the index after the quoted string or comment
+// starting at s[i], which should be the opening '"' or '('. An error is
+// returned if the quoted string or comment is unterminated.
+func skipQuoted(s string, i, offset int) (int, error) {
+	start, depth := i, 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			if s[start] == '"' && i > start {
+				return i + 1, nil
+			}
+		case '(':
+			if s[start] == '(' {
+				depth++
+			}
+		case ')':
+			if s[start] == '(' {
+				depth--
+				if depth == 0 {
+					return i + 1, nil
+				}
+			}
+		}
+	}
+	return i, &AddressError{Token: s[start:], Position: offset + start, Reason: "unterminated quoted string or comment"}
+}
+
+// splitAddressList splits an address list on top-level commas,
+// respecting quoted strings, comments and angle brackets. The members of
+// RFC 5322 groups, such as "Team: a@example.com, b@example.com;", are
+// returned as individual addresses after their group name is validated.
+func splitAddressList(s string) ([]addressToken, error) {
+	tokens := []addressToken{}
+	start, inAngle, inGroup := 0, false, false
+	add := func(end int) {
+		if strings.TrimSpace(s[start:end]) != "" {
+			tokens = append(tokens, addressToken{s: s[start:end], pos: start})
+		}
+	}
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '"', '(':
+			next, err := skipQuoted(s, i, 0)
+			if err != nil {
+				return nil, err
+			}
+			i = next
+			continue
+		case '<':
+			inAngle = true
+		case '>':
+			inAngle = false
+		case ':':
+			if !inAngle && !inGroup {
+				if err := validatePhrase(s[start:i], start); err != nil {
+					return nil, err
+				}
+				inGroup = true
+				start = i + 1
+			}
+		case ';':
+			if !inAngle && inGroup {
+				add(i)
+				inGroup = false
+				start = i + 1
+			}
+		case ',':
+			if !inAngle {
+				add(i)
+				start = i + 1
+			}
+		}
+		i++
+	}
+	if inGroup {
+		return nil, &AddressError{Token: s, Position: 0, Reason: "unterminated group"}
+	}
+	add(len(s))
+	return tokens, nil
+}
+
+// validatePhrase validates a display name phrase of atoms, quoted
+// strings and comments. Unquoted full stops, permitted only by the
+// obsolete phrase syntax, are rejected.
+func validatePhrase(s string, offset int) error {
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '"' || c == '(':
+			next, err := skipQuoted(s, i, offset)
+			if err != nil {
+				return err
+			}
+			i = next
+		case isWSP(c):
+			i++
+		case c == '.':
+			return &AddressError{Token: ".", Position: offset + i, Reason: "unquoted full stop in display name (obsolete syntax)"}
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				return &AddressError{Token: s[i : i+1], Position: offset + i, Reason: "invalid UTF-8 in display name"}
+			}
+			if !isAtext(r) {
+				return &AddressError{Token: string(r), Position: offset + i, Reason: "invalid character in display name"}
+			}
+			i += size
+		}
+	}
+	return nil
+}
+
+// trimCFWS trims surrounding whitespace and comments, returning the
+// trimmed string and the offset of its start.
+func trimCFWS(s string, offset int) (string, int, error) {
+	for {
+		t := strings.TrimLeft(s, " \t\r\n")
+		offset += len(s) - len(t)
+		s = strings.TrimRight(t, " \t\r\n")
+		switch {
+		case strings.HasPrefix(s, "("):
+			next, err := skipQuoted(s, 0, offset)
+			if err != nil {
+				return s, offset, err
+			}
+			s, offset = s[next:], offset+next
+		case strings.HasSuffix(s, ")"):
+			i := strings.LastIndex(s, "(")
+			if i < 0 {
+				return s, offset, &AddressError{Token: ")", Position: offset + len(s) - 1, Reason: "unbalanced comment"}
+			}
+			s = s[:i]
+		default:
+			return s, offset, nil
+		}
+	}
+}
+
+// validateDotAtom validates an RFC 5322 dot-atom such as a local part
+// or domain.
+func validateDotAtom(s string, offset int, part string) error {
+	if s == "" {
+		return &AddressError{Token: s, Position: offset, Reason: "empty " + part}
+	}
+	for _, atom := range strings.Split(s, ".") {
+		if atom == "" {
+			return &AddressError{Token: s, Position: offset, Reason: "empty atom in " + part}
+		}
+		for _, r := range atom {
+			if !isAtext(r) {
+				return &AddressError{Token: string(r), Position: offset + strings.IndexRune(s, r), Reason: "invalid character in " + part}
+			}
+		}
+	}
+	return nil
+}
+
+// validateAddrSpec validates an RFC 5322 addr-spec "local@domain".
+func validateAddrSpec(s string, offset int) error {
+	if strings.HasPrefix(s, "@") {
+		return &AddressError{Token: s, Position: offset, Reason: "source route (obsolete syntax)"}
+	}
+	at := strings.LastIndex(s, "@")
+	if at < 0 {
+		return &AddressError{Token: s, Position: offset, Reason: "missing @ in address"}
+	}
+	local, domain := s[:at], s[at+1:]
+	if strings.HasPrefix(local, `"`) {
+		end, err := skipQuoted(local, 0, offset)
+		if err != nil {
+			return err
+		}
+		if end != len(local) {
+			return &AddressError{Token: local, Position: offset, Reason: "invalid quoted local part (obsolete syntax)"}
+		}
+	} else if err := validateDotAtom(local, offset, "local part"); err != nil {
+		return err
+	}
+	if strings.HasPrefix(domain, "[") {
+		if !strings.HasSuffix(domain, "]") || strings.ContainsAny(domain[1:len(domain)-1], "[]\\ ") {
+			return &AddressError{Token: domain, Position: offset + at + 1, Reason: "invalid domain literal"}
+		}
+		return nil
+	}
+	return validateDotAtom(domain, offset+at+1, "domain")
+}
+
+// validateStrictAddress validates a single address, either a bare
+// addr-spec or a name-addr of an optional display name followed by an
+// addr-spec in angle brackets.
+func validateStrictAddress(a addressToken) error {
+	s, offset, err := trimCFWS(a.s, a.pos)
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		return &AddressError{Token: a.s, Position: a.pos, Reason: "empty address"}
+	}
+
+	// find a top-level angle bracket
+	angle := -1
+	for i := 0; i < len(s) && angle < 0; {
+		switch s[i] {
+		case '"', '(':
+			next, err := skipQuoted(s, i, offset)
+			if err != nil {
+				return err
+			}
+			i = next
+			continue
+		case '<':
+			angle = i
+		}
+		i++
+	}
+
+	if angle < 0 {
+		if i := strings.IndexAny(s, " \t\r\n"); i >= 0 && !strings.HasPrefix(s, `"`) {
+			return &AddressError{Token: s, Position: offset, Reason: "missing angle brackets around address"}
+		}
+		return validateAddrSpec(s, offset)
+	}
+
+	if err := validatePhrase(s[:angle], offset); err != nil {
+		return err
+	}
+	end := strings.IndexByte(s[angle:], '>')
+	if end < 0 {
+		return &AddressError{Token: s[angle:], Position: offset + angle, Reason: "missing closing angle bracket"}
+	}
+	end += angle
+	if rest, _, err := trimCFWS(s[end+1:], offset+end+1); err != nil || rest != "" {
+		return &AddressError{Token: s[end+1:], Position: offset + end + 1, Reason: "unexpected text after address"}
+	}
+	return validateAddrSpec(s[angle+1:end], offset+angle+1)
+}
+
+// strictParseAddressList strictly validates a list of addresses before
+// parsing them with net/mail.ParseAddressList.
+func strictParseAddressList(list string) ([]*mail.Address, error) {
+	tokens, err := splitAddressList(list)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tokens {
+		if err := validateStrictAddress(t); err != nil {
+			return nil, err
+		}
+	}
+	addresses, err := mail.ParseAddressList(list)
+	if err != nil {
+		return nil, &AddressError{Token: list, Position: 0, Reason: err.Error()}
+	}
+	return addresses, nil
+}
+
+// strictParseAddress strictly validates a single address before
+// parsing it with net/mail.ParseAddress.
+func strictParseAddress(s string) (*mail.Address, error) {
+	tokens, err := splitAddressList(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) != 1 {
+		return nil, &AddressError{Token: s, Position: 0, Reason: "expected a single address"}
+	}
+	if err := validateStrictAddress(tokens[0]); err != nil {
+		return nil, err
+	}
+	address, err := mail.ParseAddress(s)
+	if err != nil {
+		return nil, &AddressError{Token: s, Position: 0, Reason: err.Error()}
+	}
+	return address, nil
+}
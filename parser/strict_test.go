@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStrictParseAddressList(t *testing.T) {
+	tests := []struct {
+		list     string
+		count    int
+		token    string
+		position int
+	}{
+		{list: "alice@example.com", count: 1},
+		{list: "Alice Sender <alice@example.com>, Bob <bob@example.net>", count: 2},
+		{list: `"Sender, Alice" <alice@example.com>`, count: 1},
+		{list: `"Hubert Schölnast" <localpart@domain.tld>`, count: 1},
+		{list: `Odbierająca <karolina@example.net>`, count: 1},
+		{list: "Team: alice@example.com, bob@example.com;, carol@example.com", count: 3},
+		{list: "alice@[192.168.0.1]", count: 1},
+		{list: "Alice Sender alice@example.com", token: "Alice Sender alice@example.com", position: 0},
+		{list: "bob@example.net, John Q. Public <john@example.com>", token: ".", position: 23},
+		{list: "<@route:john@example.com>", token: "@route:john@example.com", position: 1},
+		{list: "bob@example.net, alice..sender@example.com", token: "alice..sender", position: 17},
+		{list: "alice@example.com,bob", token: "bob", position: 18},
+		{list: `"unterminated <alice@example.com>`, token: `"unterminated <alice@example.com>`, position: 0},
+		{list: "Ali\xffce <alice@example.com>", token: "\xff", position: 3},
+		{list: "Zoë [x] <zoe@example.com>", token: "[", position: 5},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			addresses, err := strictParseAddressList(tt.list)
+			if tt.token == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := len(addresses), tt.count; got != want {
+					t.Errorf("got %d want %d addresses", got, want)
+				}
+				return
+			}
+			var ae *AddressError
+			if !errors.As(err, &ae) {
+				t.Fatalf("expected an AddressError, got %v", err)
+			}
+			if got, want := ae.Token, tt.token; got != want {
+				t.Errorf("token got %q want %q", got, want)
+			}
+			if got, want := ae.Position, tt.position; got != want {
+				t.Errorf("position got %d want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestStrictParseAddress(t *testing.T) {
+	if _, err := strictParseAddress("Alice <alice@example.com>"); err != nil {
+		t.Error(err)
+	}
+	if _, err := strictParseAddress("alice@example.com, bob@example.com"); err == nil {
+		t.Error("expected error for more than one address")
+	}
+}
+
+func TestOptStrictAddresses(t *testing.T) {
+	msg := `From: John Q. Public <john@example.com>
+To: bob@example.net
+Subject: Obsolete phrase
+
+Body.
+`
+	// default parsing accepts the obsolete phrase
+	if _, err := NewParser().Parse(strings.NewReader(msg)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewParser(WithStrictAddresses()).Parse(strings.NewReader(msg))
+	var ae *AddressError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected an AddressError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "from header") {
+		t.Errorf("error %q should name the header", err)
+	}
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/mail"
+	"path/filepath"
+	"strings"
+
+	"github.com/rorycl/letters/email"
+)
+
+// "structure" provides a fast structural parse of an email, describing
+// its MIME tree without decoding or retaining any content.
+
+// ParseStructure parses the MIME structure of an email into a tree of
+// *email.PartNode, recording the content information and file name of
+// each part. Part bodies are read only to be discarded and are never
+// decoded, making this much cheaper than Parse for determining what a
+// message contains. The parser's maximum message size and line length
+// are respected.
+func (p *Parser) ParseStructure(r io.Reader) (*email.PartNode, error) {
+	msg, err := mail.ReadMessage(p.limitReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read message: %w", err)
+	}
+	ci, err := email.ExtractContentInfo(msg.Header, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot extract content: %w", err)
+	}
+	se := newStagedEmail(p)
+	se.contentInfo = ci
+	return se.parseStructure(msg.Body, ci, 1)
+}
+
+// parseStructure returns the node describing the part with content r
+// at the given nesting depth, recursively parsing the sub-parts of
+// multipart parts.
+func (se *stagedEmail) parseStructure(r io.Reader, ci *email.ContentInfo, depth int) (*email.PartNode, error) {
+	node := &email.PartNode{
+		ContentInfo: ci,
+		Filename:    structureFilename(ci),
+	}
+	if !strings.HasPrefix(ci.Type, "multipart/") {
+		var err error
+		node.Size, err = io.Copy(io.Discard, r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read part: %w", err)
+		}
+		return node, nil
+	}
+
+	if err := se.checkPartDepth(depth); err != nil {
+		return nil, err
+	}
+	r, boundary := se.sniffBoundary(r, ci, ci.TypeParams["boundary"])
+	multipartReader := multipart.NewReader(r, boundary)
+	for {
+		// use raw parts to avoid quoted-printable decoding
+		part, err := multipartReader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read part: %w", err)
+		}
+		partCI, err := email.ExtractContentInfo(part.Header, ci)
+		if err != nil {
+			return nil, fmt.Errorf("content extraction error: %w", err)
+		}
+		child, err := se.parseStructure(part, partCI, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+// structureFilename returns the file name of a part from its
+// Content-Disposition filename or Content-Type name parameters,
+// stripped of any path in the same manner as parseFile.
+func structureFilename(ci *email.ContentInfo) string {
+	name, ok := ci.DispositionParams["filename"]
+	if !ok {
+		name, ok = ci.TypeParams["name"]
+	}
+	if !ok {
+		return ""
+	}
+	return filepath.Base(filepath.Clean(name))
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rorycl/letters/email"
+)
+
+func TestParseStructure(t *testing.T) {
+
+	f, err := os.Open("testdata/cats.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	root, err := NewParser().ParseStructure(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	root.Walk(func(n *email.PartNode, depth int) {
+		s := strings.Repeat("  ", depth) + n.ContentInfo.Type
+		if n.Filename != "" {
+			s += " " + n.ContentInfo.Disposition + " " + n.Filename
+		}
+		if len(n.Children) == 0 && n.Size == 0 {
+			s += " (empty)"
+		}
+		got = append(got, s)
+	})
+	want := []string{
+		"multipart/related",
+		"  multipart/alternative",
+		"    text/plain",
+		"    text/html",
+		"  image/png inline cat2.png",
+		"  image/jpeg inline cat3.jpg",
+		"  image/jpeg inline cat1.jpg",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParseStructureSinglePart(t *testing.T) {
+
+	msg := "Subject: plain\n\nJust text.\n"
+	root, err := NewParser().ParseStructure(strings.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := root.ContentInfo.Type, "text/plain"; got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+	if got, want := root.Size, int64(len("Just text.\n")); got != want {
+		t.Errorf("got size %d want %d", got, want)
+	}
+	if got, want := len(root.Children), 0; got != want {
+		t.Errorf("got %d children want %d", got, want)
+	}
+}
+
+func benchmarkCats(b *testing.B) []byte {
+	b.Helper()
+	c, err := os.ReadFile("testdata/cats.eml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+func BenchmarkParse(b *testing.B) {
+	c := benchmarkCats(b)
+	p := NewParser()
+	for b.Loop() {
+		if _, err := p.Parse(bytes.NewReader(c)); err != nil {
+			b.Fatal(fmt.Errorf("parse error: %w", err))
+		}
+	}
+}
+
+func BenchmarkParseWithoutAttachments(b *testing.B) {
+	c := benchmarkCats(b)
+	p := NewParser(WithoutAttachments())
+	for b.Loop() {
+		if _, err := p.Parse(bytes.NewReader(c)); err != nil {
+			b.Fatal(fmt.Errorf("parse error: %w", err))
+		}
+	}
+}
+
+func BenchmarkParseTextOnly(b *testing.B) {
+	c := benchmarkCats(b)
+	p := NewParser(WithTextOnly())
+	for b.Loop() {
+		if _, err := p.Parse(bytes.NewReader(c)); err != nil {
+			b.Fatal(fmt.Errorf("parse error: %w", err))
+		}
+	}
+}
+
+func BenchmarkParseStructure(b *testing.B) {
+	c := benchmarkCats(b)
+	p := NewParser()
+	for b.Loop() {
+		if _, err := p.ParseStructure(bytes.NewReader(c)); err != nil {
+			b.Fatal(fmt.Errorf("parse structure error: %w", err))
+		}
+	}
+}
+
+func TestParseStructureMaxMessageSize(t *testing.T) {
+
+	msg := "Subject: attachment\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/plain\r\n\r\nSee attached.\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\n\r\n" +
+		strings.Repeat("0123456789", 100) + "\r\n--b--\r\n"
+	size := int64(len(msg))
+
+	tests := []struct {
+		max   int64
+		isErr bool
+	}{
+		{0, false},
+		{size, false},
+		{size - 1, true},
+		{10, true}, // within the headers
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			_, err := NewParser(WithMaxMessageSize(tt.max)).ParseStructure(strings.NewReader(msg))
+			if got, want := errors.Is(err, ErrMessageTooLarge), tt.isErr; got != want {
+				t.Errorf("got message too large error %t want %t (%v)", got, want, err)
+			}
+		})
+	}
+}